@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/chekist32/goipay/internal/app"
+	"github.com/chekist32/goipay/internal/cli"
+)
+
+const api_key_env string = "GOIPAY_API_KEY"
+
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintf(os.Stderr, "usage: %v [flags] <command>\n\n", os.Args[0])
+	fmt.Fprintln(os.Stderr, "Talks to the running server unless -config is set, then to its database directly.")
+	fmt.Fprintln(os.Stderr, "\nFlags:")
+	fs.PrintDefaults()
+	fmt.Fprintln(os.Stderr)
+	cli.Usage(os.Stderr)
+}
+
+func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to the config file of the server, its database is used directly if set")
+	server := fs.String("server", "localhost:3000", "host:port of the gRPC server")
+	apiKey := fs.String("api-key", os.Getenv(api_key_env), "Admin api key, "+api_key_env+" by default")
+	tlsEnabled := fs.Bool("tls", false, "Connect to the server over TLS")
+	ca := fs.String("ca", "", "PEM file of the CA the server certificate is verified with, the system roots by default")
+	cert := fs.String("cert", "", "PEM file of the client certificate for mutual TLS")
+	key := fs.String("key", "", "PEM file of the client key for mutual TLS")
+	fs.Usage = func() { usage(fs) }
+	fs.Parse(os.Args[1:])
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var admin cli.Admin
+	var err error
+	if *configPath != "" {
+		admin, err = app.NewDbAdmin(ctx, *configPath)
+	} else {
+		admin, err = cli.NewGrpcAdmin(&cli.GrpcAdminConfig{Server: *server, ApiKey: *apiKey, TLS: *tlsEnabled, Ca: *ca, Cert: *cert, Key: *key})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = cli.Run(ctx, admin, fs.Args(), os.Stdout)
+	admin.Close()
+	if err != nil {
+		if errors.Is(err, cli.InvalidCommandErr) {
+			usage(fs)
+		}
+		log.Fatal(err)
+	}
+}
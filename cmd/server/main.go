@@ -6,17 +6,65 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/chekist32/goipay/internal/app"
 )
 
+const (
+	rotate_master_key_cmd string = "rotate-master-key"
+	migrate_cmd           string = "migrate"
+)
+
+func rotateMasterKey(args []string) {
+	fs := flag.NewFlagSet(rotate_master_key_cmd, flag.ExitOnError)
+	configPath := fs.String("config", "config.yml", "Path to the config file")
+	fs.Parse(args)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := app.RotateMasterKey(ctx, *configPath); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// migrate runs "migrate up|down|status [-config config.yml]".
+func migrate(args []string) {
+	if len(args) == 0 {
+		log.Fatalf("usage: %v %v up|down|status [-config config.yml]", os.Args[0], migrate_cmd)
+	}
+
+	fs := flag.NewFlagSet(migrate_cmd, flag.ExitOnError)
+	configPath := fs.String("config", "config.yml", "Path to the config file")
+	fs.Parse(args[1:])
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := app.Migrate(ctx, *configPath, args[0]); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case rotate_master_key_cmd:
+			rotateMasterKey(os.Args[2:])
+			return
+		case migrate_cmd:
+			migrate(os.Args[2:])
+			return
+		}
+	}
+
 	configPath := flag.String("config", "config.yml", "Path to the config file")
 	flag.Parse()
 
 	app := app.NewApp(*configPath)
 
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	if err := app.Start(ctx); err != nil {
@@ -0,0 +1,260 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chekist32/goipay/internal/auth"
+	"github.com/chekist32/goipay/internal/cli"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/envelope"
+	handler_v1 "github.com/chekist32/goipay/internal/handler/v1"
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/chekist32/goipay/internal/processor"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// DbAdmin is the cli.Admin talking to the database directly, it's meant for the server being stopped.
+// The invoices can't be rechecked through it as that needs the daemons.
+type DbAdmin struct {
+	log         *zerolog.Logger
+	dbConnPool  *pgxpool.Pool
+	keyring     *envelope.Keyring
+	gracePeriod time.Duration
+
+	// The handlers not touching the payment processor are reused as is
+	users    *handler_v1.UserGrpc
+	invoices *handler_v1.InvoiceGrpc
+	admin    *handler_v1.AdminGrpc
+}
+
+func adminContext(ctx context.Context) context.Context {
+	return auth.NewContext(ctx, &auth.Principal{Admin: true})
+}
+
+func (a *DbAdmin) RegisterUser(ctx context.Context, userId *string) (string, error) {
+	res, err := a.users.RegisterUser(adminContext(ctx), &pb_v1.RegisterUserRequest{UserId: userId})
+	if err != nil {
+		return "", err
+	}
+
+	return res.UserId, nil
+}
+
+func (a *DbAdmin) encryptXmrPrivViewKey(privViewKey string) (string, error) {
+	if a.keyring == nil {
+		return privViewKey, nil
+	}
+
+	return a.keyring.Encrypt(privViewKey)
+}
+
+func (a *DbAdmin) SetXmrKeys(ctx context.Context, userId string, privViewKey string, pubSpendKey string) error {
+	userIdUUID, err := util.StringToPgUUID(userId)
+	if err != nil {
+		return errors.New("invalid userId")
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, a.dbConnPool)
+	if err != nil {
+		a.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return err
+	}
+
+	exists, err := q.UserExistsById(ctx, *userIdUUID)
+	if err != nil {
+		tx.Rollback(ctx)
+		a.log.Err(err).Str("queryName", "UserExistsById").Msg(util.DefaultFailedSqlQueryMsg)
+		return err
+	}
+	if !exists {
+		tx.Rollback(ctx)
+		return errors.New("invalid userId")
+	}
+
+	cryptData, err := q.FindCryptoDataByUserId(ctx, *userIdUUID)
+	if err != nil {
+		tx.Rollback(ctx)
+		a.log.Err(err).Str("queryName", "FindCryptoDataByUserId").Msg(util.DefaultFailedSqlQueryMsg)
+		return err
+	}
+
+	if err := handler_v1.UpdateXmrCryptoData(ctx, a.log, q, a.encryptXmrPrivViewKey, &cryptData, privViewKey, pubSpendKey); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	tx.Commit(ctx)
+
+	return nil
+}
+
+func (a *DbAdmin) ListInvoices(ctx context.Context, req *pb_v1.ListInvoicesRequest) ([]*pb_v1.Invoice, error) {
+	res, err := a.admin.ListInvoices(adminContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Invoices, nil
+}
+
+func (a *DbAdmin) GetInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	res, err := a.invoices.GetInvoices(adminContext(ctx), &pb_v1.GetInvoicesRequest{PaymentIds: []string{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Invoices) == 0 {
+		return nil, errors.New("invoice not found")
+	}
+
+	return res.Invoices[0], nil
+}
+
+func (a *DbAdmin) ExpireInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	invoiceId, err := util.StringToPgUUID(id)
+	if err != nil {
+		return nil, errors.New("invalid id")
+	}
+
+	if _, err := processor.ExpireStoredInvoice(ctx, a.dbConnPool, *invoiceId, a.gracePeriod, a.log); err != nil {
+		return nil, err
+	}
+
+	return a.GetInvoice(ctx, id)
+}
+
+func (a *DbAdmin) RecheckInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	return nil, fmt.Errorf("%w: rechecking needs the daemons, use -server with the running server", cli.UnsupportedCommandErr)
+}
+
+func (a *DbAdmin) GetSyncStates(ctx context.Context) ([]*pb_v1.SyncState, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, a.dbConnPool)
+	if err != nil {
+		a.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	caches, err := q.FindAllCryptoCaches(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		a.log.Err(err).Str("queryName", "FindAllCryptoCaches").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	states := make([]*pb_v1.SyncState, 0, len(caches))
+	for i := 0; i < len(caches); i++ {
+		state, err := util.DbCryptoCacheToPbSyncState(&caches[i])
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func (a *DbAdmin) ResetSyncHeight(ctx context.Context, coin pb_v1.CoinType, height uint64) (*pb_v1.SyncState, error) {
+	dbCoin, err := util.PbCoinToDbCoin(coin)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastSyncedBlockHeight pgtype.Int8
+	if err := lastSyncedBlockHeight.Scan(int64(height)); err != nil {
+		a.log.Err(err).Str("fieldName", "lastSyncedBlockHeight").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+		return nil, err
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, a.dbConnPool)
+	if err != nil {
+		a.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	cache, err := q.UpdateCryptoCacheByCoin(ctx, db.UpdateCryptoCacheByCoinParams{Coin: dbCoin, LastSyncedBlockHeight: lastSyncedBlockHeight})
+	if err != nil {
+		tx.Rollback(ctx)
+		a.log.Err(err).Str("queryName", "UpdateCryptoCacheByCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return util.DbCryptoCacheToPbSyncState(&cache)
+}
+
+func (a *DbAdmin) ReleaseAddresses(ctx context.Context, req *pb_v1.ReleaseAddressesRequest) ([]string, error) {
+	var coin db.NullCoinType
+	if req.Coin != nil {
+		c, err := util.PbCoinToDbCoin(req.GetCoin())
+		if err != nil {
+			return nil, err
+		}
+		coin = db.NullCoinType{CoinType: c, Valid: true}
+	}
+
+	addrs, err := processor.ReleaseStuckAddresses(ctx, a.dbConnPool, coin, pgtype.Text{String: req.GetAddress(), Valid: req.Address != nil}, a.gracePeriod, a.log)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(addrs))
+	for i := 0; i < len(addrs); i++ {
+		res = append(res, addrs[i].Address)
+	}
+
+	return res, nil
+}
+
+func (a *DbAdmin) Close() error {
+	a.dbConnPool.Close()
+	return nil
+}
+
+// NewDbAdmin connects to the database of the config, the logs go to stderr to keep stdout for the output of the commands.
+func NewDbAdmin(ctx context.Context, pathToConfig string) (*DbAdmin, error) {
+	logger := zerolog.New(zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) { w.Out = os.Stderr })).With().Timestamp().Logger()
+	log := &logger
+
+	conf, err := NewAppConfig(pathToConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := appConfigToKeyring(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	invoiceConf, err := appConfigToInvoiceConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	connPool, err := newDbConnPool(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := connPool.Ping(ctx); err != nil {
+		connPool.Close()
+		return nil, err
+	}
+
+	return &DbAdmin{
+		log:         log,
+		dbConnPool:  connPool,
+		keyring:     keyring,
+		gracePeriod: invoiceConf.LatePaymentGracePeriod,
+		users:       handler_v1.NewUserGrpc(connPool, nil, log),
+		invoices:    handler_v1.NewInvoiceGrpc(connPool, nil, log),
+		admin:       handler_v1.NewAdminGrpc(connPool, nil, log),
+	}, nil
+}
@@ -2,54 +2,163 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/chekist32/goipay/internal/dto"
+	"github.com/chekist32/goipay/internal/envelope"
 	handler_v1 "github.com/chekist32/goipay/internal/handler/v1"
+	handler_v2 "github.com/chekist32/goipay/internal/handler/v2"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/migrate"
 	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	pb_v2 "github.com/chekist32/goipay/internal/pb/v2"
 	"github.com/chekist32/goipay/internal/processor"
+	"github.com/chekist32/goipay/internal/tracing"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/chekist32/goipay/internal/webhook"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 	"gopkg.in/yaml.v3"
 )
 
 type AppMode string
 
+const (
+	gateway_grpc_buffer_size int           = 1024 * 1024
+	tracer_shutdown_timeout  time.Duration = 5 * time.Second
+
+	DEFAULT_SHUTDOWN_TIMEOUT time.Duration = 30 * time.Second
+)
+
 const (
 	DEV_APP_MODE  AppMode = "dev"
 	PROD_APP_MODE AppMode = "prod"
 )
 
+var (
+	// MissingMasterKeyErr is returned if the master key is required but neither encryption.masterKey nor encryption.masterKeyFile is set.
+	MissingMasterKeyErr error = errors.New("encryption.masterKey or encryption.masterKeyFile is required")
+)
+
 type AppConfigDaemon struct {
 	Url  string `yaml:"url"`
 	User string `yaml:"user"`
 	Pass string `yaml:"pass"`
 }
 
+type AppConfigEthToken struct {
+	Symbol   string `yaml:"symbol"`
+	Contract string `yaml:"contract"`
+	Decimals uint32 `yaml:"decimals"`
+}
+
 type AppConfig struct {
 	Mode AppMode `yaml:"mode"`
 
 	Server struct {
 		Host string `yaml:"host"`
 		Port string `yaml:"port"`
+
+		// How long the in-flight calls and invoice verification are waited for on shutdown, 30s by default
+		ShutdownTimeout string `yaml:"shutdownTimeout"`
+
+		Auth struct {
+			Enabled string `yaml:"enabled"`
+			// Accepted besides the api keys stored in the database
+			AdminKey string `yaml:"adminKey"`
+		} `yaml:"auth"`
+
+		// TLS is enabled if the cert is set
+		TLS struct {
+			Cert string `yaml:"cert"`
+			Key  string `yaml:"key"`
+			// Client certificates signed by this CA are required if set
+			ClientCa string `yaml:"clientCa"`
+		} `yaml:"tls"`
 	} `yaml:"server"`
 
+	// HTTP/JSON gateway in front of the gRPC server, disabled if the port is empty
+	Gateway struct {
+		Host string `yaml:"host"`
+		Port string `yaml:"port"`
+	} `yaml:"gateway"`
+
+	// Prometheus metrics endpoint, disabled if the port is empty
+	Metrics struct {
+		Host string `yaml:"host"`
+		Port string `yaml:"port"`
+	} `yaml:"metrics"`
+
+	// gRPC health checking is always served, the HTTP probes are disabled if the port is empty
+	Health struct {
+		Host string `yaml:"host"`
+		Port string `yaml:"port"`
+		// Blocks the daemon tip may be ahead of the sync before the InvoiceService is reported as NOT_SERVING
+		MaxSyncLag string `yaml:"maxSyncLag"`
+	} `yaml:"health"`
+
+	// OpenTelemetry tracing, disabled if the OTLP endpoint is empty
+	Tracing struct {
+		// OTLP/gRPC collector, e.g. localhost:4317
+		OtlpEndpoint string `yaml:"otlpEndpoint"`
+		Insecure     string `yaml:"insecure"`
+	} `yaml:"tracing"`
+
 	Database struct {
 		Host string `yaml:"host"`
 		Port string `yaml:"port"`
 		User string `yaml:"user"`
 		Pass string `yaml:"pass"`
 		Name string `yaml:"name"`
+		// Applies the pending migrations at startup
+		AutoMigrate string `yaml:"autoMigrate"`
 	} `yaml:"database"`
 
+	Invoice struct {
+		LatePaymentGracePeriod string `yaml:"latePaymentGracePeriod"`
+	} `yaml:"invoice"`
+
+	// Encrypts the stored XMR private view keys, they're kept in plaintext if no master key is set
+	Encryption struct {
+		// Hex-encoded 32 bytes, takes precedence over the masterKeyFile
+		MasterKey     string `yaml:"masterKey"`
+		MasterKeyFile string `yaml:"masterKeyFile"`
+		// Comma-separated files of the master keys being rotated out
+		PreviousMasterKeyFiles string `yaml:"previousMasterKeyFiles"`
+	} `yaml:"encryption"`
+
 	Coin struct {
 		Xmr struct {
 			Daemon AppConfigDaemon `yaml:"daemon"`
 		} `yaml:"xmr"`
+		Btc struct {
+			Daemon AppConfigDaemon `yaml:"daemon"`
+		} `yaml:"btc"`
+		Ltc struct {
+			Daemon AppConfigDaemon `yaml:"daemon"`
+		} `yaml:"ltc"`
+		Eth struct {
+			Daemon AppConfigDaemon     `yaml:"daemon"`
+			Tokens []AppConfigEthToken `yaml:"tokens"`
+		} `yaml:"eth"`
+		Ton struct {
+			Daemon AppConfigDaemon `yaml:"daemon"`
+		} `yaml:"ton"`
 	} `yaml:"coin"`
 }
 
@@ -68,28 +177,103 @@ func NewAppConfig(path string) (*AppConfig, error) {
 
 	conf.Server.Host = os.ExpandEnv(conf.Server.Host)
 	conf.Server.Port = os.ExpandEnv(conf.Server.Port)
+	conf.Server.ShutdownTimeout = os.ExpandEnv(conf.Server.ShutdownTimeout)
+	conf.Server.Auth.Enabled = os.ExpandEnv(conf.Server.Auth.Enabled)
+	conf.Server.Auth.AdminKey = os.ExpandEnv(conf.Server.Auth.AdminKey)
+	conf.Server.TLS.Cert = os.ExpandEnv(conf.Server.TLS.Cert)
+	conf.Server.TLS.Key = os.ExpandEnv(conf.Server.TLS.Key)
+	conf.Server.TLS.ClientCa = os.ExpandEnv(conf.Server.TLS.ClientCa)
+
+	conf.Gateway.Host = os.ExpandEnv(conf.Gateway.Host)
+	conf.Gateway.Port = os.ExpandEnv(conf.Gateway.Port)
+
+	conf.Metrics.Host = os.ExpandEnv(conf.Metrics.Host)
+	conf.Metrics.Port = os.ExpandEnv(conf.Metrics.Port)
+
+	conf.Health.Host = os.ExpandEnv(conf.Health.Host)
+	conf.Health.Port = os.ExpandEnv(conf.Health.Port)
+	conf.Health.MaxSyncLag = os.ExpandEnv(conf.Health.MaxSyncLag)
+
+	conf.Tracing.OtlpEndpoint = os.ExpandEnv(conf.Tracing.OtlpEndpoint)
+	conf.Tracing.Insecure = os.ExpandEnv(conf.Tracing.Insecure)
 
 	conf.Database.Host = os.ExpandEnv(conf.Database.Host)
 	conf.Database.Port = os.ExpandEnv(conf.Database.Port)
 	conf.Database.User = os.ExpandEnv(conf.Database.User)
 	conf.Database.Pass = os.ExpandEnv(conf.Database.Pass)
 	conf.Database.Name = os.ExpandEnv(conf.Database.Name)
+	conf.Database.AutoMigrate = os.ExpandEnv(conf.Database.AutoMigrate)
+
+	conf.Invoice.LatePaymentGracePeriod = os.ExpandEnv(conf.Invoice.LatePaymentGracePeriod)
+
+	conf.Encryption.MasterKey = os.ExpandEnv(conf.Encryption.MasterKey)
+	conf.Encryption.MasterKeyFile = os.ExpandEnv(conf.Encryption.MasterKeyFile)
+	conf.Encryption.PreviousMasterKeyFiles = os.ExpandEnv(conf.Encryption.PreviousMasterKeyFiles)
 
 	conf.Coin.Xmr.Daemon.Url = os.ExpandEnv(conf.Coin.Xmr.Daemon.Url)
 	conf.Coin.Xmr.Daemon.User = os.ExpandEnv(conf.Coin.Xmr.Daemon.User)
 	conf.Coin.Xmr.Daemon.Pass = os.ExpandEnv(conf.Coin.Xmr.Daemon.Pass)
 
+	conf.Coin.Btc.Daemon.Url = os.ExpandEnv(conf.Coin.Btc.Daemon.Url)
+	conf.Coin.Btc.Daemon.User = os.ExpandEnv(conf.Coin.Btc.Daemon.User)
+	conf.Coin.Btc.Daemon.Pass = os.ExpandEnv(conf.Coin.Btc.Daemon.Pass)
+
+	conf.Coin.Ltc.Daemon.Url = os.ExpandEnv(conf.Coin.Ltc.Daemon.Url)
+	conf.Coin.Ltc.Daemon.User = os.ExpandEnv(conf.Coin.Ltc.Daemon.User)
+	conf.Coin.Ltc.Daemon.Pass = os.ExpandEnv(conf.Coin.Ltc.Daemon.Pass)
+
+	conf.Coin.Eth.Daemon.Url = os.ExpandEnv(conf.Coin.Eth.Daemon.Url)
+	conf.Coin.Eth.Daemon.User = os.ExpandEnv(conf.Coin.Eth.Daemon.User)
+	conf.Coin.Eth.Daemon.Pass = os.ExpandEnv(conf.Coin.Eth.Daemon.Pass)
+
+	conf.Coin.Ton.Daemon.Url = os.ExpandEnv(conf.Coin.Ton.Daemon.Url)
+	conf.Coin.Ton.Daemon.User = os.ExpandEnv(conf.Coin.Ton.Daemon.User)
+	conf.Coin.Ton.Daemon.Pass = os.ExpandEnv(conf.Coin.Ton.Daemon.Pass)
+
 	return &conf, nil
 }
 
 type App struct {
 	ctxCancel context.CancelFunc
+	// The background goroutines started by Start, they use the pool
+	routines util.Routines
+
+	config          *AppConfig
+	log             *zerolog.Logger
+	shutdownTimeout time.Duration
+
+	dbConnPool        *pgxpool.Pool
+	tracerProvider    *sdktrace.TracerProvider
+	paymentProcessor  *processor.PaymentProcessor
+	webhookDispatcher *webhook.Dispatcher
+	authInterceptor   *AuthInterceptor
+	healthChecker     *healthChecker
+}
+
+func (a *App) newGrpcServer(opts ...grpc.ServerOption) *grpc.Server {
+	metricsInterceptor := NewMetricsInterceptor()
+	opts = append(opts,
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(metricsInterceptor.Intercepte, NewRequestLoggingInterceptor(a.log).Intercepte, a.authInterceptor.Intercepte),
+		grpc.ChainStreamInterceptor(metricsInterceptor.IntercepteStream, a.authInterceptor.IntercepteStream),
+	)
 
-	config *AppConfig
-	log    *zerolog.Logger
+	g := grpc.NewServer(opts...)
+	pb_v1.RegisterUserServiceServer(g, handler_v1.NewUserGrpc(a.dbConnPool, a.paymentProcessor, a.log))
+	invoiceGrpc := handler_v1.NewInvoiceGrpc(a.dbConnPool, a.paymentProcessor, a.log)
+	pb_v1.RegisterInvoiceServiceServer(g, invoiceGrpc)
+	pb_v2.RegisterInvoiceServiceServer(g, handler_v2.NewInvoiceGrpc(invoiceGrpc))
+	// Every client would be an admin without the auth
+	if a.authInterceptor.enabled {
+		pb_v1.RegisterAdminServiceServer(g, handler_v1.NewAdminGrpc(a.dbConnPool, a.paymentProcessor, a.log))
+	}
+	healthpb.RegisterHealthServer(g, a.healthChecker.server)
 
-	dbConnPool       *pgxpool.Pool
-	paymentProcessor *processor.PaymentProcessor
+	if a.config.Mode == DEV_APP_MODE {
+		reflection.Register(g)
+	}
+
+	return g
 }
 
 func (a *App) Start(ctx context.Context) error {
@@ -99,19 +283,46 @@ func (a *App) Start(ctx context.Context) error {
 	}
 	defer a.dbConnPool.Close()
 
+	if a.tracerProvider != nil {
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), tracer_shutdown_timeout)
+			defer cancel()
+			if err := a.tracerProvider.Shutdown(ctx); err != nil {
+				a.log.Err(err).Msg("failed to flush the spans")
+			}
+		}()
+	}
+
+	routinesCtx, cancelRoutines := context.WithCancel(ctx)
+	defer cancelRoutines()
+	// The metrics and health servers
+	var servers []*http.Server
+	// Used instead of shutdown if the startup fails
+	stopBackground := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+		defer cancel()
+		a.stopBackground(ctx, servers, cancelRoutines)
+	}
+
+	var opts []grpc.ServerOption
+	var reloader *tlsReloader
+	if a.config.Server.TLS.Cert != "" {
+		r, err := newTlsReloader(a.config.Server.TLS.Cert, a.config.Server.TLS.Key, a.config.Server.TLS.ClientCa, a.log)
+		if err != nil {
+			a.log.Err(err).Msg("failed to load TLS certificates")
+			return err
+		}
+		reloader = r
+		a.routines.Go(func() { r.Start(routinesCtx) })
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.TLSConfig("h2"))))
+	}
+
 	lis, err := net.Listen("tcp", a.config.Server.Host+":"+a.config.Server.Port)
 	if err != nil {
 		a.log.Fatal().Msgf("failed to listen on port %v: %v", a.config.Server.Port, err)
 	}
-	g := grpc.NewServer(
-		grpc.UnaryInterceptor(NewRequestLoggingInterceptor(a.log).Intercepte),
-	)
-	pb_v1.RegisterUserServiceServer(g, handler_v1.NewUserGrpc(a.dbConnPool, a.log))
-	pb_v1.RegisterInvoiceServiceServer(g, handler_v1.NewInvoiceGrpc(a.dbConnPool, a.paymentProcessor, a.log))
-
-	if a.config.Mode == DEV_APP_MODE {
-		reflection.Register(g)
-	}
+	g := a.newGrpcServer(opts...)
 
 	ch := make(chan error, 1)
 	go func() {
@@ -122,16 +333,87 @@ func (a *App) Start(ctx context.Context) error {
 		close(ch)
 	}()
 
+	a.routines.Go(func() { a.webhookDispatcher.Start(routinesCtx) })
+	a.routines.Go(func() { a.healthChecker.Start(routinesCtx) })
+
 	a.log.Info().Msgf("Starting server %v\n", lis.Addr())
 
+	var gw *http.Server
+	gwCh := make(chan error, 1)
+	if a.config.Gateway.Port != "" {
+		gwLis := bufconn.Listen(gateway_grpc_buffer_size)
+		gwGrpc := a.newGrpcServer()
+		go gwGrpc.Serve(gwLis)
+		defer gwGrpc.Stop()
+
+		var conn *grpc.ClientConn
+		gw, conn, err = newGatewayServer(ctx, a.config.Gateway.Host+":"+a.config.Gateway.Port, gwLis, a.log)
+		if err != nil {
+			a.log.Err(err).Msg("failed to create gateway")
+			g.Stop()
+			stopBackground()
+			return err
+		}
+		defer conn.Close()
+
+		// The in-process connection is trusted, so the gateway itself has to be served with the server TLS config
+		// and require client certificates when mTLS is on
+		if reloader != nil {
+			gw.TLSConfig = reloader.TLSConfig("h2", "http/1.1")
+		}
+
+		go func() {
+			if err := listenAndServeGateway(gw); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.log.Err(err).Msg("failed to start gateway")
+				gwCh <- err
+			}
+		}()
+
+		a.log.Info().Msgf("Starting gateway %v\n", gw.Addr)
+	}
+
+	if a.config.Metrics.Port != "" {
+		mux := http.NewServeMux()
+		mux.Handle(metrics.METRICS_PATH, metrics.Handler())
+		ms := &http.Server{Addr: a.config.Metrics.Host + ":" + a.config.Metrics.Port, Handler: mux}
+		servers = append(servers, ms)
+
+		go func() {
+			if err := ms.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.log.Err(err).Msg("failed to start metrics server")
+			}
+		}()
+
+		a.log.Info().Msgf("Serving metrics on %v%v\n", ms.Addr, metrics.METRICS_PATH)
+	}
+
+	if a.config.Health.Port != "" {
+		hs := &http.Server{Addr: a.config.Health.Host + ":" + a.config.Health.Port, Handler: a.healthChecker.Handler()}
+		servers = append(servers, hs)
+
+		go func() {
+			if err := hs.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.log.Err(err).Msg("failed to start health server")
+			}
+		}()
+
+		a.log.Info().Msgf("Serving health probes on %v%v and %v%v\n", hs.Addr, HEALTHZ_PATH, hs.Addr, READYZ_PATH)
+	}
+
 	select {
 	case err = <-ch:
-		return err
+		if gw != nil {
+			gw.Close()
+		}
+	case err = <-gwCh:
+		g.Stop()
 	case <-ctx.Done():
-		a.ctxCancel()
-		g.GracefulStop()
+		a.shutdown(g, gw, servers, cancelRoutines)
 		return nil
 	}
+	stopBackground()
+
+	return err
 }
 
 func appConfigToDaemonsConfig(c *AppConfig) *dto.DaemonsConfig {
@@ -143,9 +425,197 @@ func appConfigToDaemonsConfig(c *AppConfig) *dto.DaemonsConfig {
 		}
 	}
 
+	ethTokens := make([]dto.EthTokenConfig, 0, len(c.Coin.Eth.Tokens))
+	for i := 0; i < len(c.Coin.Eth.Tokens); i++ {
+		ethTokens = append(ethTokens, dto.EthTokenConfig{
+			Symbol:   c.Coin.Eth.Tokens[i].Symbol,
+			Contract: c.Coin.Eth.Tokens[i].Contract,
+			Decimals: c.Coin.Eth.Tokens[i].Decimals,
+		})
+	}
+
 	return &dto.DaemonsConfig{
-		Xmr: *acdTodc(&c.Coin.Xmr.Daemon),
+		Xmr:       *acdTodc(&c.Coin.Xmr.Daemon),
+		Btc:       *acdTodc(&c.Coin.Btc.Daemon),
+		Ltc:       *acdTodc(&c.Coin.Ltc.Daemon),
+		Eth:       *acdTodc(&c.Coin.Eth.Daemon),
+		Ton:       *acdTodc(&c.Coin.Ton.Daemon),
+		EthTokens: ethTokens,
+	}
+}
+
+func appConfigToInvoiceConfig(c *AppConfig) (*dto.InvoiceConfig, error) {
+	var gracePeriod time.Duration
+	if c.Invoice.LatePaymentGracePeriod != "" {
+		d, err := time.ParseDuration(c.Invoice.LatePaymentGracePeriod)
+		if err != nil {
+			return nil, err
+		}
+		gracePeriod = d
 	}
+
+	return &dto.InvoiceConfig{LatePaymentGracePeriod: gracePeriod}, nil
+}
+
+// appConfigToKeyring returns nil if no master key is configured.
+func appConfigToKeyring(c *AppConfig) (*envelope.Keyring, error) {
+	var current []byte
+	var err error
+	switch {
+	case c.Encryption.MasterKey != "":
+		current, err = envelope.ParseMasterKey(c.Encryption.MasterKey)
+	case c.Encryption.MasterKeyFile != "":
+		current, err = envelope.ReadMasterKeyFile(c.Encryption.MasterKeyFile)
+	case c.Encryption.PreviousMasterKeyFiles != "":
+		return nil, MissingMasterKeyErr
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make([][]byte, 0)
+	for _, path := range strings.Split(c.Encryption.PreviousMasterKeyFiles, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+
+		key, err := envelope.ReadMasterKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, key)
+	}
+
+	return envelope.NewKeyring(current, previous...)
+}
+
+func appConfigToDbUrl(c *AppConfig) string {
+	return fmt.Sprintf("postgresql://%v:%v@%v:%v/%v", c.Database.User, c.Database.Pass, c.Database.Host, c.Database.Port, c.Database.Name)
+}
+
+// gracefulStop stops the server gracefully, the calls still running once ctx is done are cancelled.
+func gracefulStop(ctx context.Context, g *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		g.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		g.Stop()
+		<-stopped
+	}
+}
+
+// stopBackground shuts down the metrics and health servers and waits for the background goroutines until ctx is done.
+func (a *App) stopBackground(ctx context.Context, servers []*http.Server, cancelRoutines context.CancelFunc) {
+	cancelRoutines()
+	for i := 0; i < len(servers); i++ {
+		if err := servers[i].Shutdown(ctx); err != nil {
+			servers[i].Close()
+		}
+	}
+
+	if err := a.routines.Wait(ctx); err != nil {
+		a.log.Err(err).Msg("failed to wait for the background goroutines")
+	}
+}
+
+// shutdown stops in order: the RPCs, the daemon sync and the in-flight invoice verification, waited for within the shutdown timeout,
+// then the last synced block heights are persisted and the background goroutines, e.g. a webhook delivery, are waited for
+// within the same timeout. The pool is closed by Start afterwards.
+func (a *App) shutdown(g *grpc.Server, gw *http.Server, servers []*http.Server, cancelRoutines context.CancelFunc) {
+	a.log.Info().Msg("Shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	// The invoice streams would keep the server from stopping until the timeout
+	a.paymentProcessor.CloseSubscriptions()
+	if gw != nil {
+		if err := gw.Shutdown(ctx); err != nil {
+			gw.Close()
+		}
+	}
+	gracefulStop(ctx, g)
+
+	if err := a.paymentProcessor.Shutdown(ctx); err != nil {
+		a.log.Err(err).Msg("failed to shut down the payment processor gracefully")
+	}
+	a.stopBackground(ctx, servers, cancelRoutines)
+	a.ctxCancel()
+}
+
+// newDbConnPool creates the pool tracing every query.
+func newDbConnPool(ctx context.Context, c *AppConfig) (*pgxpool.Pool, error) {
+	poolConf, err := pgxpool.ParseConfig(appConfigToDbUrl(c))
+	if err != nil {
+		return nil, err
+	}
+	poolConf.ConnConfig.Tracer = tracing.NewQueryTracer()
+
+	return pgxpool.NewWithConfig(ctx, poolConf)
+}
+
+// runMigrations runs the migrate command over a database/sql wrapper of the pool.
+func runMigrations(ctx context.Context, connPool *pgxpool.Pool, command string, log *zerolog.Logger) error {
+	db := stdlib.OpenDBFromPool(connPool)
+	defer db.Close()
+
+	return migrate.Run(ctx, db, command, log)
+}
+
+func appConfigToTracerProvider(ctx context.Context, c *AppConfig) (*sdktrace.TracerProvider, error) {
+	if c.Tracing.OtlpEndpoint == "" {
+		return nil, nil
+	}
+
+	insecure := false
+	if c.Tracing.Insecure != "" {
+		var err error
+		if insecure, err = strconv.ParseBool(c.Tracing.Insecure); err != nil {
+			return nil, err
+		}
+	}
+
+	return tracing.NewOtlpTracerProvider(ctx, c.Tracing.OtlpEndpoint, insecure)
+}
+
+func appConfigToShutdownTimeout(c *AppConfig) (time.Duration, error) {
+	if c.Server.ShutdownTimeout == "" {
+		return DEFAULT_SHUTDOWN_TIMEOUT, nil
+	}
+
+	return time.ParseDuration(c.Server.ShutdownTimeout)
+}
+
+func appConfigToMaxSyncLag(c *AppConfig) (uint64, error) {
+	if c.Health.MaxSyncLag == "" {
+		return DEFAULT_MAX_SYNC_LAG, nil
+	}
+
+	return strconv.ParseUint(c.Health.MaxSyncLag, 10, 64)
+}
+
+func appConfigAutoMigrate(c *AppConfig) (bool, error) {
+	if c.Database.AutoMigrate == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(c.Database.AutoMigrate)
+}
+
+// appConfigAuthEnabled reports whether the api key authentication is on, it has to be turned off explicitly with server.auth.enabled: false.
+func appConfigAuthEnabled(c *AppConfig) (bool, error) {
+	if c.Server.Auth.Enabled == "" {
+		return true, nil
+	}
+
+	return strconv.ParseBool(c.Server.Auth.Enabled)
 }
 
 func getLogger() *zerolog.Logger {
@@ -162,22 +632,132 @@ func NewApp(pathToConfig string) *App {
 		log.Fatal().Err(err)
 	}
 
-	dbUrl := fmt.Sprintf("postgresql://%v:%v@%v:%v/%v", conf.Database.User, conf.Database.Pass, conf.Database.Host, conf.Database.Port, conf.Database.Name)
-	connPool, err := pgxpool.New(ctx, dbUrl)
+	tp, err := appConfigToTracerProvider(ctx, conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid tracing config")
+	}
+
+	connPool, err := newDbConnPool(ctx, conf)
 	if err != nil {
 		log.Fatal().Err(err)
 	}
 
-	pp, err := processor.NewPaymentProcessor(ctx, connPool, appConfigToDaemonsConfig(conf), log)
+	autoMigrate, err := appConfigAutoMigrate(conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid database.autoMigrate")
+	}
+	if autoMigrate {
+		if err := runMigrations(ctx, connPool, migrate.UP_CMD, log); err != nil {
+			log.Fatal().Err(err).Msg("failed to apply the migrations")
+		}
+	}
+
+	invoiceConf, err := appConfigToInvoiceConfig(conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid invoice.latePaymentGracePeriod")
+	}
+
+	if conf.Server.TLS.Cert == "" && (conf.Server.TLS.Key != "" || conf.Server.TLS.ClientCa != "") {
+		log.Fatal().Msg("server.tls.cert is required to enable TLS")
+	}
+
+	authEnabled, err := appConfigAuthEnabled(conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid server.auth.enabled")
+	}
+	if !authEnabled {
+		log.Warn().Msg("Api key authentication is disabled by server.auth.enabled, every client has admin access and the AdminService is not served")
+	}
+
+	keyring, err := appConfigToKeyring(conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid encryption config")
+	}
+	if keyring == nil {
+		log.Warn().Msg("No master key is configured, the XMR private view keys are stored in plaintext")
+	}
+
+	shutdownTimeout, err := appConfigToShutdownTimeout(conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid server.shutdownTimeout")
+	}
+
+	maxSyncLag, err := appConfigToMaxSyncLag(conf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid health.maxSyncLag")
+	}
+
+	daemonsConf := appConfigToDaemonsConfig(conf)
+	daemonsConf.XmrKeyring = keyring
+
+	pp, err := processor.NewPaymentProcessor(ctx, connPool, daemonsConf, invoiceConf, log)
 	if err != nil {
 		log.Fatal().Err(err)
 	}
+	metrics.Registry.MustRegister(metrics.NewInvoiceCollector(connPool, pp.PendingInvoices, log))
 
 	return &App{
-		log:              log,
-		ctxCancel:        cancel,
-		config:           conf,
-		dbConnPool:       connPool,
-		paymentProcessor: pp,
+		log:               log,
+		ctxCancel:         cancel,
+		config:            conf,
+		shutdownTimeout:   shutdownTimeout,
+		dbConnPool:        connPool,
+		tracerProvider:    tp,
+		paymentProcessor:  pp,
+		webhookDispatcher: webhook.NewDispatcher(connPool, &http.Client{}, log),
+		authInterceptor:   NewAuthInterceptor(connPool, authEnabled, conf.Server.Auth.AdminKey, log),
+		healthChecker:     newHealthChecker(connPool.Ping, pp.SyncLag, maxSyncLag, log),
 	}
 }
+
+// RotateMasterKey rewraps the stored XMR private view keys with the current master key
+// and encrypts the ones still stored in plaintext. The previous master keys have to be configured until it's done.
+func RotateMasterKey(ctx context.Context, pathToConfig string) error {
+	log := getLogger()
+
+	conf, err := NewAppConfig(pathToConfig)
+	if err != nil {
+		return err
+	}
+
+	keyring, err := appConfigToKeyring(conf)
+	if err != nil {
+		return err
+	}
+	if keyring == nil {
+		return MissingMasterKeyErr
+	}
+
+	connPool, err := newDbConnPool(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	updated, err := processor.RotateXmrPrivViewKeys(ctx, connPool, keyring)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msgf("%v XMR private view keys have been encrypted with the master key %v", updated, keyring.CurrentKeyId())
+
+	return nil
+}
+
+// Migrate runs the migrate command against the configured database, see migrate.Run.
+func Migrate(ctx context.Context, pathToConfig string, command string) error {
+	log := getLogger()
+
+	conf, err := NewAppConfig(pathToConfig)
+	if err != nil {
+		return err
+	}
+
+	connPool, err := newDbConnPool(ctx, conf)
+	if err != nil {
+		return err
+	}
+	defer connPool.Close()
+
+	return runMigrations(ctx, connPool, command, log)
+}
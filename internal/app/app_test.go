@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppConfigTracing(t *testing.T) {
+	t.Setenv("TRACING_OTLP_ENDPOINT", "localhost:4317")
+	t.Setenv("TRACING_INSECURE", "true")
+	conf, err := NewAppConfig("../../config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "localhost:4317", conf.Tracing.OtlpEndpoint)
+	assert.Equal(t, "true", conf.Tracing.Insecure)
+
+	tp, err := appConfigToTracerProvider(context.Background(), conf)
+	assert.NoError(t, err)
+	if assert.NotNil(t, tp) {
+		tp.Shutdown(context.Background())
+	}
+}
+
+func TestAppConfigHealth(t *testing.T) {
+	t.Setenv("HEALTH_HOST", "localhost")
+	t.Setenv("HEALTH_PORT", "8081")
+	t.Setenv("HEALTH_MAX_SYNC_LAG", "20")
+	conf, err := NewAppConfig("../../config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "localhost", conf.Health.Host)
+	assert.Equal(t, "8081", conf.Health.Port)
+
+	maxSyncLag, err := appConfigToMaxSyncLag(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), maxSyncLag)
+}
+
+func TestAppConfigShutdownTimeout(t *testing.T) {
+	t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "45s")
+	conf, err := NewAppConfig("../../config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout, err := appConfigToShutdownTimeout(conf)
+	assert.NoError(t, err)
+	assert.Equal(t, 45*time.Second, timeout)
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	handler_v1 "github.com/chekist32/goipay/internal/handler/v1"
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	pb_v2 "github.com/chekist32/goipay/internal/pb/v2"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGatewayServer creates the HTTP/JSON gateway. Every call is proxied to the gRPC server listening on grpcLis,
+// so the requests go through the same interceptors as the native gRPC ones.
+func newGatewayServer(ctx context.Context, addr string, grpcLis *bufconn.Listener, log *zerolog.Logger) (*http.Server, *grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(
+		"passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return grpcLis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mux := runtime.NewServeMux()
+	if err := pb_v1.RegisterUserServiceHandler(ctx, mux, conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if err := pb_v1.RegisterInvoiceServiceHandler(ctx, mux, conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if err := pb_v1.RegisterAdminServiceHandler(ctx, mux, conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if err := pb_v2.RegisterInvoiceServiceHandler(ctx, mux, conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	sse := handler_v1.NewInvoiceEventsSse(mux, pb_v1.NewInvoiceServiceClient(conn), log)
+	if err := mux.HandlePath(http.MethodGet, handler_v1.INVOICE_EVENTS_SSE_PATH, sse.Handle); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return &http.Server{Addr: addr, Handler: mux}, conn, nil
+}
+
+// listenAndServeGateway serves the gateway over TLS if its TLSConfig is set, the certificates come from the config.
+func listenAndServeGateway(gw *http.Server) error {
+	if gw.TLSConfig != nil {
+		return gw.ListenAndServeTLS("", "")
+	}
+
+	return gw.ListenAndServe()
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	pb_v2 "github.com/chekist32/goipay/internal/pb/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeGatewayInvoiceServer records the proxied requests and answers them with canned responses.
+type fakeGatewayInvoiceServer struct {
+	pb_v1.UnimplementedInvoiceServiceServer
+
+	md         metadata.MD
+	createReq  *pb_v1.CreateInvoiceRequest
+	getReq     *pb_v1.GetInvoicesRequest
+	streamReqs chan *pb_v1.InvoiceStatusStreamRequest
+}
+
+func (f *fakeGatewayInvoiceServer) CreateInvoice(ctx context.Context, req *pb_v1.CreateInvoiceRequest) (*pb_v1.CreateInvoiceResponse, error) {
+	f.md, _ = metadata.FromIncomingContext(ctx)
+	f.createReq = req
+	return &pb_v1.CreateInvoiceResponse{PaymentId: "8f2b4a51-0c5d-4b0e-9d8e-5b4c1f0e2a77", Address: "address"}, nil
+}
+
+func (f *fakeGatewayInvoiceServer) GetInvoices(ctx context.Context, req *pb_v1.GetInvoicesRequest) (*pb_v1.GetInvoicesResponse, error) {
+	f.getReq = req
+	if len(req.PaymentIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no payment ids")
+	}
+	return &pb_v1.GetInvoicesResponse{Invoices: []*pb_v1.Invoice{{Id: req.PaymentIds[0], RequiredAmountAtomic: "1000"}}}, nil
+}
+
+func (f *fakeGatewayInvoiceServer) InvoiceStatusStream(req *pb_v1.InvoiceStatusStreamRequest, stream pb_v1.InvoiceService_InvoiceStatusStreamServer) error {
+	f.streamReqs <- req
+	return stream.Send(&pb_v1.InvoiceStatusStreamResponse{Invoice: &pb_v1.Invoice{Id: "id1"}, Sequence: 5})
+}
+
+type fakeGatewayUserServer struct {
+	pb_v1.UnimplementedUserServiceServer
+
+	deleteReq *pb_v1.DeleteWebhookRequest
+}
+
+func (f *fakeGatewayUserServer) DeleteWebhook(ctx context.Context, req *pb_v1.DeleteWebhookRequest) (*pb_v1.DeleteWebhookResponse, error) {
+	f.deleteReq = req
+	return &pb_v1.DeleteWebhookResponse{}, nil
+}
+
+type fakeGatewayInvoiceV2Server struct {
+	pb_v2.UnimplementedInvoiceServiceServer
+}
+
+func (f *fakeGatewayInvoiceV2Server) GetInvoices(ctx context.Context, req *pb_v2.GetInvoicesRequest) (*pb_v2.GetInvoicesResponse, error) {
+	return &pb_v2.GetInvoicesResponse{Invoices: []*pb_v2.Invoice{{Id: req.PaymentIds[0], RequiredAmount: "1000"}}}, nil
+}
+
+func newTestGateway(t *testing.T, invoiceSrv *fakeGatewayInvoiceServer, userSrv *fakeGatewayUserServer) http.Handler {
+	lis := bufconn.Listen(1024 * 1024)
+	g := grpc.NewServer()
+	pb_v1.RegisterInvoiceServiceServer(g, invoiceSrv)
+	pb_v1.RegisterUserServiceServer(g, userSrv)
+	pb_v2.RegisterInvoiceServiceServer(g, &fakeGatewayInvoiceV2Server{})
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	srv, conn, err := newGatewayServer(context.Background(), "", lis, &zerolog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return srv.Handler
+}
+
+func serveGateway(h http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer key")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestGatewayRoutes(t *testing.T) {
+	invoiceSrv := &fakeGatewayInvoiceServer{streamReqs: make(chan *pb_v1.InvoiceStatusStreamRequest, 1)}
+	userSrv := &fakeGatewayUserServer{}
+	h := newTestGateway(t, invoiceSrv, userSrv)
+
+	t.Run("POST /v1/invoices", func(t *testing.T) {
+		w := serveGateway(h, http.MethodPost, "/v1/invoices", `{"userId": "u1", "coin": "XMR", "amountAtomic": "1000000000000", "timeout": 3600, "confirmations": 1}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		amountAtomic := "1000000000000"
+		assert.True(t, proto.Equal(&pb_v1.CreateInvoiceRequest{UserId: "u1", Coin: pb_v1.CoinType_XMR, AmountAtomic: &amountAtomic, Timeout: 3600, Confirmations: 1}, invoiceSrv.createReq), "request %v", invoiceSrv.createReq)
+		// The Authorization header is forwarded for the auth interceptor
+		assert.Equal(t, []string{"Bearer key"}, invoiceSrv.md.Get("authorization"))
+
+		var res map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, "8f2b4a51-0c5d-4b0e-9d8e-5b4c1f0e2a77", res["paymentId"])
+	})
+
+	t.Run("GET /v1/invoices", func(t *testing.T) {
+		w := serveGateway(h, http.MethodGet, "/v1/invoices?paymentIds=id1&paymentIds=id2", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"id1", "id2"}, invoiceSrv.getReq.PaymentIds)
+		assert.Contains(t, w.Body.String(), `"requiredAmountAtomic":"1000"`)
+
+		// The gRPC status is mapped to the HTTP one
+		w = serveGateway(h, http.MethodGet, "/v1/invoices", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "no payment ids")
+	})
+
+	t.Run("GET /v2/invoices", func(t *testing.T) {
+		w := serveGateway(h, http.MethodGet, "/v2/invoices?paymentIds=id1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"requiredAmount":"1000"`)
+	})
+
+	t.Run("DELETE /v1/users/{userId}/webhooks/{webhookId}", func(t *testing.T) {
+		w := serveGateway(h, http.MethodDelete, "/v1/users/u1/webhooks/w1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, proto.Equal(&pb_v1.DeleteWebhookRequest{UserId: "u1", WebhookId: "w1"}, userSrv.deleteReq), "request %v", userSrv.deleteReq)
+	})
+
+	t.Run("GET /v1/invoices/events", func(t *testing.T) {
+		w := serveGateway(h, http.MethodGet, "/v1/invoices/events?coins=BTC", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(w.Body.String(), "id: 5\nevent: invoice\n"))
+		assert.Equal(t, []pb_v1.CoinType{pb_v1.CoinType_BTC}, (<-invoiceSrv.streamReqs).Coins)
+	})
+
+	t.Run("Should Return 501 (unimplemented rpc)", func(t *testing.T) {
+		w := serveGateway(h, http.MethodGet, "/v1/admin/sync-states", "")
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+
+	t.Run("Should Return 404 (unknown route)", func(t *testing.T) {
+		w := serveGateway(h, http.MethodGet, "/v1/unknown", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGatewayTls(t *testing.T) {
+	log := zerolog.Nop()
+	dir := t.TempDir()
+	certFile, keyFile, clientCaFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt")
+
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+	client := newTestCert(t, "client", ca)
+
+	modTime := time.Now().Add(-time.Minute)
+	writeTestFile(t, certFile, server.certPem, modTime)
+	writeTestFile(t, keyFile, server.keyPem, modTime)
+	writeTestFile(t, clientCaFile, ca.certPem, modTime)
+
+	r, err := newTlsReloader(certFile, keyFile, clientCaFile, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userSrv := &fakeGatewayUserServer{}
+	srv := httptest.NewUnstartedServer(newTestGateway(t, &fakeGatewayInvoiceServer{}, userSrv))
+	srv.TLS = r.TLSConfig("h2", "http/1.1")
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientCert, err := tls.X509KeyPair(client.certPem, client.keyPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleteWebhook := func(clientConf *tls.Config) (*http.Response, error) {
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConf}}
+		req, err := http.NewRequest(http.MethodDelete, srv.URL+"/v1/users/u1/webhooks/w1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c.Do(req)
+	}
+
+	t.Run("Should Serve HTTP/1.1 With Client Certificate", func(t *testing.T) {
+		res, err := deleteWebhook(&tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{clientCert}})
+		if assert.NoError(t, err) {
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "HTTP/1.1", res.Proto)
+			assert.True(t, proto.Equal(&pb_v1.DeleteWebhookRequest{UserId: "u1", WebhookId: "w1"}, userSrv.deleteReq), "request %v", userSrv.deleteReq)
+		}
+	})
+
+	t.Run("Should Reject Client Without Certificate", func(t *testing.T) {
+		_, err := deleteWebhook(&tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Should Reject Plain HTTP", func(t *testing.T) {
+		res, err := http.Get("http" + strings.TrimPrefix(srv.URL, "https") + "/v1/users/u1/webhooks")
+		if assert.NoError(t, err) {
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	HEALTHZ_PATH string = "/healthz"
+	READYZ_PATH  string = "/readyz"
+
+	DEFAULT_MAX_SYNC_LAG uint64 = 10
+
+	health_check_interval time.Duration = 10 * time.Second
+	health_check_timeout  time.Duration = 5 * time.Second
+)
+
+// healthChecker periodically pings the database and checks how far the daemons are ahead of the sync,
+// reporting the result through the grpc.health.v1.Health service and the HTTP probes.
+// The UserService only depends on the database, while the InvoiceService and the server as a whole ("") need the daemons synced as well.
+type healthChecker struct {
+	log *zerolog.Logger
+
+	server     *health.Server
+	pingDb     func(ctx context.Context) error
+	syncLag    func() map[db.CoinType]uint64
+	maxSyncLag uint64
+
+	mu sync.RWMutex
+	// Reasons of the server not being ready, empty if it is
+	failures []string
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
+func (h *healthChecker) setFailures(failures []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = failures
+}
+
+func (h *healthChecker) check(ctx context.Context) {
+	failures := make([]string, 0)
+
+	ctx, cancel := context.WithTimeout(ctx, health_check_timeout)
+	defer cancel()
+
+	dbOk := true
+	if err := h.pingDb(ctx); err != nil {
+		h.log.Err(err).Msg("Health check failed to ping the database")
+		failures = append(failures, "database: "+err.Error())
+		dbOk = false
+	}
+
+	syncOk := true
+	lagFailures := make([]string, 0)
+	for coin, lag := range h.syncLag() {
+		if lag > h.maxSyncLag {
+			lagFailures = append(lagFailures, fmt.Sprintf("%v: %v blocks behind the daemon", coin, lag))
+			syncOk = false
+		}
+	}
+	slices.Sort(lagFailures)
+	failures = append(failures, lagFailures...)
+
+	h.server.SetServingStatus(pb_v1.UserService_ServiceDesc.ServiceName, servingStatus(dbOk))
+	h.server.SetServingStatus(pb_v1.InvoiceService_ServiceDesc.ServiceName, servingStatus(dbOk && syncOk))
+	h.server.SetServingStatus("", servingStatus(dbOk && syncOk))
+	h.setFailures(failures)
+}
+
+// Start checks the health every health_check_interval until ctx is done, then reports every service as NOT_SERVING.
+func (h *healthChecker) Start(ctx context.Context) {
+	ticker := time.NewTicker(health_check_interval)
+	defer ticker.Stop()
+
+	h.check(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			h.check(ctx)
+		case <-ctx.Done():
+			h.setFailures([]string{"shutting down"})
+			h.server.Shutdown()
+			return
+		}
+	}
+}
+
+// healthz is the liveness probe, the server is alive as long as it responds.
+func (h *healthChecker) healthz(w http.ResponseWriter, r *http.Request) {
+	io.WriteString(w, "ok\n")
+}
+
+// readyz is the readiness probe, it responds with 503 and the reasons while the server isn't ready.
+func (h *healthChecker) readyz(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	failures := h.failures
+	h.mu.RUnlock()
+
+	if len(failures) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, strings.Join(failures, "\n")+"\n")
+		return
+	}
+
+	io.WriteString(w, "ok\n")
+}
+
+// Handler serves the HTTP probes.
+func (h *healthChecker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HEALTHZ_PATH, h.healthz)
+	mux.HandleFunc(READYZ_PATH, h.readyz)
+	return mux
+}
+
+// newHealthChecker creates the checker, every service is NOT_SERVING until the first check.
+func newHealthChecker(pingDb func(ctx context.Context) error, syncLag func() map[db.CoinType]uint64, maxSyncLag uint64, log *zerolog.Logger) *healthChecker {
+	h := &healthChecker{
+		log:        log,
+		server:     health.NewServer(),
+		pingDb:     pingDb,
+		syncLag:    syncLag,
+		maxSyncLag: maxSyncLag,
+		failures:   []string{"not checked yet"},
+	}
+
+	for _, service := range []string{"", pb_v1.UserService_ServiceDesc.ServiceName, pb_v1.InvoiceService_ServiceDesc.ServiceName} {
+		h.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return h
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chekist32/goipay/internal/db"
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func getServingStatus(t *testing.T, h *healthChecker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	res, err := h.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.Status
+}
+
+func getReadyz(h *healthChecker) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.Handler().ServeHTTP(rec, httptest.NewRequest("GET", READYZ_PATH, nil))
+	return rec
+}
+
+func TestHealthChecker(t *testing.T) {
+	var dbErr error
+	lag := map[db.CoinType]uint64{db.CoinTypeXMR: 0, db.CoinTypeBTC: 0}
+	h := newHealthChecker(
+		func(ctx context.Context) error { return dbErr },
+		func() map[db.CoinType]uint64 { return lag },
+		DEFAULT_MAX_SYNC_LAG,
+		&zerolog.Logger{},
+	)
+	userService, invoiceService := pb_v1.UserService_ServiceDesc.ServiceName, pb_v1.InvoiceService_ServiceDesc.ServiceName
+
+	t.Run("Not Checked Yet", func(t *testing.T) {
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, getServingStatus(t, h, ""))
+		assert.Equal(t, http.StatusServiceUnavailable, getReadyz(h).Code)
+	})
+
+	t.Run("Serving", func(t *testing.T) {
+		lag[db.CoinTypeXMR] = DEFAULT_MAX_SYNC_LAG
+		h.check(context.Background())
+
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, getServingStatus(t, h, ""))
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, getServingStatus(t, h, userService))
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, getServingStatus(t, h, invoiceService))
+		assert.Equal(t, http.StatusOK, getReadyz(h).Code)
+	})
+
+	t.Run("Sync Lag", func(t *testing.T) {
+		lag[db.CoinTypeXMR] = DEFAULT_MAX_SYNC_LAG + 1
+		h.check(context.Background())
+
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, getServingStatus(t, h, ""))
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, getServingStatus(t, h, userService))
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, getServingStatus(t, h, invoiceService))
+
+		rec := getReadyz(h)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), "XMR: 11 blocks behind the daemon"))
+	})
+
+	t.Run("Database Down", func(t *testing.T) {
+		lag[db.CoinTypeXMR] = 0
+		dbErr = errors.New("connection refused")
+		h.check(context.Background())
+
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, getServingStatus(t, h, ""))
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, getServingStatus(t, h, userService))
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, getServingStatus(t, h, invoiceService))
+
+		rec := getReadyz(h)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), "database: connection refused"))
+	})
+
+	t.Run("Liveness", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.Handler().ServeHTTP(rec, httptest.NewRequest("GET", HEALTHZ_PATH, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -2,9 +2,21 @@ package app
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
 
+	"github.com/chekist32/goipay/internal/auth"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type RequestLoggingInterceptor struct {
@@ -27,3 +39,112 @@ func (i *RequestLoggingInterceptor) Intercepte(ctx context.Context, req any, inf
 func NewRequestLoggingInterceptor(log *zerolog.Logger) *RequestLoggingInterceptor {
 	return &RequestLoggingInterceptor{log: log}
 }
+
+// MetricsInterceptor counts the calls by method and status code and observes their latencies.
+type MetricsInterceptor struct{}
+
+func (i *MetricsInterceptor) observe(fullMethod string, start time.Time, err error) {
+	metrics.GrpcRequestsTotal.WithLabelValues(fullMethod, status.Code(err).String()).Inc()
+	metrics.GrpcRequestDurationSeconds.WithLabelValues(fullMethod).Observe(time.Since(start).Seconds())
+}
+
+func (i *MetricsInterceptor) Intercepte(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	res, err := handler(ctx, req)
+	i.observe(info.FullMethod, start, err)
+
+	return res, err
+}
+
+func (i *MetricsInterceptor) IntercepteStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+	i.observe(info.FullMethod, start, err)
+
+	return err
+}
+
+func NewMetricsInterceptor() *MetricsInterceptor {
+	return &MetricsInterceptor{}
+}
+
+// AuthInterceptor authenticates the calls of the goipay services with the api key from the "authorization: Bearer <key>" metadata
+// and stores the key owner in the context, see auth.FromContext. The handlers are responsible for scoping the data to it.
+type AuthInterceptor struct {
+	log          *zerolog.Logger
+	dbConnPool   *pgxpool.Pool
+	enabled      bool
+	adminKeyHash string
+}
+
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (i *AuthInterceptor) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	// Reflection, health checks and other standard services stay public
+	if strings.HasPrefix(fullMethod, "/grpc.") {
+		return ctx, nil
+	}
+	if !i.enabled {
+		return auth.NewContext(ctx, &auth.Principal{Admin: true}), nil
+	}
+
+	key, err := auth.KeyFromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "missing api key")
+	}
+
+	hash := auth.HashKey(key)
+	if i.adminKeyHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(i.adminKeyHash)) == 1 {
+		return auth.NewContext(ctx, &auth.Principal{Admin: true}), nil
+	}
+
+	apiKey, err := db.New(i.dbConnPool).FindActiveApiKeyByKeyHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+
+		i.log.Err(err).Str("queryName", "FindActiveApiKeyByKeyHash").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	return auth.NewContext(ctx, auth.NewPrincipal(&apiKey)), nil
+}
+
+func (i *AuthInterceptor) Intercepte(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := i.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (i *AuthInterceptor) IntercepteStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// NewAuthInterceptor creates the interceptor. If it's disabled, every call is treated as an admin one.
+// The adminKey is accepted besides the keys stored in the database, it's meant to create the first ones.
+func NewAuthInterceptor(dbConnPool *pgxpool.Pool, enabled bool, adminKey string, log *zerolog.Logger) *AuthInterceptor {
+	var adminKeyHash string
+	if adminKey != "" {
+		adminKeyHash = auth.HashKey(adminKey)
+	}
+
+	return &AuthInterceptor{log: log, dbConnPool: dbConnPool, enabled: enabled, adminKeyHash: adminKeyHash}
+}
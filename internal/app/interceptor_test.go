@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsInterceptor(t *testing.T) {
+	const method string = "/invoice.v1.InvoiceService/CreateInvoice"
+
+	i := NewMetricsInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	okBefore := testutil.ToFloat64(metrics.GrpcRequestsTotal.WithLabelValues(method, codes.OK.String()))
+	notFoundBefore := testutil.ToFloat64(metrics.GrpcRequestsTotal.WithLabelValues(method, codes.NotFound.String()))
+
+	_, err := i.Intercepte(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) { return nil, nil })
+	assert.NoError(t, err)
+
+	_, err = i.Intercepte(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, okBefore+1, testutil.ToFloat64(metrics.GrpcRequestsTotal.WithLabelValues(method, codes.OK.String())))
+	assert.Equal(t, notFoundBefore+1, testutil.ToFloat64(metrics.GrpcRequestsTotal.WithLabelValues(method, codes.NotFound.String())))
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", metrics.METRICS_PATH, nil))
+	body := rec.Body.String()
+	assert.True(t, strings.Contains(body, `goipay_grpc_requests_total{code="NotFound",method="`+method+`"}`))
+	assert.True(t, strings.Contains(body, `goipay_grpc_request_duration_seconds_count{method="`+method+`"}`))
+}
+
+func TestAuthInterceptorDefaults(t *testing.T) {
+	const method string = "/invoice.v1.InvoiceService/CreateInvoice"
+
+	// The repo config with SERVER_AUTH_ENABLED unset
+	t.Setenv("SERVER_AUTH_ENABLED", "")
+	conf, err := NewAppConfig("../../config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled, err := appConfigAuthEnabled(conf)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	i := NewAuthInterceptor(nil, enabled, "", &zerolog.Logger{})
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, err = i.Intercepte(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.False(t, called)
+
+	// The standard services stay public
+	_, err = i.Intercepte(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	t.Run("Should Be Disabled Only Explicitly", func(t *testing.T) {
+		for value, expected := range map[string]bool{"false": false, "0": false, "true": true} {
+			var c AppConfig
+			c.Server.Auth.Enabled = value
+			enabled, err := appConfigAuthEnabled(&c)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, enabled, value)
+		}
+
+		var c AppConfig
+		c.Server.Auth.Enabled = "off"
+		_, err := appConfigAuthEnabled(&c)
+		assert.Error(t, err)
+	})
+}
+
+func TestAdminServiceRequiresAuth(t *testing.T) {
+	log := zerolog.Nop()
+
+	for _, enabled := range []bool{true, false} {
+		a := &App{
+			config:          &AppConfig{},
+			log:             &log,
+			authInterceptor: NewAuthInterceptor(nil, enabled, "", &log),
+			healthChecker:   newHealthChecker(nil, nil, 0, &log),
+		}
+
+		_, ok := a.newGrpcServer().GetServiceInfo()["admin.v1.AdminService"]
+		assert.Equal(t, enabled, ok, "auth enabled: %v", enabled)
+	}
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const tls_reload_interval time.Duration = 10 * time.Second
+
+var (
+	// InvalidClientCaErr is returned if the client CA file contains no PEM certificates.
+	InvalidClientCaErr error = errors.New("no certificates found in the client CA file")
+)
+
+// tlsReloader serves the server certificate and the client CA pool from their files
+// and reloads them once the files change, so renewed certificates are picked up without a restart.
+type tlsReloader struct {
+	log *zerolog.Logger
+
+	certFile     string
+	keyFile      string
+	clientCaFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  []time.Time
+}
+
+func (r *tlsReloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCaFile != "" {
+		files = append(files, r.clientCaFile)
+	}
+
+	return files
+}
+
+func (r *tlsReloader) filesModTimes() ([]time.Time, error) {
+	files := r.files()
+
+	modTimes := make([]time.Time, 0, len(files))
+	for i := 0; i < len(files); i++ {
+		info, err := os.Stat(files[i])
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	return modTimes, nil
+}
+
+func (r *tlsReloader) modified(modTimes []time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.modTimes) != len(modTimes) {
+		return true
+	}
+	for i := 0; i < len(modTimes); i++ {
+		if !r.modTimes[i].Equal(modTimes[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reload loads the files if any of them has been modified since the last load and reports whether it did.
+func (r *tlsReloader) reload() (bool, error) {
+	modTimes, err := r.filesModTimes()
+	if err != nil {
+		return false, err
+	}
+	if !r.modified(modTimes) {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCaFile != "" {
+		pem, err := os.ReadFile(r.clientCaFile)
+		if err != nil {
+			return false, err
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return false, InvalidClientCaErr
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// Start polls the files until the ctx is done. A failed reload keeps the previous certificates.
+func (r *tlsReloader) Start(ctx context.Context) {
+	ticker := time.NewTicker(tls_reload_interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				r.log.Err(err).Msg("failed to reload TLS certificates, keeping the previous ones")
+				continue
+			}
+			if reloaded {
+				r.log.Info().Msg("TLS certificates have been reloaded")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *tlsReloader) configForClient(nextProtos []string) *tls.Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conf := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*r.cert},
+		// The config replaces the one passed to the gRPC credentials or the HTTP server, so the ALPN set by them has to be repeated
+		NextProtos: nextProtos,
+	}
+	if r.clientCAs != nil {
+		conf.ClientCAs = r.clientCAs
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return conf
+}
+
+// TLSConfig returns the server config negotiating the nextProtos, every handshake uses the certificates loaded last.
+func (r *tlsReloader) TLSConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.configForClient(nextProtos), nil
+		},
+	}
+}
+
+// newTlsReloader loads the certificates right away. Client certificates are required only if the clientCaFile is set.
+func newTlsReloader(certFile string, keyFile string, clientCaFile string, log *zerolog.Logger) (*tlsReloader, error) {
+	r := &tlsReloader{log: log, certFile: certFile, keyFile: keyFile, clientCaFile: clientCaFile}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPem []byte
+	keyPem  []byte
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPem:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+func writeTestFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func handshake(serverConf *tls.Config, clientConf *tls.Config) (*x509.Certificate, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	server := tls.Server(serverConn, serverConf)
+	go server.Handshake()
+
+	client := tls.Client(clientConn, clientConf)
+	if err := client.Handshake(); err != nil {
+		return nil, err
+	}
+	// The server verifies the client certificate after the client considers the handshake done with TLS 1.3
+	client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, err := client.Read(make([]byte, 1)); err != nil {
+		if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+			return nil, err
+		}
+	}
+
+	return client.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestTlsReloader(t *testing.T) {
+	log := zerolog.Nop()
+	dir := t.TempDir()
+	certFile, keyFile, clientCaFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt")
+
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+	client := newTestCert(t, "client", ca)
+
+	modTime := time.Now().Add(-time.Minute)
+	writeTestFile(t, certFile, server.certPem, modTime)
+	writeTestFile(t, keyFile, server.keyPem, modTime)
+	writeTestFile(t, clientCaFile, ca.certPem, modTime)
+
+	t.Run("Server TLS", func(t *testing.T) {
+		r, err := newTlsReloader(certFile, keyFile, "", &log)
+		assert.NoError(t, err)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+
+		peer, err := handshake(r.TLSConfig("h2"), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.NoError(t, err)
+		assert.Equal(t, server.cert.SerialNumber, peer.SerialNumber)
+	})
+
+	t.Run("Mutual TLS", func(t *testing.T) {
+		r, err := newTlsReloader(certFile, keyFile, clientCaFile, &log)
+		assert.NoError(t, err)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		clientCert, err := tls.X509KeyPair(client.certPem, client.keyPem)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = handshake(r.TLSConfig("h2"), &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{clientCert}})
+		assert.NoError(t, err)
+
+		_, err = handshake(r.TLSConfig("h2"), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.Error(t, err)
+
+		// Signed by another CA
+		otherClient := newTestCert(t, "client", newTestCert(t, "other ca", nil))
+		otherClientCert, err := tls.X509KeyPair(otherClient.certPem, otherClient.keyPem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = handshake(r.TLSConfig("h2"), &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{otherClientCert}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		r, err := newTlsReloader(certFile, keyFile, "", &log)
+		assert.NoError(t, err)
+
+		reloaded, err := r.reload()
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+
+		renewed := newTestCert(t, "server", ca)
+		writeTestFile(t, certFile, renewed.certPem, time.Now())
+		writeTestFile(t, keyFile, renewed.keyPem, time.Now())
+
+		reloaded, err = r.reload()
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		peer, err := handshake(r.TLSConfig("h2"), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.NoError(t, err)
+		assert.Equal(t, renewed.cert.SerialNumber, peer.SerialNumber)
+
+		// A broken file keeps the previous certificate
+		writeTestFile(t, keyFile, []byte("broken"), time.Now().Add(time.Minute))
+		_, err = r.reload()
+		assert.Error(t, err)
+
+		peer, err = handshake(r.TLSConfig("h2"), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.NoError(t, err)
+		assert.Equal(t, renewed.cert.SerialNumber, peer.SerialNumber)
+	})
+
+	t.Run("Invalid Files", func(t *testing.T) {
+		writeTestFile(t, certFile, server.certPem, time.Now())
+		writeTestFile(t, keyFile, server.keyPem, time.Now())
+
+		_, err := newTlsReloader(filepath.Join(dir, "missing.crt"), keyFile, "", &log)
+		assert.Error(t, err)
+
+		invalidCaFile := filepath.Join(dir, "invalid_ca.crt")
+		writeTestFile(t, invalidCaFile, []byte("not a pem"), time.Now())
+		_, err = newTlsReloader(certFile, keyFile, invalidCaFile, &log)
+		assert.ErrorIs(t, err, InvalidClientCaErr)
+	})
+}
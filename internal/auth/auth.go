@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	API_KEY_PREFIX       string = "gp_"
+	AUTHORIZATION_HEADER string = "authorization"
+
+	bearer_prefix  string = "Bearer "
+	api_key_length int    = 32
+)
+
+var (
+	// MissingApiKeyErr is returned if the request carries no "authorization: Bearer <key>" metadata.
+	MissingApiKeyErr error = errors.New("missing api key")
+)
+
+type principalCtxKey struct{}
+
+// Principal is the owner of the api key a request has been authenticated with.
+type Principal struct {
+	// Not valid for admin keys
+	UserId pgtype.UUID
+	Admin  bool
+}
+
+// CanAccessUser reports whether the principal may read or change the data of the user.
+func (p *Principal) CanAccessUser(userId pgtype.UUID) bool {
+	return p.Admin || (p.UserId.Valid && p.UserId == userId)
+}
+
+// NewPrincipal creates the principal of a stored api key.
+func NewPrincipal(key *db.ApiKey) *Principal {
+	if key.Role == db.ApiKeyRoleTypeADMIN {
+		return &Principal{Admin: true}
+	}
+
+	return &Principal{UserId: key.UserID}
+}
+
+// NewContext returns a copy of ctx carrying the principal.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx by NewContext.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(*Principal)
+	return p, ok
+}
+
+// NewKey generates a random api key. Only its hash is meant to be stored.
+func NewKey() (string, error) {
+	b := make([]byte, api_key_length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return API_KEY_PREFIX + hex.EncodeToString(b), nil
+}
+
+// HashKey returns the hex-encoded SHA-256 of the key.
+// Keys are random and long enough, so a slow password hash isn't needed.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyFromIncomingContext extracts the api key from the "authorization: Bearer <key>" metadata of the request.
+// The HTTP gateway forwards the Authorization header as this metadata.
+func KeyFromIncomingContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", MissingApiKeyErr
+	}
+
+	vals := md.Get(AUTHORIZATION_HEADER)
+	if len(vals) == 0 || !strings.HasPrefix(vals[0], bearer_prefix) {
+		return "", MissingApiKeyErr
+	}
+
+	key := strings.TrimSpace(strings.TrimPrefix(vals[0], bearer_prefix))
+	if key == "" {
+		return "", MissingApiKeyErr
+	}
+
+	return key, nil
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNewKey(t *testing.T) {
+	k1, err := NewKey()
+	assert.NoError(t, err)
+	k2, err := NewKey()
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(k1, API_KEY_PREFIX))
+	assert.Len(t, k1, len(API_KEY_PREFIX)+2*api_key_length)
+	assert.NotEqual(t, k1, k2)
+}
+
+func TestHashKey(t *testing.T) {
+	// echo -n 'gp_test' | sha256sum
+	assert.Equal(t, "b89063ef7b2710384a329f8ba900a413fecdf683702ecbdd65277008ee86bc8a", HashKey("gp_test"))
+	assert.NotEqual(t, HashKey("gp_test"), HashKey("gp_test2"))
+}
+
+func TestPrincipalCanAccessUser(t *testing.T) {
+	userId := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	otherUserId := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+
+	user := NewPrincipal(&db.ApiKey{Role: db.ApiKeyRoleTypeUSER, UserID: userId})
+	assert.False(t, user.Admin)
+	assert.True(t, user.CanAccessUser(userId))
+	assert.False(t, user.CanAccessUser(otherUserId))
+	assert.False(t, user.CanAccessUser(pgtype.UUID{}))
+
+	admin := NewPrincipal(&db.ApiKey{Role: db.ApiKeyRoleTypeADMIN})
+	assert.True(t, admin.Admin)
+	assert.True(t, admin.CanAccessUser(userId))
+	assert.True(t, admin.CanAccessUser(otherUserId))
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	p := &Principal{Admin: true}
+	actual, ok := FromContext(NewContext(context.Background(), p))
+	assert.True(t, ok)
+	assert.Same(t, p, actual)
+}
+
+func TestKeyFromIncomingContext(t *testing.T) {
+	_, err := KeyFromIncomingContext(context.Background())
+	assert.ErrorIs(t, err, MissingApiKeyErr)
+
+	for _, v := range []string{"", "gp_key", "Basic gp_key", "Bearer ", "Bearer   "} {
+		_, err := KeyFromIncomingContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs(AUTHORIZATION_HEADER, v)))
+		assert.ErrorIs(t, err, MissingApiKeyErr, v)
+	}
+
+	key, err := KeyFromIncomingContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs(AUTHORIZATION_HEADER, "Bearer gp_key")))
+	assert.NoError(t, err)
+	assert.Equal(t, "gp_key", key)
+}
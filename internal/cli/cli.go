@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// UnsupportedCommandErr is returned by the backends for the commands they can't run, e.g. rechecking an invoice needs the running server.
+	UnsupportedCommandErr error = errors.New("the command is not supported by the backend")
+	// InvalidCommandErr is returned for unknown commands and invalid arguments, the usage is printed along with it.
+	InvalidCommandErr error = errors.New("invalid command")
+)
+
+// Admin is implemented by the backends the CLI talks to: the gRPC admin API of the running server and the database directly.
+type Admin interface {
+	// RegisterUser registers the user with the id or a random one if it's nil, the id is returned.
+	RegisterUser(ctx context.Context, userId *string) (string, error)
+	SetXmrKeys(ctx context.Context, userId string, privViewKey string, pubSpendKey string) error
+
+	ListInvoices(ctx context.Context, req *pb_v1.ListInvoicesRequest) ([]*pb_v1.Invoice, error)
+	GetInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error)
+	ExpireInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error)
+	RecheckInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error)
+
+	GetSyncStates(ctx context.Context) ([]*pb_v1.SyncState, error)
+	// ResetSyncHeight sets the next block height to be synced, the blocks after it are synced again once the server starts.
+	ResetSyncHeight(ctx context.Context, coin pb_v1.CoinType, height uint64) (*pb_v1.SyncState, error)
+
+	// ReleaseAddresses frees the occupied addresses not used by any pending invoice, the released ones are returned.
+	ReleaseAddresses(ctx context.Context, req *pb_v1.ReleaseAddressesRequest) ([]string, error)
+
+	Close() error
+}
+
+type command struct {
+	group string
+	name  string
+	usage string
+	run   func(ctx context.Context, admin Admin, args []string, out io.Writer) error
+}
+
+var commands []command = []command{
+	{"user", "register", "[-id <uuid>]", runUserRegister},
+	{"keys", "set-xmr", "-user <uuid> -priv-view-key <key> -pub-spend-key <key>", runKeysSetXmr},
+	{"invoice", "list", "[-user <uuid>] [-coin XMR|BTC|LTC|ETH|TON] [-status PENDING|...] [-limit 100]", runInvoiceList},
+	{"invoice", "get", "<id>", runInvoiceGet},
+	{"invoice", "expire", "<id>", runInvoiceExpire},
+	{"invoice", "recheck", "<id>", runInvoiceRecheck},
+	{"sync", "show", "", runSyncShow},
+	{"sync", "reset", "-coin XMR|BTC|LTC|ETH|TON -height <block height>", runSyncReset},
+	{"address", "release", "[-coin XMR|BTC|LTC|ETH|TON] [-address <address>]", runAddressRelease},
+}
+
+// Usage prints the commands.
+func Usage(out io.Writer) {
+	fmt.Fprintln(out, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintln(out, strings.TrimSpace(fmt.Sprintf("  %v %v %v", c.group, c.name, c.usage)))
+	}
+}
+
+// Run runs the command in args, e.g. ["invoice", "get", "<id>"], against the admin backend and prints the result to out.
+func Run(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	if len(args) < 2 {
+		return InvalidCommandErr
+	}
+
+	for _, c := range commands {
+		if c.group == args[0] && c.name == args[1] {
+			return c.run(ctx, admin, args[2:], out)
+		}
+	}
+
+	return fmt.Errorf("%w: %v", InvalidCommandErr, strings.Join(args[:2], " "))
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", InvalidCommandErr, err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %v", InvalidCommandErr, fs.Args())
+	}
+
+	return nil
+}
+
+func parseId(args []string) (string, error) {
+	if len(args) != 1 || args[0] == "" {
+		return "", fmt.Errorf("%w: exactly one invoice id is expected", InvalidCommandErr)
+	}
+
+	return args[0], nil
+}
+
+func parseCoin(coin string) (pb_v1.CoinType, error) {
+	c, ok := pb_v1.CoinType_value[strings.ToUpper(coin)]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown coin %v", InvalidCommandErr, coin)
+	}
+
+	return pb_v1.CoinType(c), nil
+}
+
+func parseInvoiceStatus(status string) (pb_v1.InvoiceStatusType, error) {
+	s, ok := pb_v1.InvoiceStatusType_value[strings.ToUpper(status)]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown invoice status %v", InvalidCommandErr, status)
+	}
+
+	return pb_v1.InvoiceStatusType(s), nil
+}
+
+func printInvoice(out io.Writer, invoice *pb_v1.Invoice) error {
+	b, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", EmitUnpopulated: true}.Marshal(invoice)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+func formatTimestamp(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.AsTime().UTC().Format(time.RFC3339)
+}
+
+func runUserRegister(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	fs := newFlagSet("user register")
+	id := fs.String("id", "", "The id of the user, a random one if empty")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var userId *string
+	if *id != "" {
+		userId = id
+	}
+
+	res, err := admin.RegisterUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, res)
+	return err
+}
+
+func runKeysSetXmr(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	fs := newFlagSet("keys set-xmr")
+	userId := fs.String("user", "", "The id of the user")
+	privViewKey := fs.String("priv-view-key", "", "The XMR private view key")
+	pubSpendKey := fs.String("pub-spend-key", "", "The XMR public spend key")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *userId == "" || *privViewKey == "" || *pubSpendKey == "" {
+		return fmt.Errorf("%w: -user, -priv-view-key and -pub-spend-key are required", InvalidCommandErr)
+	}
+
+	return admin.SetXmrKeys(ctx, *userId, *privViewKey, *pubSpendKey)
+}
+
+func runInvoiceList(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	fs := newFlagSet("invoice list")
+	userId := fs.String("user", "", "Only the invoices of the user")
+	coin := fs.String("coin", "", "Only the invoices of the coin")
+	status := fs.String("status", "", "Only the invoices with the status")
+	limit := fs.Uint("limit", 0, "The number of the latest invoices, 100 if not set")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	req := &pb_v1.ListInvoicesRequest{}
+	if *userId != "" {
+		req.UserId = userId
+	}
+	if *coin != "" {
+		c, err := parseCoin(*coin)
+		if err != nil {
+			return err
+		}
+		req.Coin = &c
+	}
+	if *status != "" {
+		s, err := parseInvoiceStatus(*status)
+		if err != nil {
+			return err
+		}
+		req.Status = &s
+	}
+	if *limit != 0 {
+		l := uint32(*limit)
+		req.Limit = &l
+	}
+
+	invoices, err := admin.ListInvoices(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tCOIN\tSTATUS\tREQUIRED\tACTUAL\tADDRESS\tCREATED AT")
+	for _, i := range invoices {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", i.Id, i.UserId, i.Coin, i.Status, i.RequiredAmountAtomic, i.ActualAmountAtomic, i.CryptoAddress, formatTimestamp(i.CreatedAt))
+	}
+
+	return w.Flush()
+}
+
+func runInvoiceGet(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	id, err := parseId(args)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := admin.GetInvoice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return printInvoice(out, invoice)
+}
+
+func runInvoiceExpire(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	id, err := parseId(args)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := admin.ExpireInvoice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return printInvoice(out, invoice)
+}
+
+func runInvoiceRecheck(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	id, err := parseId(args)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := admin.RecheckInvoice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return printInvoice(out, invoice)
+}
+
+func printSyncStates(out io.Writer, states []*pb_v1.SyncState) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COIN\tLAST SYNCED BLOCK\tSYNCED AT\tSYNC LAG")
+	for _, s := range states {
+		lag := "-"
+		if s.SyncLag != nil {
+			lag = fmt.Sprint(s.GetSyncLag())
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", s.Coin, s.LastSyncedBlockHeight, formatTimestamp(s.SyncedAt), lag)
+	}
+
+	return w.Flush()
+}
+
+func runSyncShow(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	if err := parseFlags(newFlagSet("sync show"), args); err != nil {
+		return err
+	}
+
+	states, err := admin.GetSyncStates(ctx)
+	if err != nil {
+		return err
+	}
+
+	return printSyncStates(out, states)
+}
+
+func runSyncReset(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	fs := newFlagSet("sync reset")
+	coin := fs.String("coin", "", "The coin to sync again")
+	height := fs.Int64("height", -1, "The next block height to be synced")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *coin == "" || *height < 0 {
+		return fmt.Errorf("%w: -coin and a non-negative -height are required", InvalidCommandErr)
+	}
+
+	c, err := parseCoin(*coin)
+	if err != nil {
+		return err
+	}
+
+	state, err := admin.ResetSyncHeight(ctx, c, uint64(*height))
+	if err != nil {
+		return err
+	}
+
+	return printSyncStates(out, []*pb_v1.SyncState{state})
+}
+
+func runAddressRelease(ctx context.Context, admin Admin, args []string, out io.Writer) error {
+	fs := newFlagSet("address release")
+	coin := fs.String("coin", "", "Only the addresses of the coin")
+	address := fs.String("address", "", "Only the address")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	req := &pb_v1.ReleaseAddressesRequest{}
+	if *coin != "" {
+		c, err := parseCoin(*coin)
+		if err != nil {
+			return err
+		}
+		req.Coin = &c
+	}
+	if *address != "" {
+		req.Address = address
+	}
+
+	addrs, err := admin.ReleaseAddresses(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range addrs {
+		fmt.Fprintln(out, a)
+	}
+	_, err = fmt.Fprintf(out, "%v addresses have been released\n", len(addrs))
+	return err
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeAdmin records the calls and answers them with canned responses.
+type fakeAdmin struct {
+	calls []string
+
+	userId        *string
+	listReq       *pb_v1.ListInvoicesRequest
+	releaseReq    *pb_v1.ReleaseAddressesRequest
+	resetCoin     pb_v1.CoinType
+	resetHeight   uint64
+	xmrKeys       []string
+	invoice       *pb_v1.Invoice
+	states        []*pb_v1.SyncState
+	releasedAddrs []string
+	recheckErr    error
+}
+
+func (a *fakeAdmin) RegisterUser(ctx context.Context, userId *string) (string, error) {
+	a.calls = append(a.calls, "RegisterUser")
+	a.userId = userId
+	return "d2c6dbe7-41b1-4b38-a3c6-1cd4b3d0e08d", nil
+}
+
+func (a *fakeAdmin) SetXmrKeys(ctx context.Context, userId string, privViewKey string, pubSpendKey string) error {
+	a.calls = append(a.calls, "SetXmrKeys")
+	a.xmrKeys = []string{userId, privViewKey, pubSpendKey}
+	return nil
+}
+
+func (a *fakeAdmin) ListInvoices(ctx context.Context, req *pb_v1.ListInvoicesRequest) ([]*pb_v1.Invoice, error) {
+	a.calls = append(a.calls, "ListInvoices")
+	a.listReq = req
+	return []*pb_v1.Invoice{a.invoice}, nil
+}
+
+func (a *fakeAdmin) GetInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	a.calls = append(a.calls, "GetInvoice "+id)
+	return a.invoice, nil
+}
+
+func (a *fakeAdmin) ExpireInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	a.calls = append(a.calls, "ExpireInvoice "+id)
+	return a.invoice, nil
+}
+
+func (a *fakeAdmin) RecheckInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	a.calls = append(a.calls, "RecheckInvoice "+id)
+	return nil, a.recheckErr
+}
+
+func (a *fakeAdmin) GetSyncStates(ctx context.Context) ([]*pb_v1.SyncState, error) {
+	a.calls = append(a.calls, "GetSyncStates")
+	return a.states, nil
+}
+
+func (a *fakeAdmin) ResetSyncHeight(ctx context.Context, coin pb_v1.CoinType, height uint64) (*pb_v1.SyncState, error) {
+	a.calls = append(a.calls, "ResetSyncHeight")
+	a.resetCoin, a.resetHeight = coin, height
+	return &pb_v1.SyncState{Coin: coin, LastSyncedBlockHeight: height}, nil
+}
+
+func (a *fakeAdmin) ReleaseAddresses(ctx context.Context, req *pb_v1.ReleaseAddressesRequest) ([]string, error) {
+	a.calls = append(a.calls, "ReleaseAddresses")
+	a.releaseReq = req
+	return a.releasedAddrs, nil
+}
+
+func (a *fakeAdmin) Close() error {
+	return nil
+}
+
+func newFakeAdmin() *fakeAdmin {
+	syncLag := uint64(2)
+	return &fakeAdmin{
+		invoice: &pb_v1.Invoice{
+			Id:                   "8f2b4a51-0c5d-4b0e-9d8e-5b4c1f0e2a77",
+			UserId:               "d2c6dbe7-41b1-4b38-a3c6-1cd4b3d0e08d",
+			Coin:                 pb_v1.CoinType_XMR,
+			Status:               pb_v1.InvoiceStatusType_PENDING,
+			CryptoAddress:        "address",
+			RequiredAmountAtomic: "1000000000000",
+			CreatedAt:            timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		},
+		states: []*pb_v1.SyncState{
+			{Coin: pb_v1.CoinType_XMR, LastSyncedBlockHeight: 3100000, SyncLag: &syncLag},
+			{Coin: pb_v1.CoinType_BTC, LastSyncedBlockHeight: 840000},
+		},
+		releasedAddrs: []string{"address1", "address2"},
+	}
+}
+
+func run(admin Admin, args ...string) (string, error) {
+	var out bytes.Buffer
+	err := Run(context.Background(), admin, args, &out)
+	return out.String(), err
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Register User", func(t *testing.T) {
+		admin := newFakeAdmin()
+
+		out, err := run(admin, "user", "register")
+		assert.NoError(t, err)
+		assert.Nil(t, admin.userId)
+		assert.Equal(t, "d2c6dbe7-41b1-4b38-a3c6-1cd4b3d0e08d\n", out)
+
+		_, err = run(admin, "user", "register", "-id", "d2c6dbe7-41b1-4b38-a3c6-1cd4b3d0e08d")
+		assert.NoError(t, err)
+		if assert.NotNil(t, admin.userId) {
+			assert.Equal(t, "d2c6dbe7-41b1-4b38-a3c6-1cd4b3d0e08d", *admin.userId)
+		}
+	})
+
+	t.Run("Set XMR Keys", func(t *testing.T) {
+		admin := newFakeAdmin()
+
+		_, err := run(admin, "keys", "set-xmr", "-user", "id", "-priv-view-key", "view", "-pub-spend-key", "spend")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id", "view", "spend"}, admin.xmrKeys)
+
+		_, err = run(admin, "keys", "set-xmr", "-user", "id")
+		assert.ErrorIs(t, err, InvalidCommandErr)
+		assert.Equal(t, []string{"SetXmrKeys"}, admin.calls)
+	})
+
+	t.Run("List Invoices", func(t *testing.T) {
+		admin := newFakeAdmin()
+
+		out, err := run(admin, "invoice", "list", "-coin", "xmr", "-status", "PENDING", "-limit", "10")
+		assert.NoError(t, err)
+		assert.Nil(t, admin.listReq.UserId)
+		assert.Equal(t, pb_v1.CoinType_XMR, admin.listReq.GetCoin())
+		assert.Equal(t, pb_v1.InvoiceStatusType_PENDING, admin.listReq.GetStatus())
+		assert.Equal(t, uint32(10), admin.listReq.GetLimit())
+		assert.True(t, strings.HasPrefix(out, "ID "))
+		assert.Contains(t, out, "8f2b4a51-0c5d-4b0e-9d8e-5b4c1f0e2a77")
+		assert.Contains(t, out, "2024-01-02T03:04:05Z")
+
+		_, err = run(admin, "invoice", "list")
+		assert.NoError(t, err)
+		assert.Nil(t, admin.listReq.Coin)
+		assert.Nil(t, admin.listReq.Limit)
+
+		_, err = run(admin, "invoice", "list", "-coin", "DOGE")
+		assert.ErrorIs(t, err, InvalidCommandErr)
+		_, err = run(admin, "invoice", "list", "-status", "PAID")
+		assert.ErrorIs(t, err, InvalidCommandErr)
+	})
+
+	t.Run("Invoice By Id", func(t *testing.T) {
+		admin := newFakeAdmin()
+		id := admin.invoice.Id
+
+		out, err := run(admin, "invoice", "get", id)
+		assert.NoError(t, err)
+		var invoice pb_v1.Invoice
+		assert.NoError(t, protojson.Unmarshal([]byte(out), &invoice))
+		assert.True(t, proto.Equal(admin.invoice, &invoice))
+		// The zero enums are printed as well
+		assert.Contains(t, out, `"XMR"`)
+		assert.Contains(t, out, `"PENDING"`)
+
+		_, err = run(admin, "invoice", "expire", id)
+		assert.NoError(t, err)
+
+		admin.recheckErr = UnsupportedCommandErr
+		_, err = run(admin, "invoice", "recheck", id)
+		assert.ErrorIs(t, err, UnsupportedCommandErr)
+
+		_, err = run(admin, "invoice", "get")
+		assert.ErrorIs(t, err, InvalidCommandErr)
+		_, err = run(admin, "invoice", "expire", id, id)
+		assert.ErrorIs(t, err, InvalidCommandErr)
+
+		assert.Equal(t, []string{"GetInvoice " + id, "ExpireInvoice " + id, "RecheckInvoice " + id}, admin.calls)
+	})
+
+	t.Run("Sync States", func(t *testing.T) {
+		admin := newFakeAdmin()
+
+		out, err := run(admin, "sync", "show")
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if assert.Equal(t, 3, len(lines)) {
+			assert.Equal(t, []string{"XMR", "3100000", "-", "2"}, strings.Fields(lines[1]))
+			assert.Equal(t, []string{"BTC", "840000", "-", "-"}, strings.Fields(lines[2]))
+		}
+
+		_, err = run(admin, "sync", "reset", "-coin", "BTC", "-height", "830000")
+		assert.NoError(t, err)
+		assert.Equal(t, pb_v1.CoinType_BTC, admin.resetCoin)
+		assert.Equal(t, uint64(830000), admin.resetHeight)
+
+		_, err = run(admin, "sync", "reset", "-coin", "BTC")
+		assert.ErrorIs(t, err, InvalidCommandErr)
+	})
+
+	t.Run("Release Addresses", func(t *testing.T) {
+		admin := newFakeAdmin()
+
+		out, err := run(admin, "address", "release", "-coin", "BTC")
+		assert.NoError(t, err)
+		assert.Equal(t, pb_v1.CoinType_BTC, admin.releaseReq.GetCoin())
+		assert.Nil(t, admin.releaseReq.Address)
+		assert.Equal(t, "address1\naddress2\n2 addresses have been released\n", out)
+	})
+
+	t.Run("Invalid Command", func(t *testing.T) {
+		admin := newFakeAdmin()
+
+		for _, args := range [][]string{{}, {"invoice"}, {"invoice", "delete"}, {"sync", "show", "-coin", "XMR"}} {
+			_, err := run(admin, args...)
+			assert.ErrorIs(t, err, InvalidCommandErr)
+		}
+		assert.Empty(t, admin.calls)
+	})
+}
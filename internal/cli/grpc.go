@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/chekist32/goipay/internal/auth"
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type GrpcAdminConfig struct {
+	// host:port of the gRPC server
+	Server string
+	// Sent as the "authorization: Bearer <key>" metadata, it has to be an admin key if the auth is enabled
+	ApiKey string
+
+	TLS bool
+	// PEM files, the system roots are used if the CA isn't set
+	Ca string
+	// The client certificate for mutual TLS
+	Cert string
+	Key  string
+}
+
+// apiKeyCredentials attaches the api key to every call.
+type apiKeyCredentials struct {
+	key    string
+	secure bool
+}
+
+func (c *apiKeyCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{auth.AUTHORIZATION_HEADER: "Bearer " + c.key}, nil
+}
+
+func (c *apiKeyCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
+
+func newTransportCredentials(c *GrpcAdminConfig) (credentials.TransportCredentials, error) {
+	if !c.TLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	conf := &tls.Config{}
+	if c.Ca != "" {
+		caPem, err := os.ReadFile(c.Ca)
+		if err != nil {
+			return nil, err
+		}
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no certificates found in %v", c.Ca)
+		}
+	}
+	if c.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, err
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(conf), nil
+}
+
+// GrpcAdmin talks to the running server, the sync height can't be reset through it as the server keeps it in memory.
+type GrpcAdmin struct {
+	conn     *grpc.ClientConn
+	users    pb_v1.UserServiceClient
+	invoices pb_v1.InvoiceServiceClient
+	admin    pb_v1.AdminServiceClient
+}
+
+func (a *GrpcAdmin) RegisterUser(ctx context.Context, userId *string) (string, error) {
+	res, err := a.users.RegisterUser(ctx, &pb_v1.RegisterUserRequest{UserId: userId})
+	if err != nil {
+		return "", err
+	}
+
+	return res.UserId, nil
+}
+
+func (a *GrpcAdmin) SetXmrKeys(ctx context.Context, userId string, privViewKey string, pubSpendKey string) error {
+	_, err := a.users.UpdateCryptoKeys(ctx, &pb_v1.UpdateCryptoKeysRequest{
+		UserId: userId,
+		XmrReq: &pb_v1.XmrKeysUpdateRequest{PrivViewKey: privViewKey, PubSpendKey: pubSpendKey},
+	})
+	return err
+}
+
+func (a *GrpcAdmin) ListInvoices(ctx context.Context, req *pb_v1.ListInvoicesRequest) ([]*pb_v1.Invoice, error) {
+	res, err := a.admin.ListInvoices(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Invoices, nil
+}
+
+func (a *GrpcAdmin) GetInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	res, err := a.invoices.GetInvoices(ctx, &pb_v1.GetInvoicesRequest{PaymentIds: []string{id}})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Invoices) == 0 {
+		return nil, errors.New("invoice not found")
+	}
+
+	return res.Invoices[0], nil
+}
+
+func (a *GrpcAdmin) ExpireInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	res, err := a.admin.ExpireInvoice(ctx, &pb_v1.ExpireInvoiceRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Invoice, nil
+}
+
+func (a *GrpcAdmin) RecheckInvoice(ctx context.Context, id string) (*pb_v1.Invoice, error) {
+	res, err := a.admin.RecheckInvoice(ctx, &pb_v1.RecheckInvoiceRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Invoice, nil
+}
+
+func (a *GrpcAdmin) GetSyncStates(ctx context.Context) ([]*pb_v1.SyncState, error) {
+	res, err := a.admin.GetSyncStates(ctx, &pb_v1.GetSyncStatesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.States, nil
+}
+
+func (a *GrpcAdmin) ResetSyncHeight(ctx context.Context, coin pb_v1.CoinType, height uint64) (*pb_v1.SyncState, error) {
+	return nil, fmt.Errorf("%w: the running server would overwrite the height, stop it and use -config", UnsupportedCommandErr)
+}
+
+func (a *GrpcAdmin) ReleaseAddresses(ctx context.Context, req *pb_v1.ReleaseAddressesRequest) ([]string, error) {
+	res, err := a.admin.ReleaseAddresses(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Addresses, nil
+}
+
+func (a *GrpcAdmin) Close() error {
+	return a.conn.Close()
+}
+
+func NewGrpcAdmin(c *GrpcAdminConfig) (*GrpcAdmin, error) {
+	creds, err := newTransportCredentials(c)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if c.ApiKey != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&apiKeyCredentials{key: c.ApiKey, secure: c.TLS}))
+	}
+
+	conn, err := grpc.NewClient(c.Server, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GrpcAdmin{
+		conn:     conn,
+		users:    pb_v1.NewUserServiceClient(conn),
+		invoices: pb_v1.NewInvoiceServiceClient(conn),
+		admin:    pb_v1.NewAdminServiceClient(conn),
+	}, nil
+}
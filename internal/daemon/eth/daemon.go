@@ -0,0 +1,128 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DEFAULT_RPC_TIMEOUT time.Duration = 30 * time.Second
+)
+
+type IDaemonRpcClient interface {
+	// eth_chainId
+	ChainId() (uint64, error)
+	// eth_blockNumber
+	BlockNumber() (uint64, error)
+	// eth_getBlockByNumber (with full txs), nil if the block doesn't exist yet
+	GetBlockByNumber(number uint64) (*Block, error)
+	// eth_getTransactionByHash, nil if the tx is unknown
+	GetTransactionByHash(hash string) (*Tx, error)
+	// eth_getTransactionReceipt, nil if the tx isn't mined
+	GetTransactionReceipt(hash string) (*Receipt, error)
+	// eth_getLogs
+	GetLogs(filter *LogFilter) ([]Log, error)
+}
+
+type RpcConnection struct {
+	host     *url.URL
+	username string
+	password string
+}
+
+func NewRpcConnection(host *url.URL, username string, password string) *RpcConnection {
+	return &RpcConnection{host: host, username: username, password: password}
+}
+
+type DaemonRpcClient struct {
+	connData RpcConnection
+	httpcl   *http.Client
+}
+
+func getResultFromDaemonRpc[R any](c *DaemonRpcClient, method string, params ...any) (R, error) {
+	var result R
+
+	if params == nil {
+		params = []any{}
+	}
+	data, err := json.Marshal(&JsonRpcRequestBody{Jsonrpc: "2.0", Id: 1, Method: method, Params: params})
+	if err != nil {
+		return result, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.connData.host.String(), bytes.NewReader(data))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	if c.connData.username != "" || c.connData.password != "" {
+		req.SetBasicAuth(c.connData.username, c.connData.password)
+	}
+
+	res, err := c.httpcl.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer res.Body.Close()
+
+	var body JsonRpcGenericResponse[R]
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		if res.StatusCode >= 400 {
+			return result, errors.New(res.Status)
+		}
+		return result, err
+	}
+	if body.Error != nil {
+		return result, body.Error
+	}
+
+	return body.Result, nil
+}
+
+func toHexQuantity(v uint64) string {
+	return "0x" + strconv.FormatUint(v, 16)
+}
+
+func NewDaemonRpcClient(connection *RpcConnection) IDaemonRpcClient {
+	return &DaemonRpcClient{
+		connData: *connection,
+		httpcl:   &http.Client{Timeout: DEFAULT_RPC_TIMEOUT},
+	}
+}
+
+// eth_chainId
+func (c *DaemonRpcClient) ChainId() (uint64, error) {
+	res, err := getResultFromDaemonRpc[Quantity](c, "eth_chainId")
+	return uint64(res), err
+}
+
+// eth_blockNumber
+func (c *DaemonRpcClient) BlockNumber() (uint64, error) {
+	res, err := getResultFromDaemonRpc[Quantity](c, "eth_blockNumber")
+	return uint64(res), err
+}
+
+// eth_getBlockByNumber
+func (c *DaemonRpcClient) GetBlockByNumber(number uint64) (*Block, error) {
+	return getResultFromDaemonRpc[*Block](c, "eth_getBlockByNumber", toHexQuantity(number), true)
+}
+
+// eth_getTransactionByHash
+func (c *DaemonRpcClient) GetTransactionByHash(hash string) (*Tx, error) {
+	return getResultFromDaemonRpc[*Tx](c, "eth_getTransactionByHash", hash)
+}
+
+// eth_getTransactionReceipt
+func (c *DaemonRpcClient) GetTransactionReceipt(hash string) (*Receipt, error) {
+	return getResultFromDaemonRpc[*Receipt](c, "eth_getTransactionReceipt", hash)
+}
+
+// eth_getLogs
+func (c *DaemonRpcClient) GetLogs(filter *LogFilter) ([]Log, error) {
+	return getResultFromDaemonRpc[[]Log](c, "eth_getLogs", filter)
+}
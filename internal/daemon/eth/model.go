@@ -0,0 +1,129 @@
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	TX_RECEIPT_STATUS_SUCCESSFUL uint64 = 1
+
+	// keccak256("Transfer(address,address,uint256)")
+	ERC20_TRANSFER_EVENT_TOPIC string = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+var (
+	invalidQuantityErr error = errors.New("invalid hex quantity")
+)
+
+type JsonRpcRequestBody struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Id      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type JsonRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *JsonRpcError) Error() string {
+	return fmt.Sprintf("eth rpc error %v: %v", e.Code, e.Message)
+}
+
+type JsonRpcGenericResponse[T any] struct {
+	Id     int           `json:"id"`
+	Result T             `json:"result"`
+	Error  *JsonRpcError `json:"error"`
+}
+
+func trimHexQuantity(data []byte) (string, error) {
+	str := string(bytes.Trim(data, `"`))
+	if !strings.HasPrefix(str, "0x") || len(str) < 3 {
+		return "", invalidQuantityErr
+	}
+
+	return str[2:], nil
+}
+
+// Quantity is a hex encoded unsigned integer, e.g. a block number.
+type Quantity uint64
+
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	str, err := trimHexQuantity(data)
+	if err != nil {
+		return err
+	}
+
+	v, err := strconv.ParseUint(str, 16, 64)
+	if err != nil {
+		return err
+	}
+
+	*q = Quantity(v)
+	return nil
+}
+
+// BigQuantity is a hex encoded unsigned integer which might not fit into uint64, e.g. a value in wei.
+type BigQuantity struct {
+	big.Int
+}
+
+func (q *BigQuantity) UnmarshalJSON(data []byte) error {
+	str, err := trimHexQuantity(data)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := q.SetString(str, 16); !ok {
+		return invalidQuantityErr
+	}
+
+	return nil
+}
+
+type Tx struct {
+	Hash string `json:"hash"`
+	From string `json:"from"`
+	// Nil for contract creation txs
+	To        *string     `json:"to"`
+	Value     BigQuantity `json:"value"`
+	BlockHash *string     `json:"blockHash"`
+	// Nil for pending txs
+	BlockNumber *Quantity `json:"blockNumber"`
+}
+
+type Block struct {
+	Hash         string   `json:"hash"`
+	ParentHash   string   `json:"parentHash"`
+	Number       Quantity `json:"number"`
+	Transactions []Tx     `json:"transactions"`
+}
+
+type Receipt struct {
+	TransactionHash string   `json:"transactionHash"`
+	BlockHash       string   `json:"blockHash"`
+	BlockNumber     Quantity `json:"blockNumber"`
+	Status          Quantity `json:"status"`
+}
+
+type LogFilter struct {
+	BlockHash string     `json:"blockHash"`
+	Address   []string   `json:"address"`
+	Topics    [][]string `json:"topics"`
+}
+
+type Log struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     Quantity `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+	LogIndex        Quantity `json:"logIndex"`
+	Removed         bool     `json:"removed"`
+}
@@ -0,0 +1,98 @@
+package ton
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DEFAULT_RPC_TIMEOUT time.Duration = 30 * time.Second
+)
+
+// IDaemonRpcClient is a client of a toncenter-compatible HTTP API (v2).
+type IDaemonRpcClient interface {
+	// getMasterchainInfo
+	GetMasterchainInfo() (*MasterchainInfo, error)
+	// getTransactions, the transactions of the account from the one with lt and hash (inclusive) back in time.
+	// The latest ones are returned if lt is 0.
+	GetTransactions(address string, limit uint32, lt uint64, hash string) ([]Transaction, error)
+}
+
+type RpcConnection struct {
+	host   *url.URL
+	apiKey string
+}
+
+func NewRpcConnection(host *url.URL, apiKey string) *RpcConnection {
+	return &RpcConnection{host: host, apiKey: apiKey}
+}
+
+type DaemonRpcClient struct {
+	connData RpcConnection
+	httpcl   *http.Client
+}
+
+func getResultFromDaemonRpc[R any](c *DaemonRpcClient, method string, params url.Values) (R, error) {
+	var result R
+
+	u := c.connData.host.JoinPath(method)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return result, err
+	}
+	if c.connData.apiKey != "" {
+		req.Header.Add("X-API-Key", c.connData.apiKey)
+	}
+
+	res, err := c.httpcl.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer res.Body.Close()
+
+	var body ApiGenericResponse[R]
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		if res.StatusCode >= 400 {
+			return result, errors.New(res.Status)
+		}
+		return result, err
+	}
+	if !body.Ok {
+		return result, &ApiError{Code: body.Code, Message: body.Error}
+	}
+
+	return body.Result, nil
+}
+
+func NewDaemonRpcClient(connection *RpcConnection) IDaemonRpcClient {
+	return &DaemonRpcClient{
+		connData: *connection,
+		httpcl:   &http.Client{Timeout: DEFAULT_RPC_TIMEOUT},
+	}
+}
+
+// getMasterchainInfo
+func (c *DaemonRpcClient) GetMasterchainInfo() (*MasterchainInfo, error) {
+	return getResultFromDaemonRpc[*MasterchainInfo](c, "getMasterchainInfo", url.Values{})
+}
+
+// getTransactions
+func (c *DaemonRpcClient) GetTransactions(address string, limit uint32, lt uint64, hash string) ([]Transaction, error) {
+	params := url.Values{
+		"address":  {address},
+		"limit":    {strconv.FormatUint(uint64(limit), 10)},
+		"archival": {"false"},
+	}
+	if lt != 0 {
+		params.Set("lt", strconv.FormatUint(lt, 10))
+		params.Set("hash", hash)
+	}
+
+	return getResultFromDaemonRpc[[]Transaction](c, "getTransactions", params)
+}
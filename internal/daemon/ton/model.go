@@ -0,0 +1,52 @@
+package ton
+
+import "fmt"
+
+type ApiError struct {
+	Code    int
+	Message string
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf("ton api error %v: %v", e.Code, e.Message)
+}
+
+type ApiGenericResponse[T any] struct {
+	Ok     bool   `json:"ok"`
+	Result T      `json:"result"`
+	Error  string `json:"error"`
+	Code   int    `json:"code"`
+}
+
+type BlockId struct {
+	Workchain int32  `json:"workchain"`
+	Shard     string `json:"shard"`
+	Seqno     uint64 `json:"seqno"`
+	RootHash  string `json:"root_hash"`
+	FileHash  string `json:"file_hash"`
+}
+
+type MasterchainInfo struct {
+	Last BlockId `json:"last"`
+}
+
+type TransactionId struct {
+	Lt   uint64 `json:"lt,string"`
+	Hash string `json:"hash"`
+}
+
+type Message struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	// Nanotons
+	Value uint64 `json:"value,string"`
+	// Text comment of the message, empty if the body isn't a text comment
+	Message string `json:"message"`
+}
+
+type Transaction struct {
+	Utime         int64         `json:"utime"`
+	TransactionId TransactionId `json:"transaction_id"`
+	InMsg         *Message      `json:"in_msg"`
+	OutMsgs       []Message     `json:"out_msgs"`
+}
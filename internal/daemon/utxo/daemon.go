@@ -0,0 +1,149 @@
+package utxo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DEFAULT_RPC_TIMEOUT time.Duration = 30 * time.Second
+)
+
+type IDaemonRpcClient interface {
+	// getblockchaininfo
+	GetBlockchainInfo() (*GetBlockchainInfoResult, error)
+	// getindexinfo
+	GetIndexInfo() (map[string]IndexInfo, error)
+	// getblockcount
+	GetBlockCount() (uint64, error)
+	// getblockhash
+	GetBlockHash(height uint64) (string, error)
+	// getblock (verbosity 2)
+	GetBlock(hash string) (*Block, error)
+	// getrawmempool
+	GetRawMempool() ([]string, error)
+	// getrawtransaction (verbose)
+	GetRawTransaction(txId string) (*Tx, error)
+}
+
+type RpcConnection struct {
+	host     *url.URL
+	username string
+	password string
+}
+
+func NewRpcConnection(host *url.URL, username string, password string) *RpcConnection {
+	return &RpcConnection{host: host, username: username, password: password}
+}
+
+type DaemonRpcClient struct {
+	connData RpcConnection
+	httpcl   *http.Client
+}
+
+func getResultFromDaemonRpc[R any](c *DaemonRpcClient, method string, params ...any) (R, error) {
+	var result R
+
+	if params == nil {
+		params = []any{}
+	}
+	data, err := json.Marshal(&JsonRpcRequestBody{Jsonrpc: "1.0", Id: "goipay", Method: method, Params: params})
+	if err != nil {
+		return result, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.connData.host.String(), bytes.NewReader(data))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	if c.connData.username != "" || c.connData.password != "" {
+		req.SetBasicAuth(c.connData.username, c.connData.password)
+	}
+
+	res, err := c.httpcl.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer res.Body.Close()
+
+	// bitcoind (and its forks) report RPC errors with 404/500 status codes along with a JSON body
+	var body JsonRpcGenericResponse[R]
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		if res.StatusCode >= 400 {
+			return result, errors.New(res.Status)
+		}
+		return result, err
+	}
+	if body.Error != nil {
+		return result, body.Error
+	}
+
+	return body.Result, nil
+}
+
+func NewDaemonRpcClient(connection *RpcConnection) IDaemonRpcClient {
+	return &DaemonRpcClient{
+		connData: *connection,
+		httpcl:   &http.Client{Timeout: DEFAULT_RPC_TIMEOUT},
+	}
+}
+
+// getblockchaininfo
+func (c *DaemonRpcClient) GetBlockchainInfo() (*GetBlockchainInfoResult, error) {
+	res, err := getResultFromDaemonRpc[GetBlockchainInfoResult](c, "getblockchaininfo")
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// getindexinfo
+func (c *DaemonRpcClient) GetIndexInfo() (map[string]IndexInfo, error) {
+	return getResultFromDaemonRpc[map[string]IndexInfo](c, "getindexinfo")
+}
+
+// getblockcount
+func (c *DaemonRpcClient) GetBlockCount() (uint64, error) {
+	return getResultFromDaemonRpc[uint64](c, "getblockcount")
+}
+
+// getblockhash
+func (c *DaemonRpcClient) GetBlockHash(height uint64) (string, error) {
+	return getResultFromDaemonRpc[string](c, "getblockhash", height)
+}
+
+// getblock (verbosity 2)
+func (c *DaemonRpcClient) GetBlock(hash string) (*Block, error) {
+	res, err := getResultFromDaemonRpc[Block](c, "getblock", hash, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// getrawmempool
+func (c *DaemonRpcClient) GetRawMempool() ([]string, error) {
+	return getResultFromDaemonRpc[[]string](c, "getrawmempool")
+}
+
+// getrawtransaction (verbose)
+func (c *DaemonRpcClient) GetRawTransaction(txId string) (*Tx, error) {
+	res, err := getResultFromDaemonRpc[Tx](c, "getrawtransaction", txId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func IsRpcErrorCode(err error, code int) bool {
+	var rpcErr *JsonRpcError
+	return errors.As(err, &rpcErr) && rpcErr.Code == code
+}
@@ -0,0 +1,123 @@
+package utxo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	SATOSHIS_PER_COIN uint64 = 100_000_000
+
+	// bitcoind RPC_INVALID_ADDRESS_OR_KEY, e.g. "No such mempool or blockchain transaction"
+	RPC_INVALID_ADDRESS_OR_KEY int = -5
+)
+
+var (
+	invalidAmountErr error = errors.New("invalid amount")
+)
+
+type JsonRpcRequestBody struct {
+	Jsonrpc string `json:"jsonrpc"`
+	Id      string `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type JsonRpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *JsonRpcError) Error() string {
+	return fmt.Sprintf("utxo daemon rpc error %v: %v", e.Code, e.Message)
+}
+
+type JsonRpcGenericResponse[T any] struct {
+	Id     string        `json:"id"`
+	Result T             `json:"result"`
+	Error  *JsonRpcError `json:"error"`
+}
+
+// Amount is a value in satoshis. It is decoded straight from the decimal
+// representation returned by the daemon to avoid float64 rounding.
+type Amount uint64
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	str := string(bytes.Trim(data, `"`))
+	if strings.HasPrefix(str, "-") {
+		return invalidAmountErr
+	}
+
+	whole, frac, _ := strings.Cut(str, ".")
+	if len(frac) > 8 {
+		return invalidAmountErr
+	}
+	frac += strings.Repeat("0", 8-len(frac))
+
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return err
+	}
+	f, err := strconv.ParseUint(frac, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*a = Amount(w*SATOSHIS_PER_COIN + f)
+	return nil
+}
+
+type GetBlockchainInfoResult struct {
+	Chain         string `json:"chain"`
+	Blocks        uint64 `json:"blocks"`
+	Headers       uint64 `json:"headers"`
+	BestBlockHash string `json:"bestblockhash"`
+}
+
+type IndexInfo struct {
+	Synced          bool   `json:"synced"`
+	BestBlockHeight uint64 `json:"best_block_height"`
+}
+
+type ScriptPubKey struct {
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	// Deprecated by bitcoind in favor of Address, still returned by older versions.
+	Addresses []string `json:"addresses"`
+}
+
+type Vout struct {
+	Value        Amount       `json:"value"`
+	N            uint32       `json:"n"`
+	ScriptPubKey ScriptPubKey `json:"scriptPubKey"`
+}
+
+func (v *Vout) Address() string {
+	if v.ScriptPubKey.Address != "" {
+		return v.ScriptPubKey.Address
+	}
+	if len(v.ScriptPubKey.Addresses) == 1 {
+		return v.ScriptPubKey.Addresses[0]
+	}
+
+	return ""
+}
+
+type Tx struct {
+	TxId          string `json:"txid"`
+	Hash          string `json:"hash"`
+	Vout          []Vout `json:"vout"`
+	BlockHash     string `json:"blockhash"`
+	Confirmations uint64 `json:"confirmations"`
+}
+
+type Block struct {
+	Hash              string `json:"hash"`
+	Height            uint64 `json:"height"`
+	PreviousBlockHash string `json:"previousblockhash"`
+	Confirmations     int64  `json:"confirmations"`
+	Tx                []Tx   `json:"tx"`
+}
@@ -0,0 +1,97 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: api_key.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createApiKey = `-- name: CreateApiKey :one
+INSERT INTO api_keys(key_hash, role, user_id) VALUES ($1, $2, $3)
+RETURNING id, key_hash, role, user_id, created_at, revoked_at
+`
+
+type CreateApiKeyParams struct {
+	KeyHash string
+	Role    ApiKeyRoleType
+	UserID  pgtype.UUID
+}
+
+func (q *Queries) CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, createApiKey, arg.KeyHash, arg.Role, arg.UserID)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.KeyHash,
+		&i.Role,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const findActiveApiKeyByIdForUpdate = `-- name: FindActiveApiKeyByIdForUpdate :one
+SELECT id, key_hash, role, user_id, created_at, revoked_at FROM api_keys
+WHERE id = $1 AND revoked_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) FindActiveApiKeyByIdForUpdate(ctx context.Context, id pgtype.UUID) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, findActiveApiKeyByIdForUpdate, id)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.KeyHash,
+		&i.Role,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const findActiveApiKeyByKeyHash = `-- name: FindActiveApiKeyByKeyHash :one
+SELECT id, key_hash, role, user_id, created_at, revoked_at FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) FindActiveApiKeyByKeyHash(ctx context.Context, keyHash string) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, findActiveApiKeyByKeyHash, keyHash)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.KeyHash,
+		&i.Role,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const revokeApiKeyById = `-- name: RevokeApiKeyById :one
+UPDATE api_keys
+SET revoked_at = timezone('UTC', now())
+WHERE id = $1 AND revoked_at IS NULL
+RETURNING id, key_hash, role, user_id, created_at, revoked_at
+`
+
+func (q *Queries) RevokeApiKeyById(ctx context.Context, id pgtype.UUID) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, revokeApiKeyById, id)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.KeyHash,
+		&i.Role,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
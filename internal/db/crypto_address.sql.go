@@ -107,6 +107,54 @@ func (q *Queries) FindNonOccupiedCryptoAddressAndLockByUserIdAndCoin(ctx context
 	return i, err
 }
 
+const releaseStuckCryptoAddresses = `-- name: ReleaseStuckCryptoAddresses :many
+UPDATE crypto_addresses AS ca
+SET is_occupied = false
+WHERE ca.is_occupied = true
+    AND ($1::coin_type IS NULL OR ca.coin = $1)
+    AND ($2::text IS NULL OR ca.address = $2)
+    AND NOT EXISTS (
+        SELECT 1 FROM invoices AS i
+        WHERE i.crypto_address = ca.address AND (
+            i.status IN ('PENDING', 'PARTIALLY_PAID', 'PENDING_MEMPOOL', 'PAID_AFTER_EXPIRY')
+            OR (i.status = 'EXPIRED' AND i.expired_at > $3)
+        )
+    )
+RETURNING id, address, coin, is_occupied, user_id
+`
+
+type ReleaseStuckCryptoAddressesParams struct {
+	Coin         NullCoinType
+	Address      pgtype.Text
+	ExpiredSince pgtype.Timestamptz
+}
+
+func (q *Queries) ReleaseStuckCryptoAddresses(ctx context.Context, arg ReleaseStuckCryptoAddressesParams) ([]CryptoAddress, error) {
+	rows, err := q.db.Query(ctx, releaseStuckCryptoAddresses, arg.Coin, arg.Address, arg.ExpiredSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CryptoAddress
+	for rows.Next() {
+		var i CryptoAddress
+		if err := rows.Scan(
+			&i.ID,
+			&i.Address,
+			&i.Coin,
+			&i.IsOccupied,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateIsOccupiedByCryptoAddress = `-- name: UpdateIsOccupiedByCryptoAddress :one
 UPDATE crypto_addresses 
 SET is_occupied = $2
@@ -11,6 +11,31 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const findAllCryptoCaches = `-- name: FindAllCryptoCaches :many
+SELECT coin, last_synced_block_height, synced_timestamp FROM crypto_cache
+ORDER BY coin
+`
+
+func (q *Queries) FindAllCryptoCaches(ctx context.Context) ([]CryptoCache, error) {
+	rows, err := q.db.Query(ctx, findAllCryptoCaches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CryptoCache
+	for rows.Next() {
+		var i CryptoCache
+		if err := rows.Scan(&i.Coin, &i.LastSyncedBlockHeight, &i.SyncedTimestamp); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findCryptoCacheByCoin = `-- name: FindCryptoCacheByCoin :one
 SELECT coin, last_synced_block_height, synced_timestamp FROM crypto_cache
 WHERE coin = $1
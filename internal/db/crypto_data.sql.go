@@ -11,9 +11,27 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createBTCCryptoData = `-- name: CreateBTCCryptoData :one
+INSERT INTO btc_crypto_data(master_pub_key) VALUES ($1)
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+// BTC
+func (q *Queries) CreateBTCCryptoData(ctx context.Context, masterPubKey string) (BtcCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, createBTCCryptoData, masterPubKey)
+	var i BtcCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
 const createCryptoData = `-- name: CreateCryptoData :one
 INSERT INTO crypto_data(xmr_id, user_id) VALUES ($1, $2)
-RETURNING user_id, xmr_id
+RETURNING user_id, xmr_id, btc_id, ltc_id, eth_id, ton_id
 `
 
 type CreateCryptoDataParams struct {
@@ -24,7 +42,63 @@ type CreateCryptoDataParams struct {
 func (q *Queries) CreateCryptoData(ctx context.Context, arg CreateCryptoDataParams) (CryptoDatum, error) {
 	row := q.db.QueryRow(ctx, createCryptoData, arg.XmrID, arg.UserID)
 	var i CryptoDatum
-	err := row.Scan(&i.UserID, &i.XmrID)
+	err := row.Scan(
+		&i.UserID,
+		&i.XmrID,
+		&i.BtcID,
+		&i.LtcID,
+		&i.EthID,
+		&i.TonID,
+	)
+	return i, err
+}
+
+const createETHCryptoData = `-- name: CreateETHCryptoData :one
+INSERT INTO eth_crypto_data(master_pub_key) VALUES ($1)
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+// ETH
+func (q *Queries) CreateETHCryptoData(ctx context.Context, masterPubKey string) (EthCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, createETHCryptoData, masterPubKey)
+	var i EthCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
+const createLTCCryptoData = `-- name: CreateLTCCryptoData :one
+INSERT INTO ltc_crypto_data(master_pub_key) VALUES ($1)
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+// LTC
+func (q *Queries) CreateLTCCryptoData(ctx context.Context, masterPubKey string) (LtcCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, createLTCCryptoData, masterPubKey)
+	var i LtcCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
+const createTONCryptoData = `-- name: CreateTONCryptoData :one
+INSERT INTO ton_crypto_data(address) VALUES ($1)
+RETURNING id, address
+`
+
+// TON
+func (q *Queries) CreateTONCryptoData(ctx context.Context, address string) (TonCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, createTONCryptoData, address)
+	var i TonCryptoDatum
+	err := row.Scan(&i.ID, &i.Address)
 	return i, err
 }
 
@@ -52,36 +126,164 @@ func (q *Queries) CreateXMRCryptoData(ctx context.Context, arg CreateXMRCryptoDa
 	return i, err
 }
 
+const findAddressAndLockTONCryptoDataById = `-- name: FindAddressAndLockTONCryptoDataById :one
+SELECT address
+FROM ton_crypto_data
+WHERE id = $1
+FOR SHARE
+`
+
+func (q *Queries) FindAddressAndLockTONCryptoDataById(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, findAddressAndLockTONCryptoDataById, id)
+	var address string
+	err := row.Scan(&address)
+	return address, err
+}
+
+const findAllPrivViewKeysAndLockXMRCryptoData = `-- name: FindAllPrivViewKeysAndLockXMRCryptoData :many
+SELECT id, priv_view_key
+FROM xmr_crypto_data
+FOR UPDATE
+`
+
+type FindAllPrivViewKeysAndLockXMRCryptoDataRow struct {
+	ID          pgtype.UUID
+	PrivViewKey string
+}
+
+func (q *Queries) FindAllPrivViewKeysAndLockXMRCryptoData(ctx context.Context) ([]FindAllPrivViewKeysAndLockXMRCryptoDataRow, error) {
+	rows, err := q.db.Query(ctx, findAllPrivViewKeysAndLockXMRCryptoData)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindAllPrivViewKeysAndLockXMRCryptoDataRow
+	for rows.Next() {
+		var i FindAllPrivViewKeysAndLockXMRCryptoDataRow
+		if err := rows.Scan(&i.ID, &i.PrivViewKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findCryptoDataByUserId = `-- name: FindCryptoDataByUserId :one
-SELECT user_id, xmr_id FROM crypto_data 
+SELECT user_id, xmr_id, btc_id, ltc_id, eth_id, ton_id FROM crypto_data 
 WHERE user_id = $1
 `
 
 func (q *Queries) FindCryptoDataByUserId(ctx context.Context, userID pgtype.UUID) (CryptoDatum, error) {
 	row := q.db.QueryRow(ctx, findCryptoDataByUserId, userID)
 	var i CryptoDatum
-	err := row.Scan(&i.UserID, &i.XmrID)
+	err := row.Scan(
+		&i.UserID,
+		&i.XmrID,
+		&i.BtcID,
+		&i.LtcID,
+		&i.EthID,
+		&i.TonID,
+	)
 	return i, err
 }
 
 const findCryptoKeysByUserId = `-- name: FindCryptoKeysByUserId :one
 SELECT 
     COALESCE(xmr.priv_view_key, '') AS priv_view_key, 
-    COALESCE(xmr.pub_spend_key, '') AS pub_spend_key
+    COALESCE(xmr.pub_spend_key, '') AS pub_spend_key,
+    COALESCE(btc.master_pub_key, '') AS btc_master_pub_key,
+    COALESCE(ltc.master_pub_key, '') AS ltc_master_pub_key,
+    COALESCE(eth.master_pub_key, '') AS eth_master_pub_key,
+    COALESCE(ton.address, '') AS ton_address
 FROM crypto_data as cd
 LEFT JOIN xmr_crypto_data as xmr ON cd.xmr_id = xmr.id
+LEFT JOIN btc_crypto_data as btc ON cd.btc_id = btc.id
+LEFT JOIN ltc_crypto_data as ltc ON cd.ltc_id = ltc.id
+LEFT JOIN eth_crypto_data as eth ON cd.eth_id = eth.id
+LEFT JOIN ton_crypto_data as ton ON cd.ton_id = ton.id
 WHERE cd.user_id = $1
 `
 
 type FindCryptoKeysByUserIdRow struct {
-	PrivViewKey string
-	PubSpendKey string
+	PrivViewKey     string
+	PubSpendKey     string
+	BtcMasterPubKey string
+	LtcMasterPubKey string
+	EthMasterPubKey string
+	TonAddress      string
 }
 
 func (q *Queries) FindCryptoKeysByUserId(ctx context.Context, userID pgtype.UUID) (FindCryptoKeysByUserIdRow, error) {
 	row := q.db.QueryRow(ctx, findCryptoKeysByUserId, userID)
 	var i FindCryptoKeysByUserIdRow
-	err := row.Scan(&i.PrivViewKey, &i.PubSpendKey)
+	err := row.Scan(
+		&i.PrivViewKey,
+		&i.PubSpendKey,
+		&i.BtcMasterPubKey,
+		&i.LtcMasterPubKey,
+		&i.EthMasterPubKey,
+		&i.TonAddress,
+	)
+	return i, err
+}
+
+const findIndicesAndLockBTCCryptoDataById = `-- name: FindIndicesAndLockBTCCryptoDataById :one
+SELECT last_major_index, last_minor_index 
+FROM btc_crypto_data
+WHERE id = $1
+FOR UPDATE
+`
+
+type FindIndicesAndLockBTCCryptoDataByIdRow struct {
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+func (q *Queries) FindIndicesAndLockBTCCryptoDataById(ctx context.Context, id pgtype.UUID) (FindIndicesAndLockBTCCryptoDataByIdRow, error) {
+	row := q.db.QueryRow(ctx, findIndicesAndLockBTCCryptoDataById, id)
+	var i FindIndicesAndLockBTCCryptoDataByIdRow
+	err := row.Scan(&i.LastMajorIndex, &i.LastMinorIndex)
+	return i, err
+}
+
+const findIndicesAndLockETHCryptoDataById = `-- name: FindIndicesAndLockETHCryptoDataById :one
+SELECT last_major_index, last_minor_index 
+FROM eth_crypto_data
+WHERE id = $1
+FOR UPDATE
+`
+
+type FindIndicesAndLockETHCryptoDataByIdRow struct {
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+func (q *Queries) FindIndicesAndLockETHCryptoDataById(ctx context.Context, id pgtype.UUID) (FindIndicesAndLockETHCryptoDataByIdRow, error) {
+	row := q.db.QueryRow(ctx, findIndicesAndLockETHCryptoDataById, id)
+	var i FindIndicesAndLockETHCryptoDataByIdRow
+	err := row.Scan(&i.LastMajorIndex, &i.LastMinorIndex)
+	return i, err
+}
+
+const findIndicesAndLockLTCCryptoDataById = `-- name: FindIndicesAndLockLTCCryptoDataById :one
+SELECT last_major_index, last_minor_index 
+FROM ltc_crypto_data
+WHERE id = $1
+FOR UPDATE
+`
+
+type FindIndicesAndLockLTCCryptoDataByIdRow struct {
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+func (q *Queries) FindIndicesAndLockLTCCryptoDataById(ctx context.Context, id pgtype.UUID) (FindIndicesAndLockLTCCryptoDataByIdRow, error) {
+	row := q.db.QueryRow(ctx, findIndicesAndLockLTCCryptoDataById, id)
+	var i FindIndicesAndLockLTCCryptoDataByIdRow
+	err := row.Scan(&i.LastMajorIndex, &i.LastMinorIndex)
 	return i, err
 }
 
@@ -104,6 +306,48 @@ func (q *Queries) FindIndicesAndLockXMRCryptoDataById(ctx context.Context, id pg
 	return i, err
 }
 
+const findKeysAndLockBTCCryptoDataById = `-- name: FindKeysAndLockBTCCryptoDataById :one
+SELECT master_pub_key
+FROM btc_crypto_data
+WHERE id = $1
+FOR SHARE
+`
+
+func (q *Queries) FindKeysAndLockBTCCryptoDataById(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, findKeysAndLockBTCCryptoDataById, id)
+	var master_pub_key string
+	err := row.Scan(&master_pub_key)
+	return master_pub_key, err
+}
+
+const findKeysAndLockETHCryptoDataById = `-- name: FindKeysAndLockETHCryptoDataById :one
+SELECT master_pub_key
+FROM eth_crypto_data
+WHERE id = $1
+FOR SHARE
+`
+
+func (q *Queries) FindKeysAndLockETHCryptoDataById(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, findKeysAndLockETHCryptoDataById, id)
+	var master_pub_key string
+	err := row.Scan(&master_pub_key)
+	return master_pub_key, err
+}
+
+const findKeysAndLockLTCCryptoDataById = `-- name: FindKeysAndLockLTCCryptoDataById :one
+SELECT master_pub_key
+FROM ltc_crypto_data
+WHERE id = $1
+FOR SHARE
+`
+
+func (q *Queries) FindKeysAndLockLTCCryptoDataById(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, findKeysAndLockLTCCryptoDataById, id)
+	var master_pub_key string
+	err := row.Scan(&master_pub_key)
+	return master_pub_key, err
+}
+
 const findKeysAndLockXMRCryptoDataById = `-- name: FindKeysAndLockXMRCryptoDataById :one
 SELECT priv_view_key, pub_spend_key
 FROM xmr_crypto_data
@@ -123,11 +367,115 @@ func (q *Queries) FindKeysAndLockXMRCryptoDataById(ctx context.Context, id pgtyp
 	return i, err
 }
 
+const setBTCCryptoDataByUserId = `-- name: SetBTCCryptoDataByUserId :one
+UPDATE crypto_data
+SET btc_id = $2 
+WHERE user_id = $1
+RETURNING user_id, xmr_id, btc_id, ltc_id, eth_id, ton_id
+`
+
+type SetBTCCryptoDataByUserIdParams struct {
+	UserID pgtype.UUID
+	BtcID  pgtype.UUID
+}
+
+func (q *Queries) SetBTCCryptoDataByUserId(ctx context.Context, arg SetBTCCryptoDataByUserIdParams) (CryptoDatum, error) {
+	row := q.db.QueryRow(ctx, setBTCCryptoDataByUserId, arg.UserID, arg.BtcID)
+	var i CryptoDatum
+	err := row.Scan(
+		&i.UserID,
+		&i.XmrID,
+		&i.BtcID,
+		&i.LtcID,
+		&i.EthID,
+		&i.TonID,
+	)
+	return i, err
+}
+
+const setETHCryptoDataByUserId = `-- name: SetETHCryptoDataByUserId :one
+UPDATE crypto_data
+SET eth_id = $2 
+WHERE user_id = $1
+RETURNING user_id, xmr_id, btc_id, ltc_id, eth_id, ton_id
+`
+
+type SetETHCryptoDataByUserIdParams struct {
+	UserID pgtype.UUID
+	EthID  pgtype.UUID
+}
+
+func (q *Queries) SetETHCryptoDataByUserId(ctx context.Context, arg SetETHCryptoDataByUserIdParams) (CryptoDatum, error) {
+	row := q.db.QueryRow(ctx, setETHCryptoDataByUserId, arg.UserID, arg.EthID)
+	var i CryptoDatum
+	err := row.Scan(
+		&i.UserID,
+		&i.XmrID,
+		&i.BtcID,
+		&i.LtcID,
+		&i.EthID,
+		&i.TonID,
+	)
+	return i, err
+}
+
+const setLTCCryptoDataByUserId = `-- name: SetLTCCryptoDataByUserId :one
+UPDATE crypto_data
+SET ltc_id = $2 
+WHERE user_id = $1
+RETURNING user_id, xmr_id, btc_id, ltc_id, eth_id, ton_id
+`
+
+type SetLTCCryptoDataByUserIdParams struct {
+	UserID pgtype.UUID
+	LtcID  pgtype.UUID
+}
+
+func (q *Queries) SetLTCCryptoDataByUserId(ctx context.Context, arg SetLTCCryptoDataByUserIdParams) (CryptoDatum, error) {
+	row := q.db.QueryRow(ctx, setLTCCryptoDataByUserId, arg.UserID, arg.LtcID)
+	var i CryptoDatum
+	err := row.Scan(
+		&i.UserID,
+		&i.XmrID,
+		&i.BtcID,
+		&i.LtcID,
+		&i.EthID,
+		&i.TonID,
+	)
+	return i, err
+}
+
+const setTONCryptoDataByUserId = `-- name: SetTONCryptoDataByUserId :one
+UPDATE crypto_data
+SET ton_id = $2 
+WHERE user_id = $1
+RETURNING user_id, xmr_id, btc_id, ltc_id, eth_id, ton_id
+`
+
+type SetTONCryptoDataByUserIdParams struct {
+	UserID pgtype.UUID
+	TonID  pgtype.UUID
+}
+
+func (q *Queries) SetTONCryptoDataByUserId(ctx context.Context, arg SetTONCryptoDataByUserIdParams) (CryptoDatum, error) {
+	row := q.db.QueryRow(ctx, setTONCryptoDataByUserId, arg.UserID, arg.TonID)
+	var i CryptoDatum
+	err := row.Scan(
+		&i.UserID,
+		&i.XmrID,
+		&i.BtcID,
+		&i.LtcID,
+		&i.EthID,
+		&i.TonID,
+	)
+	return i, err
+}
+
 const setXMRCryptoDataByUserId = `-- name: SetXMRCryptoDataByUserId :one
 UPDATE crypto_data
 SET xmr_id = $2 
 WHERE user_id = $1
-RETURNING user_id, xmr_id
+RETURNING user_id, xmr_id, btc_id, ltc_id, eth_id, ton_id
 `
 
 type SetXMRCryptoDataByUserIdParams struct {
@@ -138,7 +486,111 @@ type SetXMRCryptoDataByUserIdParams struct {
 func (q *Queries) SetXMRCryptoDataByUserId(ctx context.Context, arg SetXMRCryptoDataByUserIdParams) (CryptoDatum, error) {
 	row := q.db.QueryRow(ctx, setXMRCryptoDataByUserId, arg.UserID, arg.XmrID)
 	var i CryptoDatum
-	err := row.Scan(&i.UserID, &i.XmrID)
+	err := row.Scan(
+		&i.UserID,
+		&i.XmrID,
+		&i.BtcID,
+		&i.LtcID,
+		&i.EthID,
+		&i.TonID,
+	)
+	return i, err
+}
+
+const updateAddressTONCryptoDataById = `-- name: UpdateAddressTONCryptoDataById :one
+UPDATE ton_crypto_data
+SET address = $2
+WHERE id = $1
+RETURNING id, address
+`
+
+type UpdateAddressTONCryptoDataByIdParams struct {
+	ID      pgtype.UUID
+	Address string
+}
+
+func (q *Queries) UpdateAddressTONCryptoDataById(ctx context.Context, arg UpdateAddressTONCryptoDataByIdParams) (TonCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updateAddressTONCryptoDataById, arg.ID, arg.Address)
+	var i TonCryptoDatum
+	err := row.Scan(&i.ID, &i.Address)
+	return i, err
+}
+
+const updateIndicesBTCCryptoDataById = `-- name: UpdateIndicesBTCCryptoDataById :one
+UPDATE btc_crypto_data
+SET last_major_index = $2,
+    last_minor_index = $3
+WHERE id = $1
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+type UpdateIndicesBTCCryptoDataByIdParams struct {
+	ID             pgtype.UUID
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+func (q *Queries) UpdateIndicesBTCCryptoDataById(ctx context.Context, arg UpdateIndicesBTCCryptoDataByIdParams) (BtcCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updateIndicesBTCCryptoDataById, arg.ID, arg.LastMajorIndex, arg.LastMinorIndex)
+	var i BtcCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
+const updateIndicesETHCryptoDataById = `-- name: UpdateIndicesETHCryptoDataById :one
+UPDATE eth_crypto_data
+SET last_major_index = $2,
+    last_minor_index = $3
+WHERE id = $1
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+type UpdateIndicesETHCryptoDataByIdParams struct {
+	ID             pgtype.UUID
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+func (q *Queries) UpdateIndicesETHCryptoDataById(ctx context.Context, arg UpdateIndicesETHCryptoDataByIdParams) (EthCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updateIndicesETHCryptoDataById, arg.ID, arg.LastMajorIndex, arg.LastMinorIndex)
+	var i EthCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
+const updateIndicesLTCCryptoDataById = `-- name: UpdateIndicesLTCCryptoDataById :one
+UPDATE ltc_crypto_data
+SET last_major_index = $2,
+    last_minor_index = $3
+WHERE id = $1
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+type UpdateIndicesLTCCryptoDataByIdParams struct {
+	ID             pgtype.UUID
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+func (q *Queries) UpdateIndicesLTCCryptoDataById(ctx context.Context, arg UpdateIndicesLTCCryptoDataByIdParams) (LtcCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updateIndicesLTCCryptoDataById, arg.ID, arg.LastMajorIndex, arg.LastMinorIndex)
+	var i LtcCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
 	return i, err
 }
 
@@ -169,6 +621,84 @@ func (q *Queries) UpdateIndicesXMRCryptoDataById(ctx context.Context, arg Update
 	return i, err
 }
 
+const updateKeysBTCCryptoDataById = `-- name: UpdateKeysBTCCryptoDataById :one
+UPDATE btc_crypto_data
+SET master_pub_key = $2,
+    last_major_index = 0,
+    last_minor_index = 0
+WHERE id = $1
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+type UpdateKeysBTCCryptoDataByIdParams struct {
+	ID           pgtype.UUID
+	MasterPubKey string
+}
+
+func (q *Queries) UpdateKeysBTCCryptoDataById(ctx context.Context, arg UpdateKeysBTCCryptoDataByIdParams) (BtcCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updateKeysBTCCryptoDataById, arg.ID, arg.MasterPubKey)
+	var i BtcCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
+const updateKeysETHCryptoDataById = `-- name: UpdateKeysETHCryptoDataById :one
+UPDATE eth_crypto_data
+SET master_pub_key = $2,
+    last_major_index = 0,
+    last_minor_index = 0
+WHERE id = $1
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+type UpdateKeysETHCryptoDataByIdParams struct {
+	ID           pgtype.UUID
+	MasterPubKey string
+}
+
+func (q *Queries) UpdateKeysETHCryptoDataById(ctx context.Context, arg UpdateKeysETHCryptoDataByIdParams) (EthCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updateKeysETHCryptoDataById, arg.ID, arg.MasterPubKey)
+	var i EthCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
+const updateKeysLTCCryptoDataById = `-- name: UpdateKeysLTCCryptoDataById :one
+UPDATE ltc_crypto_data
+SET master_pub_key = $2,
+    last_major_index = 0,
+    last_minor_index = 0
+WHERE id = $1
+RETURNING id, master_pub_key, last_major_index, last_minor_index
+`
+
+type UpdateKeysLTCCryptoDataByIdParams struct {
+	ID           pgtype.UUID
+	MasterPubKey string
+}
+
+func (q *Queries) UpdateKeysLTCCryptoDataById(ctx context.Context, arg UpdateKeysLTCCryptoDataByIdParams) (LtcCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updateKeysLTCCryptoDataById, arg.ID, arg.MasterPubKey)
+	var i LtcCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.MasterPubKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
+
 const updateKeysXMRCryptoDataById = `-- name: UpdateKeysXMRCryptoDataById :one
 UPDATE xmr_crypto_data
 SET priv_view_key = $2,
@@ -197,3 +727,28 @@ func (q *Queries) UpdateKeysXMRCryptoDataById(ctx context.Context, arg UpdateKey
 	)
 	return i, err
 }
+
+const updatePrivViewKeyXMRCryptoDataById = `-- name: UpdatePrivViewKeyXMRCryptoDataById :one
+UPDATE xmr_crypto_data
+SET priv_view_key = $2
+WHERE id = $1
+RETURNING id, priv_view_key, pub_spend_key, last_major_index, last_minor_index
+`
+
+type UpdatePrivViewKeyXMRCryptoDataByIdParams struct {
+	ID          pgtype.UUID
+	PrivViewKey string
+}
+
+func (q *Queries) UpdatePrivViewKeyXMRCryptoDataById(ctx context.Context, arg UpdatePrivViewKeyXMRCryptoDataByIdParams) (XmrCryptoDatum, error) {
+	row := q.db.QueryRow(ctx, updatePrivViewKeyXMRCryptoDataById, arg.ID, arg.PrivViewKey)
+	var i XmrCryptoDatum
+	err := row.Scan(
+		&i.ID,
+		&i.PrivViewKey,
+		&i.PubSpendKey,
+		&i.LastMajorIndex,
+		&i.LastMinorIndex,
+	)
+	return i, err
+}
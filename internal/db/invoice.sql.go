@@ -14,9 +14,10 @@ import (
 const confirmInvoiceById = `-- name: ConfirmInvoiceById :one
 UPDATE invoices
 SET status = 'CONFIRMED',
+    confirmations = confirmations_required,
     confirmed_at = timezone('UTC', now())
 WHERE id = $1
-RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
 `
 
 func (q *Queries) ConfirmInvoiceById(ctx context.Context, id pgtype.UUID) (Invoice, error) {
@@ -35,6 +36,14 @@ func (q *Queries) ConfirmInvoiceById(ctx context.Context, id pgtype.UUID) (Invoi
 		&i.ExpiresAt,
 		&i.TxID,
 		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
 	)
 	return i, err
 }
@@ -43,19 +52,29 @@ const confirmInvoiceStatusMempoolById = `-- name: ConfirmInvoiceStatusMempoolByI
 UPDATE invoices
 SET actual_amount = $2,
     status = 'PENDING_MEMPOOL',
-    tx_id = $3
+    tx_id = $3,
+    amount_delta = $4,
+    outcome = $5
 WHERE id = $1
-RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
 `
 
 type ConfirmInvoiceStatusMempoolByIdParams struct {
 	ID           pgtype.UUID
-	ActualAmount pgtype.Float8
+	ActualAmount pgtype.Numeric
 	TxID         pgtype.Text
+	AmountDelta  pgtype.Numeric
+	Outcome      NullInvoiceOutcomeType
 }
 
 func (q *Queries) ConfirmInvoiceStatusMempoolById(ctx context.Context, arg ConfirmInvoiceStatusMempoolByIdParams) (Invoice, error) {
-	row := q.db.QueryRow(ctx, confirmInvoiceStatusMempoolById, arg.ID, arg.ActualAmount, arg.TxID)
+	row := q.db.QueryRow(ctx, confirmInvoiceStatusMempoolById,
+		arg.ID,
+		arg.ActualAmount,
+		arg.TxID,
+		arg.AmountDelta,
+		arg.Outcome,
+	)
 	var i Invoice
 	err := row.Scan(
 		&i.ID,
@@ -70,10 +89,49 @@ func (q *Queries) ConfirmInvoiceStatusMempoolById(ctx context.Context, arg Confi
 		&i.ExpiresAt,
 		&i.TxID,
 		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
 	)
 	return i, err
 }
 
+const countInvoicesByCoinAndStatus = `-- name: CountInvoicesByCoinAndStatus :many
+SELECT coin, status, COUNT(*) AS count FROM invoices
+GROUP BY coin, status
+`
+
+type CountInvoicesByCoinAndStatusRow struct {
+	Coin   CoinType
+	Status InvoiceStatusType
+	Count  int64
+}
+
+func (q *Queries) CountInvoicesByCoinAndStatus(ctx context.Context) ([]CountInvoicesByCoinAndStatusRow, error) {
+	rows, err := q.db.Query(ctx, countInvoicesByCoinAndStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountInvoicesByCoinAndStatusRow
+	for rows.Next() {
+		var i CountInvoicesByCoinAndStatusRow
+		if err := rows.Scan(&i.Coin, &i.Status, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createInvoice = `-- name: CreateInvoice :one
 INSERT INTO invoices(
     crypto_address,
@@ -81,18 +139,26 @@ INSERT INTO invoices(
     required_amount, 
     confirmations_required,
     expires_at,
-    user_id) 
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id
+    user_id,
+    token_contract,
+    memo,
+    decimals,
+    tolerance_amount) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
 `
 
 type CreateInvoiceParams struct {
 	CryptoAddress         string
 	Coin                  CoinType
-	RequiredAmount        float64
+	RequiredAmount        pgtype.Numeric
 	ConfirmationsRequired int16
 	ExpiresAt             pgtype.Timestamptz
 	UserID                pgtype.UUID
+	TokenContract         pgtype.Text
+	Memo                  pgtype.Text
+	Decimals              int16
+	ToleranceAmount       pgtype.Numeric
 }
 
 func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error) {
@@ -103,6 +169,10 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		arg.ConfirmationsRequired,
 		arg.ExpiresAt,
 		arg.UserID,
+		arg.TokenContract,
+		arg.Memo,
+		arg.Decimals,
+		arg.ToleranceAmount,
 	)
 	var i Invoice
 	err := row.Scan(
@@ -118,15 +188,24 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		&i.ExpiresAt,
 		&i.TxID,
 		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
 	)
 	return i, err
 }
 
 const expireInvoiceById = `-- name: ExpireInvoiceById :one
 UPDATE invoices
-SET status = 'EXPIRED'
+SET status = 'EXPIRED',
+    expired_at = timezone('UTC', now())
 WHERE id = $1
-RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
 `
 
 func (q *Queries) ExpireInvoiceById(ctx context.Context, id pgtype.UUID) (Invoice, error) {
@@ -145,12 +224,82 @@ func (q *Queries) ExpireInvoiceById(ctx context.Context, id pgtype.UUID) (Invoic
 		&i.ExpiresAt,
 		&i.TxID,
 		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
 	)
 	return i, err
 }
 
+const findAllInvoicesByFilter = `-- name: FindAllInvoicesByFilter :many
+SELECT id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations FROM invoices
+WHERE ($1::uuid IS NULL OR user_id = $1)
+    AND ($2::coin_type IS NULL OR coin = $2)
+    AND ($3::invoice_status_type IS NULL OR status = $3)
+ORDER BY created_at DESC
+LIMIT $4
+`
+
+type FindAllInvoicesByFilterParams struct {
+	UserID pgtype.UUID
+	Coin   NullCoinType
+	Status NullInvoiceStatusType
+	Limit  int32
+}
+
+func (q *Queries) FindAllInvoicesByFilter(ctx context.Context, arg FindAllInvoicesByFilterParams) ([]Invoice, error) {
+	rows, err := q.db.Query(ctx, findAllInvoicesByFilter,
+		arg.UserID,
+		arg.Coin,
+		arg.Status,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Invoice
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.CryptoAddress,
+			&i.Coin,
+			&i.RequiredAmount,
+			&i.ActualAmount,
+			&i.ConfirmationsRequired,
+			&i.CreatedAt,
+			&i.ConfirmedAt,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.TxID,
+			&i.UserID,
+			&i.TokenContract,
+			&i.Memo,
+			&i.Decimals,
+			&i.ToleranceAmount,
+			&i.AmountDelta,
+			&i.Outcome,
+			&i.ExpiredAt,
+			&i.Confirmations,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findAllInvoicesByIds = `-- name: FindAllInvoicesByIds :many
-SELECT id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id FROM invoices
+SELECT id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations FROM invoices
 WHERE id = ANY($1::uuid[])
 `
 
@@ -176,6 +325,60 @@ func (q *Queries) FindAllInvoicesByIds(ctx context.Context, dollar_1 []pgtype.UU
 			&i.ExpiresAt,
 			&i.TxID,
 			&i.UserID,
+			&i.TokenContract,
+			&i.Memo,
+			&i.Decimals,
+			&i.ToleranceAmount,
+			&i.AmountDelta,
+			&i.Outcome,
+			&i.ExpiredAt,
+			&i.Confirmations,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findAllInvoicesExpiredSince = `-- name: FindAllInvoicesExpiredSince :many
+SELECT id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations FROM invoices
+WHERE status IN ('EXPIRED', 'PAID_AFTER_EXPIRY') AND expired_at > $1
+`
+
+func (q *Queries) FindAllInvoicesExpiredSince(ctx context.Context, expiredAt pgtype.Timestamptz) ([]Invoice, error) {
+	rows, err := q.db.Query(ctx, findAllInvoicesExpiredSince, expiredAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Invoice
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.CryptoAddress,
+			&i.Coin,
+			&i.RequiredAmount,
+			&i.ActualAmount,
+			&i.ConfirmationsRequired,
+			&i.CreatedAt,
+			&i.ConfirmedAt,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.TxID,
+			&i.UserID,
+			&i.TokenContract,
+			&i.Memo,
+			&i.Decimals,
+			&i.ToleranceAmount,
+			&i.AmountDelta,
+			&i.Outcome,
+			&i.ExpiredAt,
+			&i.Confirmations,
 		); err != nil {
 			return nil, err
 		}
@@ -188,8 +391,8 @@ func (q *Queries) FindAllInvoicesByIds(ctx context.Context, dollar_1 []pgtype.UU
 }
 
 const findAllPendingInvoices = `-- name: FindAllPendingInvoices :many
-SELECT id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id FROM invoices
-WHERE status IN ('PENDING', 'PENDING_MEMPOOL')
+SELECT id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations FROM invoices
+WHERE status IN ('PENDING', 'PARTIALLY_PAID', 'PENDING_MEMPOOL')
 `
 
 func (q *Queries) FindAllPendingInvoices(ctx context.Context) ([]Invoice, error) {
@@ -214,6 +417,14 @@ func (q *Queries) FindAllPendingInvoices(ctx context.Context) ([]Invoice, error)
 			&i.ExpiresAt,
 			&i.TxID,
 			&i.UserID,
+			&i.TokenContract,
+			&i.Memo,
+			&i.Decimals,
+			&i.ToleranceAmount,
+			&i.AmountDelta,
+			&i.Outcome,
+			&i.ExpiredAt,
+			&i.Confirmations,
 		); err != nil {
 			return nil, err
 		}
@@ -225,11 +436,176 @@ func (q *Queries) FindAllPendingInvoices(ctx context.Context) ([]Invoice, error)
 	return items, nil
 }
 
+const findInvoiceByIdAndLock = `-- name: FindInvoiceByIdAndLock :one
+SELECT id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations FROM invoices
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) FindInvoiceByIdAndLock(ctx context.Context, id pgtype.UUID) (Invoice, error) {
+	row := q.db.QueryRow(ctx, findInvoiceByIdAndLock, id)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.CryptoAddress,
+		&i.Coin,
+		&i.RequiredAmount,
+		&i.ActualAmount,
+		&i.ConfirmationsRequired,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.TxID,
+		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
+	)
+	return i, err
+}
+
+const partiallyPayInvoiceById = `-- name: PartiallyPayInvoiceById :one
+UPDATE invoices
+SET actual_amount = $2,
+    status = 'PARTIALLY_PAID',
+    tx_id = $3
+WHERE id = $1
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
+`
+
+type PartiallyPayInvoiceByIdParams struct {
+	ID           pgtype.UUID
+	ActualAmount pgtype.Numeric
+	TxID         pgtype.Text
+}
+
+func (q *Queries) PartiallyPayInvoiceById(ctx context.Context, arg PartiallyPayInvoiceByIdParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, partiallyPayInvoiceById, arg.ID, arg.ActualAmount, arg.TxID)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.CryptoAddress,
+		&i.Coin,
+		&i.RequiredAmount,
+		&i.ActualAmount,
+		&i.ConfirmationsRequired,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.TxID,
+		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
+	)
+	return i, err
+}
+
+const payAfterExpiryInvoiceById = `-- name: PayAfterExpiryInvoiceById :one
+UPDATE invoices
+SET actual_amount = $2,
+    status = 'PAID_AFTER_EXPIRY',
+    tx_id = $3,
+    amount_delta = $4,
+    outcome = $5
+WHERE id = $1
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
+`
+
+type PayAfterExpiryInvoiceByIdParams struct {
+	ID           pgtype.UUID
+	ActualAmount pgtype.Numeric
+	TxID         pgtype.Text
+	AmountDelta  pgtype.Numeric
+	Outcome      NullInvoiceOutcomeType
+}
+
+func (q *Queries) PayAfterExpiryInvoiceById(ctx context.Context, arg PayAfterExpiryInvoiceByIdParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, payAfterExpiryInvoiceById,
+		arg.ID,
+		arg.ActualAmount,
+		arg.TxID,
+		arg.AmountDelta,
+		arg.Outcome,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.CryptoAddress,
+		&i.Coin,
+		&i.RequiredAmount,
+		&i.ActualAmount,
+		&i.ConfirmationsRequired,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.TxID,
+		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
+	)
+	return i, err
+}
+
+const reorgInvoiceById = `-- name: ReorgInvoiceById :one
+UPDATE invoices
+SET status = 'REORGED'
+WHERE id = $1
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
+`
+
+func (q *Queries) ReorgInvoiceById(ctx context.Context, id pgtype.UUID) (Invoice, error) {
+	row := q.db.QueryRow(ctx, reorgInvoiceById, id)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.CryptoAddress,
+		&i.Coin,
+		&i.RequiredAmount,
+		&i.ActualAmount,
+		&i.ConfirmationsRequired,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.TxID,
+		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
+	)
+	return i, err
+}
+
 const shiftExpiresAtForNonConfirmedInvoices = `-- name: ShiftExpiresAtForNonConfirmedInvoices :many
 UPDATE invoices
 SET expires_at = timezone('UTC', now()) + INTERVAL '5 minute'
-WHERE status IN ('PENDING', 'PENDING_MEMPOOL') AND (expires_at - timezone('UTC', now()) < INTERVAL '5 minutes')
-RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id
+WHERE status IN ('PENDING', 'PARTIALLY_PAID', 'PENDING_MEMPOOL') AND (expires_at - timezone('UTC', now()) < INTERVAL '5 minutes')
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
 `
 
 func (q *Queries) ShiftExpiresAtForNonConfirmedInvoices(ctx context.Context) ([]Invoice, error) {
@@ -254,6 +630,14 @@ func (q *Queries) ShiftExpiresAtForNonConfirmedInvoices(ctx context.Context) ([]
 			&i.ExpiresAt,
 			&i.TxID,
 			&i.UserID,
+			&i.TokenContract,
+			&i.Memo,
+			&i.Decimals,
+			&i.ToleranceAmount,
+			&i.AmountDelta,
+			&i.Outcome,
+			&i.ExpiredAt,
+			&i.Confirmations,
 		); err != nil {
 			return nil, err
 		}
@@ -264,3 +648,132 @@ func (q *Queries) ShiftExpiresAtForNonConfirmedInvoices(ctx context.Context) ([]
 	}
 	return items, nil
 }
+
+const unconfirmInvoiceById = `-- name: UnconfirmInvoiceById :one
+UPDATE invoices
+SET status = 'PENDING_MEMPOOL',
+    confirmations = 0,
+    confirmed_at = NULL
+WHERE id = $1
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
+`
+
+func (q *Queries) UnconfirmInvoiceById(ctx context.Context, id pgtype.UUID) (Invoice, error) {
+	row := q.db.QueryRow(ctx, unconfirmInvoiceById, id)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.CryptoAddress,
+		&i.Coin,
+		&i.RequiredAmount,
+		&i.ActualAmount,
+		&i.ConfirmationsRequired,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.TxID,
+		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
+	)
+	return i, err
+}
+
+const updateConfirmationsInvoiceById = `-- name: UpdateConfirmationsInvoiceById :one
+UPDATE invoices
+SET confirmations = $2
+WHERE id = $1
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
+`
+
+type UpdateConfirmationsInvoiceByIdParams struct {
+	ID            pgtype.UUID
+	Confirmations int16
+}
+
+func (q *Queries) UpdateConfirmationsInvoiceById(ctx context.Context, arg UpdateConfirmationsInvoiceByIdParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, updateConfirmationsInvoiceById, arg.ID, arg.Confirmations)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.CryptoAddress,
+		&i.Coin,
+		&i.RequiredAmount,
+		&i.ActualAmount,
+		&i.ConfirmationsRequired,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.TxID,
+		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
+	)
+	return i, err
+}
+
+const updatePaidAmountInvoiceById = `-- name: UpdatePaidAmountInvoiceById :one
+UPDATE invoices
+SET actual_amount = $2,
+    tx_id = $3,
+    amount_delta = $4,
+    outcome = $5
+WHERE id = $1
+RETURNING id, crypto_address, coin, required_amount, actual_amount, confirmations_required, created_at, confirmed_at, status, expires_at, tx_id, user_id, token_contract, memo, decimals, tolerance_amount, amount_delta, outcome, expired_at, confirmations
+`
+
+type UpdatePaidAmountInvoiceByIdParams struct {
+	ID           pgtype.UUID
+	ActualAmount pgtype.Numeric
+	TxID         pgtype.Text
+	AmountDelta  pgtype.Numeric
+	Outcome      NullInvoiceOutcomeType
+}
+
+func (q *Queries) UpdatePaidAmountInvoiceById(ctx context.Context, arg UpdatePaidAmountInvoiceByIdParams) (Invoice, error) {
+	row := q.db.QueryRow(ctx, updatePaidAmountInvoiceById,
+		arg.ID,
+		arg.ActualAmount,
+		arg.TxID,
+		arg.AmountDelta,
+		arg.Outcome,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.CryptoAddress,
+		&i.Coin,
+		&i.RequiredAmount,
+		&i.ActualAmount,
+		&i.ConfirmationsRequired,
+		&i.CreatedAt,
+		&i.ConfirmedAt,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.TxID,
+		&i.UserID,
+		&i.TokenContract,
+		&i.Memo,
+		&i.Decimals,
+		&i.ToleranceAmount,
+		&i.AmountDelta,
+		&i.Outcome,
+		&i.ExpiredAt,
+		&i.Confirmations,
+	)
+	return i, err
+}
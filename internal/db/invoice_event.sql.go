@@ -0,0 +1,86 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: invoice_event.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createInvoiceEvent = `-- name: CreateInvoiceEvent :one
+INSERT INTO invoice_events(invoice_id, status, invoice)
+VALUES ($1, $2, $3)
+RETURNING sequence, invoice_id, status, invoice, created_at
+`
+
+type CreateInvoiceEventParams struct {
+	InvoiceID pgtype.UUID
+	Status    InvoiceStatusType
+	Invoice   []byte
+}
+
+func (q *Queries) CreateInvoiceEvent(ctx context.Context, arg CreateInvoiceEventParams) (InvoiceEvent, error) {
+	row := q.db.QueryRow(ctx, createInvoiceEvent, arg.InvoiceID, arg.Status, arg.Invoice)
+	var i InvoiceEvent
+	err := row.Scan(
+		&i.Sequence,
+		&i.InvoiceID,
+		&i.Status,
+		&i.Invoice,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findAllInvoiceEventsFromSequence = `-- name: FindAllInvoiceEventsFromSequence :many
+SELECT sequence, invoice_id, status, invoice, created_at FROM invoice_events
+WHERE sequence > $1
+ORDER BY sequence
+LIMIT $2
+`
+
+type FindAllInvoiceEventsFromSequenceParams struct {
+	Sequence int64
+	Limit    int32
+}
+
+func (q *Queries) FindAllInvoiceEventsFromSequence(ctx context.Context, arg FindAllInvoiceEventsFromSequenceParams) ([]InvoiceEvent, error) {
+	rows, err := q.db.Query(ctx, findAllInvoiceEventsFromSequence, arg.Sequence, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoiceEvent
+	for rows.Next() {
+		var i InvoiceEvent
+		if err := rows.Scan(
+			&i.Sequence,
+			&i.InvoiceID,
+			&i.Status,
+			&i.Invoice,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockInvoiceEvents = `-- name: LockInvoiceEvents :exec
+SELECT pg_advisory_xact_lock(hashtext('invoice_events'))
+`
+
+// Serializes the event inserts till the end of the tx, so the sequences are committed in order
+// and a reader never sees a greater sequence before a lower one.
+func (q *Queries) LockInvoiceEvents(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockInvoiceEvents)
+	return err
+}
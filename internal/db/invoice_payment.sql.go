@@ -0,0 +1,254 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: invoice_payment.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createInvoicePayment = `-- name: CreateInvoicePayment :one
+INSERT INTO invoice_payments(
+    invoice_id,
+    tx_id,
+    output_index,
+    amount,
+    block_height)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (invoice_id, tx_id, output_index) DO NOTHING
+RETURNING id, invoice_id, tx_id, output_index, amount, block_height, created_at
+`
+
+type CreateInvoicePaymentParams struct {
+	InvoiceID   pgtype.UUID
+	TxID        string
+	OutputIndex int32
+	Amount      pgtype.Numeric
+	BlockHeight pgtype.Int8
+}
+
+func (q *Queries) CreateInvoicePayment(ctx context.Context, arg CreateInvoicePaymentParams) (InvoicePayment, error) {
+	row := q.db.QueryRow(ctx, createInvoicePayment,
+		arg.InvoiceID,
+		arg.TxID,
+		arg.OutputIndex,
+		arg.Amount,
+		arg.BlockHeight,
+	)
+	var i InvoicePayment
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.TxID,
+		&i.OutputIndex,
+		&i.Amount,
+		&i.BlockHeight,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteInvoicePaymentsByInvoiceIdAndTxIds = `-- name: DeleteInvoicePaymentsByInvoiceIdAndTxIds :many
+DELETE FROM invoice_payments
+WHERE invoice_id = $1 AND tx_id = ANY($2::text[])
+RETURNING id, invoice_id, tx_id, output_index, amount, block_height, created_at
+`
+
+type DeleteInvoicePaymentsByInvoiceIdAndTxIdsParams struct {
+	InvoiceID pgtype.UUID
+	TxIds     []string
+}
+
+func (q *Queries) DeleteInvoicePaymentsByInvoiceIdAndTxIds(ctx context.Context, arg DeleteInvoicePaymentsByInvoiceIdAndTxIdsParams) ([]InvoicePayment, error) {
+	rows, err := q.db.Query(ctx, deleteInvoicePaymentsByInvoiceIdAndTxIds, arg.InvoiceID, arg.TxIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoicePayment
+	for rows.Next() {
+		var i InvoicePayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.TxID,
+			&i.OutputIndex,
+			&i.Amount,
+			&i.BlockHeight,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findAllInvoicePaymentsByInvoiceId = `-- name: FindAllInvoicePaymentsByInvoiceId :many
+SELECT id, invoice_id, tx_id, output_index, amount, block_height, created_at FROM invoice_payments
+WHERE invoice_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) FindAllInvoicePaymentsByInvoiceId(ctx context.Context, invoiceID pgtype.UUID) ([]InvoicePayment, error) {
+	rows, err := q.db.Query(ctx, findAllInvoicePaymentsByInvoiceId, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoicePayment
+	for rows.Next() {
+		var i InvoicePayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.TxID,
+			&i.OutputIndex,
+			&i.Amount,
+			&i.BlockHeight,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findAllInvoicePaymentsByInvoiceIds = `-- name: FindAllInvoicePaymentsByInvoiceIds :many
+SELECT id, invoice_id, tx_id, output_index, amount, block_height, created_at FROM invoice_payments
+WHERE invoice_id = ANY($1::uuid[])
+ORDER BY created_at, id
+`
+
+func (q *Queries) FindAllInvoicePaymentsByInvoiceIds(ctx context.Context, dollar_1 []pgtype.UUID) ([]InvoicePayment, error) {
+	rows, err := q.db.Query(ctx, findAllInvoicePaymentsByInvoiceIds, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoicePayment
+	for rows.Next() {
+		var i InvoicePayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.TxID,
+			&i.OutputIndex,
+			&i.Amount,
+			&i.BlockHeight,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resetBlockHeightInvoicePaymentsByCoinFromBlockHeight = `-- name: ResetBlockHeightInvoicePaymentsByCoinFromBlockHeight :many
+UPDATE invoice_payments AS ip
+SET block_height = NULL
+FROM invoices AS i
+WHERE ip.invoice_id = i.id AND i.coin = $1 AND ip.block_height >= $2
+RETURNING ip.id, ip.invoice_id, ip.tx_id, ip.output_index, ip.amount, ip.block_height, ip.created_at
+`
+
+type ResetBlockHeightInvoicePaymentsByCoinFromBlockHeightParams struct {
+	Coin        CoinType
+	BlockHeight pgtype.Int8
+}
+
+func (q *Queries) ResetBlockHeightInvoicePaymentsByCoinFromBlockHeight(ctx context.Context, arg ResetBlockHeightInvoicePaymentsByCoinFromBlockHeightParams) ([]InvoicePayment, error) {
+	rows, err := q.db.Query(ctx, resetBlockHeightInvoicePaymentsByCoinFromBlockHeight, arg.Coin, arg.BlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoicePayment
+	for rows.Next() {
+		var i InvoicePayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.TxID,
+			&i.OutputIndex,
+			&i.Amount,
+			&i.BlockHeight,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumAmountInvoicePaymentsByInvoiceId = `-- name: SumAmountInvoicePaymentsByInvoiceId :one
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(78, 0) AS amount FROM invoice_payments
+WHERE invoice_id = $1
+`
+
+func (q *Queries) SumAmountInvoicePaymentsByInvoiceId(ctx context.Context, invoiceID pgtype.UUID) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, sumAmountInvoicePaymentsByInvoiceId, invoiceID)
+	var amount pgtype.Numeric
+	err := row.Scan(&amount)
+	return amount, err
+}
+
+const updateBlockHeightInvoicePaymentsByInvoiceIdAndTxId = `-- name: UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId :many
+UPDATE invoice_payments
+SET block_height = $3
+WHERE invoice_id = $1 AND tx_id = $2
+RETURNING id, invoice_id, tx_id, output_index, amount, block_height, created_at
+`
+
+type UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxIdParams struct {
+	InvoiceID   pgtype.UUID
+	TxID        string
+	BlockHeight pgtype.Int8
+}
+
+func (q *Queries) UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId(ctx context.Context, arg UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxIdParams) ([]InvoicePayment, error) {
+	rows, err := q.db.Query(ctx, updateBlockHeightInvoicePaymentsByInvoiceIdAndTxId, arg.InvoiceID, arg.TxID, arg.BlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InvoicePayment
+	for rows.Next() {
+		var i InvoicePayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.TxID,
+			&i.OutputIndex,
+			&i.Amount,
+			&i.BlockHeight,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,6 +11,48 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiKeyRoleType string
+
+const (
+	ApiKeyRoleTypeUSER  ApiKeyRoleType = "USER"
+	ApiKeyRoleTypeADMIN ApiKeyRoleType = "ADMIN"
+)
+
+func (e *ApiKeyRoleType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ApiKeyRoleType(s)
+	case string:
+		*e = ApiKeyRoleType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ApiKeyRoleType: %T", src)
+	}
+	return nil
+}
+
+type NullApiKeyRoleType struct {
+	ApiKeyRoleType ApiKeyRoleType
+	Valid          bool // Valid is true if ApiKeyRoleType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullApiKeyRoleType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ApiKeyRoleType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ApiKeyRoleType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullApiKeyRoleType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ApiKeyRoleType), nil
+}
+
 type CoinType string
 
 const (
@@ -56,13 +98,59 @@ func (ns NullCoinType) Value() (driver.Value, error) {
 	return string(ns.CoinType), nil
 }
 
+type InvoiceOutcomeType string
+
+const (
+	InvoiceOutcomeTypeEXACT     InvoiceOutcomeType = "EXACT"
+	InvoiceOutcomeTypeUNDERPAID InvoiceOutcomeType = "UNDERPAID"
+	InvoiceOutcomeTypeOVERPAID  InvoiceOutcomeType = "OVERPAID"
+)
+
+func (e *InvoiceOutcomeType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InvoiceOutcomeType(s)
+	case string:
+		*e = InvoiceOutcomeType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InvoiceOutcomeType: %T", src)
+	}
+	return nil
+}
+
+type NullInvoiceOutcomeType struct {
+	InvoiceOutcomeType InvoiceOutcomeType
+	Valid              bool // Valid is true if InvoiceOutcomeType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInvoiceOutcomeType) Scan(value interface{}) error {
+	if value == nil {
+		ns.InvoiceOutcomeType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InvoiceOutcomeType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInvoiceOutcomeType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InvoiceOutcomeType), nil
+}
+
 type InvoiceStatusType string
 
 const (
-	InvoiceStatusTypePENDING        InvoiceStatusType = "PENDING"
-	InvoiceStatusTypePENDINGMEMPOOL InvoiceStatusType = "PENDING_MEMPOOL"
-	InvoiceStatusTypeEXPIRED        InvoiceStatusType = "EXPIRED"
-	InvoiceStatusTypeCONFIRMED      InvoiceStatusType = "CONFIRMED"
+	InvoiceStatusTypePENDING         InvoiceStatusType = "PENDING"
+	InvoiceStatusTypePARTIALLYPAID   InvoiceStatusType = "PARTIALLY_PAID"
+	InvoiceStatusTypePENDINGMEMPOOL  InvoiceStatusType = "PENDING_MEMPOOL"
+	InvoiceStatusTypeEXPIRED         InvoiceStatusType = "EXPIRED"
+	InvoiceStatusTypeCONFIRMED       InvoiceStatusType = "CONFIRMED"
+	InvoiceStatusTypePAIDAFTEREXPIRY InvoiceStatusType = "PAID_AFTER_EXPIRY"
+	InvoiceStatusTypeREORGED         InvoiceStatusType = "REORGED"
 )
 
 func (e *InvoiceStatusType) Scan(src interface{}) error {
@@ -100,6 +188,65 @@ func (ns NullInvoiceStatusType) Value() (driver.Value, error) {
 	return string(ns.InvoiceStatusType), nil
 }
 
+type WebhookDeliveryStatusType string
+
+const (
+	WebhookDeliveryStatusTypePENDING   WebhookDeliveryStatusType = "PENDING"
+	WebhookDeliveryStatusTypeDELIVERED WebhookDeliveryStatusType = "DELIVERED"
+	WebhookDeliveryStatusTypeFAILED    WebhookDeliveryStatusType = "FAILED"
+)
+
+func (e *WebhookDeliveryStatusType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatusType(s)
+	case string:
+		*e = WebhookDeliveryStatusType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatusType: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatusType struct {
+	WebhookDeliveryStatusType WebhookDeliveryStatusType
+	Valid                     bool // Valid is true if WebhookDeliveryStatusType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatusType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatusType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatusType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatusType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatusType), nil
+}
+
+type ApiKey struct {
+	ID        pgtype.UUID
+	KeyHash   string
+	Role      ApiKeyRoleType
+	UserID    pgtype.UUID
+	CreatedAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+}
+
+type BtcCryptoDatum struct {
+	ID             pgtype.UUID
+	MasterPubKey   string
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
 type CryptoAddress struct {
 	ID         pgtype.UUID
 	Address    string
@@ -117,14 +264,25 @@ type CryptoCache struct {
 type CryptoDatum struct {
 	UserID pgtype.UUID
 	XmrID  pgtype.UUID
+	BtcID  pgtype.UUID
+	LtcID  pgtype.UUID
+	EthID  pgtype.UUID
+	TonID  pgtype.UUID
+}
+
+type EthCryptoDatum struct {
+	ID             pgtype.UUID
+	MasterPubKey   string
+	LastMajorIndex int32
+	LastMinorIndex int32
 }
 
 type Invoice struct {
 	ID                    pgtype.UUID
 	CryptoAddress         string
 	Coin                  CoinType
-	RequiredAmount        float64
-	ActualAmount          pgtype.Float8
+	RequiredAmount        pgtype.Numeric
+	ActualAmount          pgtype.Numeric
 	ConfirmationsRequired int16
 	CreatedAt             pgtype.Timestamptz
 	ConfirmedAt           pgtype.Timestamptz
@@ -132,12 +290,84 @@ type Invoice struct {
 	ExpiresAt             pgtype.Timestamptz
 	TxID                  pgtype.Text
 	UserID                pgtype.UUID
+	TokenContract         pgtype.Text
+	Memo                  pgtype.Text
+	Decimals              int16
+	ToleranceAmount       pgtype.Numeric
+	AmountDelta           pgtype.Numeric
+	Outcome               NullInvoiceOutcomeType
+	ExpiredAt             pgtype.Timestamptz
+	Confirmations         int16
+}
+
+type InvoiceEvent struct {
+	Sequence  int64
+	InvoiceID pgtype.UUID
+	Status    InvoiceStatusType
+	Invoice   []byte
+	CreatedAt pgtype.Timestamptz
+}
+
+type InvoicePayment struct {
+	ID          pgtype.UUID
+	InvoiceID   pgtype.UUID
+	TxID        string
+	OutputIndex int32
+	Amount      pgtype.Numeric
+	BlockHeight pgtype.Int8
+	CreatedAt   pgtype.Timestamptz
+}
+
+type LtcCryptoDatum struct {
+	ID             pgtype.UUID
+	MasterPubKey   string
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+type TonAddressCursor struct {
+	Address  string
+	LastLt   int64
+	LastHash string
+}
+
+type TonCryptoDatum struct {
+	ID      pgtype.UUID
+	Address string
 }
 
 type User struct {
 	ID pgtype.UUID
 }
 
+type Webhook struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	Url       string
+	Secret    string
+	CreatedAt pgtype.Timestamptz
+}
+
+type WebhookDeliveryAttempt struct {
+	ID         pgtype.UUID
+	OutboxID   pgtype.UUID
+	StatusCode pgtype.Int4
+	Error      pgtype.Text
+	CreatedAt  pgtype.Timestamptz
+}
+
+type WebhookOutbox struct {
+	ID            pgtype.UUID
+	WebhookID     pgtype.UUID
+	InvoiceID     pgtype.UUID
+	Payload       []byte
+	Status        WebhookDeliveryStatusType
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamptz
+	DeliveredAt   pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+}
+
 type XmrCryptoDatum struct {
 	ID             pgtype.UUID
 	PrivViewKey    string
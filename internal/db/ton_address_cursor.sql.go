@@ -0,0 +1,42 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: ton_address_cursor.sql
+
+package db
+
+import (
+	"context"
+)
+
+const findTonAddressCursorByAddress = `-- name: FindTonAddressCursorByAddress :one
+SELECT address, last_lt, last_hash FROM ton_address_cursors
+WHERE address = $1
+`
+
+func (q *Queries) FindTonAddressCursorByAddress(ctx context.Context, address string) (TonAddressCursor, error) {
+	row := q.db.QueryRow(ctx, findTonAddressCursorByAddress, address)
+	var i TonAddressCursor
+	err := row.Scan(&i.Address, &i.LastLt, &i.LastHash)
+	return i, err
+}
+
+const upsertTonAddressCursor = `-- name: UpsertTonAddressCursor :exec
+INSERT INTO ton_address_cursors(address, last_lt, last_hash)
+VALUES ($1, $2, $3)
+ON CONFLICT (address) DO UPDATE
+SET last_lt = EXCLUDED.last_lt,
+    last_hash = EXCLUDED.last_hash
+WHERE ton_address_cursors.last_lt < EXCLUDED.last_lt
+`
+
+type UpsertTonAddressCursorParams struct {
+	Address  string
+	LastLt   int64
+	LastHash string
+}
+
+func (q *Queries) UpsertTonAddressCursor(ctx context.Context, arg UpsertTonAddressCursorParams) error {
+	_, err := q.db.Exec(ctx, upsertTonAddressCursor, arg.Address, arg.LastLt, arg.LastHash)
+	return err
+}
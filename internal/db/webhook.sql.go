@@ -0,0 +1,302 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: webhook.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks(user_id, url, secret) VALUES ($1, $2, $3)
+RETURNING id, user_id, url, secret, created_at
+`
+
+type CreateWebhookParams struct {
+	UserID pgtype.UUID
+	Url    string
+	Secret string
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRow(ctx, createWebhook, arg.UserID, arg.Url, arg.Secret)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.Secret,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookDeliveryAttempt = `-- name: CreateWebhookDeliveryAttempt :one
+INSERT INTO webhook_delivery_attempts(outbox_id, status_code, error) VALUES ($1, $2, $3)
+RETURNING id, outbox_id, status_code, error, created_at
+`
+
+type CreateWebhookDeliveryAttemptParams struct {
+	OutboxID   pgtype.UUID
+	StatusCode pgtype.Int4
+	Error      pgtype.Text
+}
+
+func (q *Queries) CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) (WebhookDeliveryAttempt, error) {
+	row := q.db.QueryRow(ctx, createWebhookDeliveryAttempt, arg.OutboxID, arg.StatusCode, arg.Error)
+	var i WebhookDeliveryAttempt
+	err := row.Scan(
+		&i.ID,
+		&i.OutboxID,
+		&i.StatusCode,
+		&i.Error,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookOutboxEventsByUserId = `-- name: CreateWebhookOutboxEventsByUserId :many
+INSERT INTO webhook_outbox(webhook_id, invoice_id, payload)
+SELECT w.id, $2, $3 FROM webhooks AS w
+WHERE w.user_id = $1
+RETURNING id, webhook_id, invoice_id, payload, status, attempts, next_attempt_at, delivered_at, created_at
+`
+
+type CreateWebhookOutboxEventsByUserIdParams struct {
+	UserID    pgtype.UUID
+	InvoiceID pgtype.UUID
+	Payload   []byte
+}
+
+func (q *Queries) CreateWebhookOutboxEventsByUserId(ctx context.Context, arg CreateWebhookOutboxEventsByUserIdParams) ([]WebhookOutbox, error) {
+	rows, err := q.db.Query(ctx, createWebhookOutboxEventsByUserId, arg.UserID, arg.InvoiceID, arg.Payload)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookOutbox
+	for rows.Next() {
+		var i WebhookOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.InvoiceID,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteWebhookByIdAndUserId = `-- name: DeleteWebhookByIdAndUserId :one
+DELETE FROM webhooks
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, url, secret, created_at
+`
+
+type DeleteWebhookByIdAndUserIdParams struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+}
+
+func (q *Queries) DeleteWebhookByIdAndUserId(ctx context.Context, arg DeleteWebhookByIdAndUserIdParams) (Webhook, error) {
+	row := q.db.QueryRow(ctx, deleteWebhookByIdAndUserId, arg.ID, arg.UserID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.Secret,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deliverWebhookOutboxEventById = `-- name: DeliverWebhookOutboxEventById :one
+UPDATE webhook_outbox
+SET status = 'DELIVERED',
+    attempts = attempts + 1,
+    delivered_at = timezone('UTC', now())
+WHERE id = $1
+RETURNING id, webhook_id, invoice_id, payload, status, attempts, next_attempt_at, delivered_at, created_at
+`
+
+func (q *Queries) DeliverWebhookOutboxEventById(ctx context.Context, id pgtype.UUID) (WebhookOutbox, error) {
+	row := q.db.QueryRow(ctx, deliverWebhookOutboxEventById, id)
+	var i WebhookOutbox
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.InvoiceID,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const failWebhookOutboxEventById = `-- name: FailWebhookOutboxEventById :one
+UPDATE webhook_outbox
+SET status = 'FAILED',
+    attempts = attempts + 1
+WHERE id = $1
+RETURNING id, webhook_id, invoice_id, payload, status, attempts, next_attempt_at, delivered_at, created_at
+`
+
+func (q *Queries) FailWebhookOutboxEventById(ctx context.Context, id pgtype.UUID) (WebhookOutbox, error) {
+	row := q.db.QueryRow(ctx, failWebhookOutboxEventById, id)
+	var i WebhookOutbox
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.InvoiceID,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findAllWebhookDeliveryAttemptsByOutboxId = `-- name: FindAllWebhookDeliveryAttemptsByOutboxId :many
+SELECT id, outbox_id, status_code, error, created_at FROM webhook_delivery_attempts
+WHERE outbox_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) FindAllWebhookDeliveryAttemptsByOutboxId(ctx context.Context, outboxID pgtype.UUID) ([]WebhookDeliveryAttempt, error) {
+	rows, err := q.db.Query(ctx, findAllWebhookDeliveryAttemptsByOutboxId, outboxID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDeliveryAttempt
+	for rows.Next() {
+		var i WebhookDeliveryAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.OutboxID,
+			&i.StatusCode,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findAllWebhooksByUserId = `-- name: FindAllWebhooksByUserId :many
+SELECT id, user_id, url, secret, created_at FROM webhooks
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) FindAllWebhooksByUserId(ctx context.Context, userID pgtype.UUID) ([]Webhook, error) {
+	rows, err := q.db.Query(ctx, findAllWebhooksByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Url,
+			&i.Secret,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findDueWebhookOutboxEventAndLock = `-- name: FindDueWebhookOutboxEventAndLock :one
+SELECT o.id, o.payload, o.attempts, w.url, w.secret FROM webhook_outbox AS o
+JOIN webhooks AS w ON w.id = o.webhook_id
+WHERE o.status = 'PENDING' AND o.next_attempt_at <= timezone('UTC', now())
+ORDER BY o.next_attempt_at
+LIMIT 1
+FOR UPDATE OF o SKIP LOCKED
+`
+
+type FindDueWebhookOutboxEventAndLockRow struct {
+	ID       pgtype.UUID
+	Payload  []byte
+	Attempts int32
+	Url      string
+	Secret   string
+}
+
+func (q *Queries) FindDueWebhookOutboxEventAndLock(ctx context.Context) (FindDueWebhookOutboxEventAndLockRow, error) {
+	row := q.db.QueryRow(ctx, findDueWebhookOutboxEventAndLock)
+	var i FindDueWebhookOutboxEventAndLockRow
+	err := row.Scan(
+		&i.ID,
+		&i.Payload,
+		&i.Attempts,
+		&i.Url,
+		&i.Secret,
+	)
+	return i, err
+}
+
+const rescheduleWebhookOutboxEventById = `-- name: RescheduleWebhookOutboxEventById :one
+UPDATE webhook_outbox
+SET attempts = attempts + 1,
+    next_attempt_at = $2
+WHERE id = $1
+RETURNING id, webhook_id, invoice_id, payload, status, attempts, next_attempt_at, delivered_at, created_at
+`
+
+type RescheduleWebhookOutboxEventByIdParams struct {
+	ID            pgtype.UUID
+	NextAttemptAt pgtype.Timestamptz
+}
+
+func (q *Queries) RescheduleWebhookOutboxEventById(ctx context.Context, arg RescheduleWebhookOutboxEventByIdParams) (WebhookOutbox, error) {
+	row := q.db.QueryRow(ctx, rescheduleWebhookOutboxEventById, arg.ID, arg.NextAttemptAt)
+	var i WebhookOutbox
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.InvoiceID,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -1,13 +1,49 @@
 package dto
 
-import "github.com/chekist32/goipay/internal/db"
+import (
+	"math/big"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/envelope"
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type NewInvoiceRequest struct {
-	UserId        string
-	Coin          db.CoinType
-	Amount        float64
+	UserId string
+	Coin   db.CoinType
+	Amount float64
+	// Amount in atomic units, takes precedence over Amount if set
+	AmountAtomic  *big.Int
 	Timeout       uint64
 	Confirmations uint32
+	// Symbol or contract address of a token, empty for the native coin
+	Token string
+	// Accepted underpayment, at most one of them is set
+	ToleranceAtomic  *big.Int
+	TolerancePercent *float64
+}
+
+// InvoiceFilter selects the invoices a subscriber is notified about.
+// An invoice has to match every non-empty field, and any of the values within one.
+type InvoiceFilter struct {
+	UserIds    []pgtype.UUID
+	InvoiceIds []pgtype.UUID
+	Coins      []db.CoinType
+	Statuses   []db.InvoiceStatusType
+}
+
+// InvoiceEvent is an invoice status transition along with its position in the event log.
+type InvoiceEvent struct {
+	Sequence int64
+	Invoice  db.Invoice
+}
+
+type Asset struct {
+	Coin     db.CoinType
+	Symbol   string
+	Contract string
+	Decimals uint32
 }
 
 type DaemonConfig struct {
@@ -16,6 +52,27 @@ type DaemonConfig struct {
 	Pass string
 }
 
+type EthTokenConfig struct {
+	Symbol   string
+	Contract string
+	Decimals uint32
+}
+
+type InvoiceConfig struct {
+	// How long the address of an expired invoice is still watched for late payments, 0 disables it
+	LatePaymentGracePeriod time.Duration
+}
+
 type DaemonsConfig struct {
 	Xmr DaemonConfig
+	Btc DaemonConfig
+	Ltc DaemonConfig
+	Eth DaemonConfig
+	// Url is the toncenter-compatible API base and Pass is sent as its API key
+	Ton DaemonConfig
+
+	EthTokens []EthTokenConfig
+
+	// Encrypts the stored XMR private view keys, nil keeps them in plaintext
+	XmrKeyring *envelope.Keyring
 }
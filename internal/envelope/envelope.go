@@ -0,0 +1,244 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+)
+
+const (
+	MASTER_KEY_LENGTH int = 32
+
+	envelope_prefix string = "enc:v1:"
+	data_key_length int    = 32
+	key_id_length   int    = 16
+)
+
+var (
+	// InvalidMasterKeyErr is returned for master keys which aren't 32 hex-encoded bytes.
+	InvalidMasterKeyErr error = errors.New("the master key must be 32 hex-encoded bytes")
+	// UnknownMasterKeyErr is returned for values encrypted with a master key missing from the keyring.
+	UnknownMasterKeyErr error = errors.New("the value is encrypted with an unknown master key")
+	// MalformedEnvelopeErr is returned for values which can't be parsed or fail the authentication.
+	MalformedEnvelopeErr error = errors.New("malformed envelope")
+)
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// envelope is "enc:v1:<master key id>:<base64 wrapped data key>:<base64 ciphertext>".
+// Every value has its own random data key, so rotating the master key only rewraps the data keys.
+type envelope struct {
+	keyId          string
+	wrappedDataKey []byte
+	ciphertext     []byte
+}
+
+func (e *envelope) String() string {
+	return envelope_prefix + e.keyId + ":" + base64.StdEncoding.EncodeToString(e.wrappedDataKey) + ":" + base64.StdEncoding.EncodeToString(e.ciphertext)
+}
+
+func parseEnvelope(v string) (*envelope, error) {
+	parts := strings.Split(strings.TrimPrefix(v, envelope_prefix), ":")
+	if !IsEncrypted(v) || len(parts) != 3 {
+		return nil, MalformedEnvelopeErr
+	}
+
+	wrappedDataKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, MalformedEnvelopeErr
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, MalformedEnvelopeErr
+	}
+
+	return &envelope{keyId: parts[0], wrappedDataKey: wrappedDataKey, ciphertext: ciphertext}, nil
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal prepends the random nonce to the ciphertext.
+func seal(aead cipher.AEAD, plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, MalformedEnvelopeErr
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, MalformedEnvelopeErr
+	}
+
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether the value has been produced by Keyring.Encrypt.
+func IsEncrypted(v string) bool {
+	return strings.HasPrefix(v, envelope_prefix)
+}
+
+// ParseMasterKey decodes a hex-encoded master key, e.g. generated with `openssl rand -hex 32`.
+func ParseMasterKey(hexKey string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(hexKey))
+	if err != nil || len(key) != MASTER_KEY_LENGTH {
+		return nil, InvalidMasterKeyErr
+	}
+
+	return key, nil
+}
+
+// ReadMasterKeyFile reads a hex-encoded master key from the file.
+func ReadMasterKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseMasterKey(string(data))
+}
+
+// Keyring encrypts with the current master key and decrypts with any of its keys.
+type Keyring struct {
+	current *masterKey
+	keys    map[string]*masterKey
+}
+
+func (k *Keyring) wrapDataKey(dataKey []byte) ([]byte, error) {
+	return seal(k.current.aead, dataKey, []byte(k.current.id))
+}
+
+func (k *Keyring) unwrapDataKey(e *envelope) ([]byte, error) {
+	mk, ok := k.keys[e.keyId]
+	if !ok {
+		return nil, UnknownMasterKeyErr
+	}
+
+	return open(mk.aead, e.wrappedDataKey, []byte(mk.id))
+}
+
+// CurrentKeyId returns the id of the master key new values are encrypted with.
+func (k *Keyring) CurrentKeyId() string {
+	return k.current.id
+}
+
+// Encrypt encrypts the plaintext with a new data key wrapped by the current master key.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, data_key_length)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	aead, err := newAead(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedDataKey, err := k.wrapDataKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return (&envelope{keyId: k.current.id, wrappedDataKey: wrappedDataKey, ciphertext: ciphertext}).String(), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt with any master key of the keyring.
+func (k *Keyring) Decrypt(v string) (string, error) {
+	e, err := parseEnvelope(v)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := k.unwrapDataKey(e)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAead(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, e.ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// Rewrap rewraps the data key of the value with the current master key, the ciphertext stays the same.
+// It reports false if the value is already wrapped with the current master key.
+func (k *Keyring) Rewrap(v string) (string, bool, error) {
+	e, err := parseEnvelope(v)
+	if err != nil {
+		return "", false, err
+	}
+	if e.keyId == k.current.id {
+		return v, false, nil
+	}
+
+	dataKey, err := k.unwrapDataKey(e)
+	if err != nil {
+		return "", false, err
+	}
+
+	e.keyId = k.current.id
+	if e.wrappedDataKey, err = k.wrapDataKey(dataKey); err != nil {
+		return "", false, err
+	}
+
+	return e.String(), true, nil
+}
+
+// NewKeyring creates a keyring encrypting with the current master key.
+// The previous ones are only used to decrypt the values not rotated yet.
+func NewKeyring(current []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]*masterKey, len(previous)+1)}
+
+	for i, key := range append([][]byte{current}, previous...) {
+		if len(key) != MASTER_KEY_LENGTH {
+			return nil, InvalidMasterKeyErr
+		}
+
+		aead, err := newAead(key)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(key)
+		mk := &masterKey{id: hex.EncodeToString(sum[:])[:key_id_length], aead: aead}
+		k.keys[mk.id] = mk
+		if i == 0 {
+			k.current = mk
+		}
+	}
+
+	return k, nil
+}
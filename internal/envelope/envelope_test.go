@@ -0,0 +1,146 @@
+package envelope
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const test_priv_view_key string = "8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009"
+
+func newTestMasterKey(t *testing.T) []byte {
+	key := make([]byte, MASTER_KEY_LENGTH)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func newTestKeyring(t *testing.T, current []byte, previous ...[]byte) *Keyring {
+	k, err := NewKeyring(current, previous...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return k
+}
+
+func TestParseMasterKey(t *testing.T) {
+	key := newTestMasterKey(t)
+
+	actual, err := ParseMasterKey(hex.EncodeToString(key) + "\n")
+	assert.NoError(t, err)
+	assert.Equal(t, key, actual)
+
+	for _, v := range []string{"", "zz", hex.EncodeToString(key[:16]), hex.EncodeToString(append(key, 0))} {
+		_, err := ParseMasterKey(v)
+		assert.ErrorIs(t, err, InvalidMasterKeyErr, v)
+	}
+}
+
+func TestReadMasterKeyFile(t *testing.T) {
+	key := newTestMasterKey(t)
+	path := filepath.Join(t.TempDir(), "master.key")
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := ReadMasterKeyFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, key, actual)
+
+	_, err = ReadMasterKeyFile(filepath.Join(t.TempDir(), "missing.key"))
+	assert.Error(t, err)
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := NewKeyring(make([]byte, 16))
+	assert.ErrorIs(t, err, InvalidMasterKeyErr)
+
+	_, err = NewKeyring(newTestMasterKey(t), make([]byte, 16))
+	assert.ErrorIs(t, err, InvalidMasterKeyErr)
+
+	key := newTestMasterKey(t)
+	assert.Equal(t, newTestKeyring(t, key).CurrentKeyId(), newTestKeyring(t, key, newTestMasterKey(t)).CurrentKeyId())
+	assert.NotEqual(t, newTestKeyring(t, key).CurrentKeyId(), newTestKeyring(t, newTestMasterKey(t)).CurrentKeyId())
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	k := newTestKeyring(t, newTestMasterKey(t))
+
+	v1, err := k.Encrypt(test_priv_view_key)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(v1))
+	assert.NotContains(t, v1, test_priv_view_key)
+	assert.True(t, strings.HasPrefix(v1, envelope_prefix+k.CurrentKeyId()+":"))
+
+	// A new data key and nonce every time
+	v2, err := k.Encrypt(test_priv_view_key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, v1, v2)
+
+	for _, v := range []string{v1, v2} {
+		plaintext, err := k.Decrypt(v)
+		assert.NoError(t, err)
+		assert.Equal(t, test_priv_view_key, plaintext)
+	}
+
+	t.Run("Unknown Master Key", func(t *testing.T) {
+		_, err := newTestKeyring(t, newTestMasterKey(t)).Decrypt(v1)
+		assert.ErrorIs(t, err, UnknownMasterKeyErr)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		e, err := parseEnvelope(v1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.ciphertext[len(e.ciphertext)-1] ^= 1
+
+		for _, v := range []string{test_priv_view_key, envelope_prefix, envelope_prefix + "a:b", v1 + ":", e.String()} {
+			_, err := k.Decrypt(v)
+			assert.ErrorIs(t, err, MalformedEnvelopeErr, v)
+		}
+	})
+}
+
+func TestRewrap(t *testing.T) {
+	oldKey, newKey := newTestMasterKey(t), newTestMasterKey(t)
+	oldKeyring := newTestKeyring(t, oldKey)
+	rotationKeyring := newTestKeyring(t, newKey, oldKey)
+
+	v, err := oldKeyring.Encrypt(test_priv_view_key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewrapped, changed, err := rotationKeyring.Rewrap(v)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, strings.HasPrefix(rewrapped, envelope_prefix+rotationKeyring.CurrentKeyId()+":"))
+
+	// The ciphertext stays the same, only the data key is rewrapped
+	e, err := parseEnvelope(v)
+	assert.NoError(t, err)
+	rewrappedE, err := parseEnvelope(rewrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, e.ciphertext, rewrappedE.ciphertext)
+
+	plaintext, err := newTestKeyring(t, newKey).Decrypt(rewrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, test_priv_view_key, plaintext)
+
+	same, changed, err := rotationKeyring.Rewrap(rewrapped)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, rewrapped, same)
+
+	_, _, err = newTestKeyring(t, newTestMasterKey(t)).Rewrap(v)
+	assert.ErrorIs(t, err, UnknownMasterKeyErr)
+}
@@ -0,0 +1,296 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chekist32/goipay/internal/db"
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/chekist32/goipay/internal/processor"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DEFAULT_LIST_INVOICES_LIMIT uint32 = 100
+	MAX_LIST_INVOICES_LIMIT     uint32 = 1000
+)
+
+type AdminGrpc struct {
+	dbConnPool       *pgxpool.Pool
+	log              *zerolog.Logger
+	paymentProcessor *processor.PaymentProcessor
+	pb_v1.UnimplementedAdminServiceServer
+}
+
+// invoicesToPb attaches the payments to the invoices.
+func (a *AdminGrpc) invoicesToPb(ctx context.Context, q *db.Queries, invoices []db.Invoice) ([]*pb_v1.Invoice, error) {
+	ids := make([]pgtype.UUID, 0, len(invoices))
+	for j := 0; j < len(invoices); j++ {
+		ids = append(ids, invoices[j].ID)
+	}
+
+	payments, err := q.FindAllInvoicePaymentsByInvoiceIds(ctx, ids)
+	if err != nil {
+		a.log.Err(err).Str("queryName", "FindAllInvoicePaymentsByInvoiceIds").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	invoicePayments := make(map[[16]byte][]db.InvoicePayment, len(invoices))
+	for j := 0; j < len(payments); j++ {
+		invoicePayments[payments[j].InvoiceID.Bytes] = append(invoicePayments[payments[j].InvoiceID.Bytes], payments[j])
+	}
+
+	res := make([]*pb_v1.Invoice, 0, len(invoices))
+	for j := 0; j < len(invoices); j++ {
+		res = append(res, util.DbInvoiceToPbInvoice(&invoices[j], invoicePayments[invoices[j].ID.Bytes]))
+	}
+
+	return res, nil
+}
+
+// findInvoice returns the invoice by the id, the row isn't locked as the processor updates it on its own.
+func (a *AdminGrpc) findInvoice(ctx context.Context, id string) (*db.Invoice, error) {
+	invoiceId, err := util.StringToPgUUID(id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, a.dbConnPool)
+	if err != nil {
+		a.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	invoices, err := q.FindAllInvoicesByIds(ctx, []pgtype.UUID{*invoiceId})
+	if err != nil {
+		tx.Rollback(ctx)
+		a.log.Err(err).Str("queryName", "FindAllInvoicesByIds").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	tx.Commit(ctx)
+
+	if len(invoices) == 0 {
+		return nil, status.Error(codes.NotFound, "invoice not found")
+	}
+
+	return &invoices[0], nil
+}
+
+// invoiceToPb returns the invoice along with its payments.
+func (a *AdminGrpc) invoiceToPb(ctx context.Context, invoice *db.Invoice) (*pb_v1.Invoice, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, a.dbConnPool)
+	if err != nil {
+		a.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	invoices, err := a.invoicesToPb(ctx, q, []db.Invoice{*invoice})
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return invoices[0], nil
+}
+
+func invoiceProcessingErrToStatus(err error, invoice *db.Invoice, log *zerolog.Logger) error {
+	switch {
+	case errors.Is(err, processor.UnimplementedCoinErr):
+		return status.Error(codes.Unimplemented, fmt.Sprintf("%v invoices are not enabled", invoice.Coin))
+	case errors.Is(err, processor.InvoiceNotPendingErr):
+		return status.Error(codes.FailedPrecondition, fmt.Sprintf("the invoice is %v, not pending", invoice.Status))
+	case errors.Is(err, processor.InvoiceAddressInUseErr):
+		return status.Error(codes.FailedPrecondition, "the address of the invoice is used by another pending invoice")
+	}
+
+	errMsg := "An error occurred while handling invoice."
+	log.Err(err).Msg(errMsg)
+	return status.Error(codes.Internal, errMsg)
+}
+
+func (a *AdminGrpc) ListInvoices(ctx context.Context, req *pb_v1.ListInvoicesRequest) (*pb_v1.ListInvoicesResponse, error) {
+	if err := checkIfAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	params := db.FindAllInvoicesByFilterParams{Limit: int32(DEFAULT_LIST_INVOICES_LIMIT)}
+	if req.UserId != nil {
+		userId, err := util.StringToPgUUID(req.GetUserId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid userId")
+		}
+		params.UserID = *userId
+	}
+	if req.Coin != nil {
+		coin, err := util.PbCoinToDbCoin(req.GetCoin())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid coin")
+		}
+		params.Coin = db.NullCoinType{CoinType: coin, Valid: true}
+	}
+	if req.Status != nil {
+		invoiceStatus, err := util.PbInvoiceStatusToDbInvoiceStatus(req.GetStatus())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid status")
+		}
+		params.Status = db.NullInvoiceStatusType{InvoiceStatusType: invoiceStatus, Valid: true}
+	}
+	if req.Limit != nil {
+		if req.GetLimit() == 0 || req.GetLimit() > MAX_LIST_INVOICES_LIMIT {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("limit must be from 1 to %v", MAX_LIST_INVOICES_LIMIT))
+		}
+		params.Limit = int32(req.GetLimit())
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, a.dbConnPool)
+	if err != nil {
+		a.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	invoices, err := q.FindAllInvoicesByFilter(ctx, params)
+	if err != nil {
+		tx.Rollback(ctx)
+		a.log.Err(err).Str("queryName", "FindAllInvoicesByFilter").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	pbInvoices, err := a.invoicesToPb(ctx, q, invoices)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return &pb_v1.ListInvoicesResponse{Invoices: pbInvoices}, nil
+}
+
+func (a *AdminGrpc) ExpireInvoice(ctx context.Context, req *pb_v1.ExpireInvoiceRequest) (*pb_v1.ExpireInvoiceResponse, error) {
+	if err := checkIfAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	invoice, err := a.findInvoice(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	expiredInvoice, err := a.paymentProcessor.ExpireInvoice(ctx, *invoice)
+	if err != nil {
+		return nil, invoiceProcessingErrToStatus(err, invoice, a.log)
+	}
+
+	pbInvoice, err := a.invoiceToPb(ctx, expiredInvoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb_v1.ExpireInvoiceResponse{Invoice: pbInvoice}, nil
+}
+
+func (a *AdminGrpc) RecheckInvoice(ctx context.Context, req *pb_v1.RecheckInvoiceRequest) (*pb_v1.RecheckInvoiceResponse, error) {
+	if err := checkIfAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	invoice, err := a.findInvoice(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	recheckedInvoice, err := a.paymentProcessor.RecheckInvoice(ctx, *invoice)
+	if err != nil {
+		return nil, invoiceProcessingErrToStatus(err, invoice, a.log)
+	}
+
+	pbInvoice, err := a.invoiceToPb(ctx, recheckedInvoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb_v1.RecheckInvoiceResponse{Invoice: pbInvoice}, nil
+}
+
+func (a *AdminGrpc) GetSyncStates(ctx context.Context, req *pb_v1.GetSyncStatesRequest) (*pb_v1.GetSyncStatesResponse, error) {
+	if err := checkIfAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, a.dbConnPool)
+	if err != nil {
+		a.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	caches, err := q.FindAllCryptoCaches(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		a.log.Err(err).Str("queryName", "FindAllCryptoCaches").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	tx.Commit(ctx)
+
+	// The enabled coins report the live height, the persisted one lags behind until the next flush
+	heights := a.paymentProcessor.LastSyncedBlockHeights()
+	syncLag := a.paymentProcessor.SyncLag()
+
+	states := make([]*pb_v1.SyncState, 0, len(caches))
+	for j := 0; j < len(caches); j++ {
+		state, err := util.DbCryptoCacheToPbSyncState(&caches[j])
+		if err != nil {
+			continue
+		}
+
+		if height, ok := heights[caches[j].Coin]; ok {
+			lag := syncLag[caches[j].Coin]
+			state.LastSyncedBlockHeight = height
+			state.SyncLag = &lag
+		}
+		states = append(states, state)
+	}
+
+	return &pb_v1.GetSyncStatesResponse{States: states}, nil
+}
+
+func (a *AdminGrpc) ReleaseAddresses(ctx context.Context, req *pb_v1.ReleaseAddressesRequest) (*pb_v1.ReleaseAddressesResponse, error) {
+	if err := checkIfAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var coin db.NullCoinType
+	if req.Coin != nil {
+		c, err := util.PbCoinToDbCoin(req.GetCoin())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid coin")
+		}
+		coin = db.NullCoinType{CoinType: c, Valid: true}
+	}
+	address := pgtype.Text{String: req.GetAddress(), Valid: req.Address != nil}
+
+	addrs, err := a.paymentProcessor.ReleaseStuckAddresses(ctx, coin, address)
+	if err != nil {
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	res := make([]string, 0, len(addrs))
+	for j := 0; j < len(addrs); j++ {
+		res = append(res, addrs[j].Address)
+	}
+
+	return &pb_v1.ReleaseAddressesResponse{Addresses: res}, nil
+}
+
+func NewAdminGrpc(dbConnPool *pgxpool.Pool, paymentProcessor *processor.PaymentProcessor, log *zerolog.Logger) *AdminGrpc {
+	return &AdminGrpc{dbConnPool: dbConnPool, paymentProcessor: paymentProcessor, log: log}
+}
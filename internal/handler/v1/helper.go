@@ -3,6 +3,7 @@ package v1
 import (
 	"context"
 
+	"github.com/chekist32/goipay/internal/auth"
 	"github.com/chekist32/goipay/internal/db"
 	"github.com/chekist32/goipay/internal/util"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -34,3 +35,32 @@ func checkIfUserExistsUUID(ctx context.Context, log *zerolog.Logger, q *db.Queri
 
 	return nil
 }
+
+func checkIfUserAccessibleString(ctx context.Context, log *zerolog.Logger, userId string) error {
+	userIdUUID, err := util.StringToPgUUID(userId)
+	if err != nil {
+		log.Err(err).Msg("An error occurred while converting the string to the PostgreSQL UUID data type.")
+		return status.Error(codes.InvalidArgument, "Invalid userId")
+	}
+
+	return checkIfUserAccessibleUUID(ctx, *userIdUUID)
+}
+
+// checkIfUserAccessibleUUID checks that the api key of the request belongs to the user or to an admin.
+func checkIfUserAccessibleUUID(ctx context.Context, userId pgtype.UUID) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok || !p.CanAccessUser(userId) {
+		return status.Error(codes.PermissionDenied, "the api key can't access this user")
+	}
+
+	return nil
+}
+
+func checkIfAdmin(ctx context.Context) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok || !p.Admin {
+		return status.Error(codes.PermissionDenied, "an admin api key is required")
+	}
+
+	return nil
+}
@@ -2,7 +2,13 @@ package v1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 
+	"github.com/chekist32/goipay/internal/auth"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/dto"
 	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
 	"github.com/chekist32/goipay/internal/processor"
 	"github.com/chekist32/goipay/internal/util"
@@ -10,6 +16,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -20,7 +27,27 @@ type InvoiceGrpc struct {
 	pb_v1.UnimplementedInvoiceServiceServer
 }
 
+// newInvoiceErrToStatus maps the errors of PaymentProcessor.HandleNewInvoice caused by the request to the gRPC status, nil is returned for the rest.
+func newInvoiceErrToStatus(err error, coin pb_v1.CoinType) error {
+	switch {
+	case errors.Is(err, processor.UnimplementedCoinErr):
+		return status.Error(codes.Unimplemented, fmt.Sprintf("%v invoices are not enabled", coin))
+	case errors.Is(err, processor.UnsupportedTokenErr):
+		return status.Error(codes.InvalidArgument, "unsupported token")
+	case errors.Is(err, processor.InvalidToleranceErr):
+		return status.Error(codes.InvalidArgument, "tolerance must be a percent from 0 to 100 or an amount not exceeding the invoice amount")
+	case errors.Is(err, processor.InvalidAmountErr):
+		return status.Error(codes.InvalidArgument, "amount has more decimals than the asset")
+	}
+
+	return nil
+}
+
 func (i *InvoiceGrpc) CreateInvoice(ctx context.Context, req *pb_v1.CreateInvoiceRequest) (*pb_v1.CreateInvoiceResponse, error) {
+	if err := checkIfUserAccessibleString(ctx, i.log, req.UserId); err != nil {
+		return nil, err
+	}
+
 	q, tx, err := util.InitDbQueriesWithTx(ctx, i.dbConnPool)
 	if err != nil {
 		i.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
@@ -35,8 +62,20 @@ func (i *InvoiceGrpc) CreateInvoice(ctx context.Context, req *pb_v1.CreateInvoic
 		return nil, err
 	}
 
-	invoice, err := i.paymentProcessor.HandleNewInvoice(util.PbNewInvoiceToProcessorNewInvoice(req))
+	newInvoiceReq, err := util.PbNewInvoiceToProcessorNewInvoice(req)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, status.Error(codes.InvalidArgument, "invalid amountAtomic or tolerance")
+	}
+
+	invoice, err := i.paymentProcessor.HandleNewInvoice(ctx, newInvoiceReq)
 	if err != nil {
+		tx.Rollback(ctx)
+
+		if st := newInvoiceErrToStatus(err, req.Coin); st != nil {
+			return nil, st
+		}
+
 		errMsg := "An error occurred while handling invoice."
 		i.log.Err(err).Msg(errMsg)
 		return nil, status.Error(codes.Internal, errMsg)
@@ -44,7 +83,7 @@ func (i *InvoiceGrpc) CreateInvoice(ctx context.Context, req *pb_v1.CreateInvoic
 
 	tx.Commit(ctx)
 
-	return &pb_v1.CreateInvoiceResponse{PaymentId: util.PgUUIDToString(invoice.ID), Address: invoice.CryptoAddress}, nil
+	return &pb_v1.CreateInvoiceResponse{PaymentId: util.PgUUIDToString(invoice.ID), Address: invoice.CryptoAddress, Memo: util.PgTextToStringPtr(invoice.Memo)}, nil
 }
 
 func (i *InvoiceGrpc) GetInvoices(ctx context.Context, req *pb_v1.GetInvoicesRequest) (*pb_v1.GetInvoicesResponse, error) {
@@ -72,9 +111,25 @@ func (i *InvoiceGrpc) GetInvoices(ctx context.Context, req *pb_v1.GetInvoicesReq
 		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
 	}
 
+	payments, err := q.FindAllInvoicePaymentsByInvoiceIds(ctx, ids)
+	if err != nil {
+		tx.Rollback(ctx)
+		i.log.Err(err).Str("queryName", "FindAllInvoicePaymentsByInvoiceIds").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	invoicePayments := make(map[[16]byte][]db.InvoicePayment, len(invoices))
+	for j := 0; j < len(payments); j++ {
+		invoicePayments[payments[j].InvoiceID.Bytes] = append(invoicePayments[payments[j].InvoiceID.Bytes], payments[j])
+	}
+
 	retIncoices := make([]*pb_v1.Invoice, 0, len(invoices))
-	for i := 0; i < len(invoices); i++ {
-		retIncoices = append(retIncoices, util.DbInvoiceToPbInvoice(&invoices[i]))
+	for j := 0; j < len(invoices); j++ {
+		// The invoices of other users are omitted the same way as the unknown ones
+		if checkIfUserAccessibleUUID(ctx, invoices[j].UserID) != nil {
+			continue
+		}
+		retIncoices = append(retIncoices, util.DbInvoiceToPbInvoice(&invoices[j], invoicePayments[invoices[j].ID.Bytes]))
 	}
 
 	tx.Commit(ctx)
@@ -82,22 +137,147 @@ func (i *InvoiceGrpc) GetInvoices(ctx context.Context, req *pb_v1.GetInvoicesReq
 	return &pb_v1.GetInvoicesResponse{Invoices: retIncoices}, nil
 }
 
+// findInvoicePayments returns the payments of the invoice, skipping the query for invoices that haven't received any.
+func (i *InvoiceGrpc) findInvoicePayments(ctx context.Context, invoice *db.Invoice) ([]db.InvoicePayment, error) {
+	if !invoice.ActualAmount.Valid {
+		return nil, nil
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, i.dbConnPool)
+	if err != nil {
+		i.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	payments, err := q.FindAllInvoicePaymentsByInvoiceId(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		i.log.Err(err).Str("queryName", "FindAllInvoicePaymentsByInvoiceId").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return payments, nil
+}
+
+// sendInvoiceEvent sends the invoice along with its payments to the stream.
+func (i *InvoiceGrpc) sendInvoiceEvent(stream pb_v1.InvoiceService_InvoiceStatusStreamServer, event *dto.InvoiceEvent) error {
+	payments, err := i.findInvoicePayments(stream.Context(), &event.Invoice)
+	if err != nil {
+		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	if err := stream.Send(&pb_v1.InvoiceStatusStreamResponse{Invoice: util.DbInvoiceToPbInvoice(&event.Invoice, payments), Sequence: uint64(event.Sequence)}); err != nil {
+		errMsg := "An error occured while sending data"
+		i.log.Err(err).Msg(errMsg)
+		return status.Error(codes.Canceled, errMsg)
+	}
+
+	return nil
+}
+
+// replayInvoiceEvents sends every persisted event after fromSequence and returns the sequence of the last one.
+func (i *InvoiceGrpc) replayInvoiceEvents(stream pb_v1.InvoiceService_InvoiceStatusStreamServer, fromSequence int64, filter *dto.InvoiceFilter) (int64, error) {
+	for {
+		events, nextSequence, err := i.paymentProcessor.ReplayInvoiceEvents(stream.Context(), fromSequence, filter)
+		if err != nil {
+			return fromSequence, status.Error(codes.Internal, "An error occurred while replaying invoice events.")
+		}
+		if nextSequence == fromSequence {
+			return fromSequence, nil
+		}
+
+		for j := 0; j < len(events); j++ {
+			if err := i.sendInvoiceEvent(stream, &events[j]); err != nil {
+				return fromSequence, err
+			}
+		}
+		fromSequence = nextSequence
+	}
+}
+
+// scopeInvoiceFilter restricts the filter to the user of the api key, admins can stream the invoices of any user.
+func scopeInvoiceFilter(ctx context.Context, filter *dto.InvoiceFilter) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "the api key can't access this user")
+	}
+	if p.Admin {
+		return nil
+	}
+
+	if len(filter.UserIds) == 0 {
+		filter.UserIds = []pgtype.UUID{p.UserId}
+		return nil
+	}
+	for j := 0; j < len(filter.UserIds); j++ {
+		if !p.CanAccessUser(filter.UserIds[j]) {
+			return status.Error(codes.PermissionDenied, "the api key can't access this user")
+		}
+	}
+
+	return nil
+}
+
 func (i *InvoiceGrpc) InvoiceStatusStream(req *pb_v1.InvoiceStatusStreamRequest, stream pb_v1.InvoiceService_InvoiceStatusStreamServer) error {
-	invoiceCn := i.paymentProcessor.NewInvoicesChan()
+	filter, err := util.PbInvoiceStatusStreamRequestToInvoiceFilter(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid userIds, paymentIds, coins or statuses")
+	}
+	if err := scopeInvoiceFilter(stream.Context(), filter); err != nil {
+		return err
+	}
+	if req.GetFromSequence() > math.MaxInt64 {
+		return status.Error(codes.InvalidArgument, "invalid fromSequence")
+	}
+
+	lastSequence := int64(req.GetFromSequence())
+	if req.FromSequence != nil {
+		if lastSequence, err = i.replayInvoiceEvents(stream, lastSequence, filter); err != nil {
+			return err
+		}
+	}
+
+	invoiceCn := i.paymentProcessor.NewInvoicesChan(filter)
+
+	// Lets the clients (e.g. the SSE gateway) know the subscription is established. Fails harmlessly if the replay already sent the headers
+	stream.SendHeader(metadata.MD{})
+
+	// Events persisted while subscribing are replayed, the live copies of them are skipped below
+	if req.FromSequence != nil {
+		if lastSequence, err = i.replayInvoiceEvents(stream, lastSequence, filter); err != nil {
+			return err
+		}
+	}
 
 	for {
 		select {
-		case invoice := <-invoiceCn:
-			if err := stream.Send(&pb_v1.InvoiceStatusStreamResponse{Invoice: util.DbInvoiceToPbInvoice(&invoice)}); err != nil {
-				errMsg := "An error occured while sending data"
-				i.log.Err(err).Msg(errMsg)
-				return status.Error(codes.Canceled, errMsg)
+		case event := <-invoiceCn:
+			if event.Sequence != 0 && event.Sequence <= lastSequence {
+				continue
+			}
+
+			if err := i.sendInvoiceEvent(stream, &event); err != nil {
+				return err
 			}
 		case <-stream.Context().Done():
 			return status.Error(codes.Canceled, "stream has been closed")
+		case <-i.paymentProcessor.SubscriptionsClosed():
+			return status.Error(codes.Unavailable, "server is shutting down")
 		}
 	}
+}
+
+func (i *InvoiceGrpc) ListSupportedAssets(ctx context.Context, req *pb_v1.ListSupportedAssetsRequest) (*pb_v1.ListSupportedAssetsResponse, error) {
+	assets := i.paymentProcessor.SupportedAssets()
+
+	pbAssets := make([]*pb_v1.Asset, 0, len(assets))
+	for j := 0; j < len(assets); j++ {
+		pbAssets = append(pbAssets, util.DtoAssetToPbAsset(&assets[j]))
+	}
 
+	return &pb_v1.ListSupportedAssetsResponse{Assets: pbAssets}, nil
 }
 
 func NewInvoiceGrpc(dbConnPool *pgxpool.Pool, paymentProcessor *processor.PaymentProcessor, log *zerolog.Logger) *InvoiceGrpc {
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	INVOICE_EVENTS_SSE_PATH string = "/v1/invoices/events"
+
+	invoice_status_stream_method string        = "/invoice.v1.InvoiceService/InvoiceStatusStream"
+	sse_keep_alive_interval      time.Duration = 15 * time.Second
+)
+
+// InvoiceEventsSse mirrors InvoiceService.InvoiceStatusStream as Server-Sent Events.
+// The query parameters are the same as the fields of InvoiceStatusStreamRequest, e.g. ?coins=XMR&statuses=CONFIRMED.
+// The id of every event is its sequence, so reconnecting EventSource clients resume the stream through the Last-Event-ID header.
+type InvoiceEventsSse struct {
+	log    *zerolog.Logger
+	mux    *runtime.ServeMux
+	client pb_v1.InvoiceServiceClient
+}
+
+type invoiceStreamRecv struct {
+	res *pb_v1.InvoiceStatusStreamResponse
+	err error
+}
+
+func (i *InvoiceEventsSse) Handle(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	_, marshaler := runtime.MarshalerForRequest(i.mux, r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	ctx, err := runtime.AnnotateContext(ctx, i.mux, r, invoice_status_stream_method)
+	ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{})
+	if err != nil {
+		runtime.HTTPError(ctx, i.mux, marshaler, w, r, err)
+		return
+	}
+
+	var req pb_v1.InvoiceStatusStreamRequest
+	if err := runtime.PopulateQueryParameters(&req, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+		runtime.HTTPError(ctx, i.mux, marshaler, w, r, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+	if lastEventId := r.Header.Get("Last-Event-ID"); req.FromSequence == nil && lastEventId != "" {
+		seq, err := strconv.ParseUint(lastEventId, 10, 64)
+		if err != nil {
+			runtime.HTTPError(ctx, i.mux, marshaler, w, r, status.Error(codes.InvalidArgument, "invalid Last-Event-ID"))
+			return
+		}
+		req.FromSequence = &seq
+	}
+
+	stream, err := i.client.InvoiceStatusStream(ctx, &req)
+	if err != nil {
+		runtime.HTTPError(ctx, i.mux, marshaler, w, r, err)
+		return
+	}
+	// Blocks until the subscription is established, so invalid filters are still reported with a proper status code
+	md, err := stream.Header()
+	if err == nil && md == nil {
+		// The stream has been closed without headers, the status is returned by Recv
+		_, err = stream.Recv()
+	}
+	// An empty stream ended normally is served as such
+	if err != nil && err != io.EOF {
+		runtime.HTTPError(ctx, i.mux, marshaler, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	recvCn := make(chan invoiceStreamRecv)
+	go func() {
+		for {
+			res, err := stream.Recv()
+			select {
+			case recvCn <- invoiceStreamRecv{res: res, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(sse_keep_alive_interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case recv := <-recvCn:
+			if recv.err != nil {
+				if recv.err != io.EOF && ctx.Err() == nil {
+					i.log.Err(recv.err).Msg("Invoice status stream has been closed")
+					data, _ := marshaler.Marshal(status.Convert(recv.err).Proto())
+					fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+					flusher.Flush()
+				}
+				return
+			}
+
+			data, err := marshaler.Marshal(recv.res.Invoice)
+			if err != nil {
+				i.log.Err(err).Msg("Failed to marshal the invoice")
+				return
+			}
+			if recv.res.Sequence != 0 {
+				fmt.Fprintf(w, "id: %d\n", recv.res.Sequence)
+			}
+			fmt.Fprintf(w, "event: invoice\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func NewInvoiceEventsSse(mux *runtime.ServeMux, client pb_v1.InvoiceServiceClient, log *zerolog.Logger) *InvoiceEventsSse {
+	return &InvoiceEventsSse{mux: mux, client: client, log: log}
+}
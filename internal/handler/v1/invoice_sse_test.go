@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeInvoiceStream sends the canned events and ends the stream with err.
+type fakeInvoiceStream struct {
+	pb_v1.UnimplementedInvoiceServiceServer
+
+	req    *pb_v1.InvoiceStatusStreamRequest
+	events []*pb_v1.InvoiceStatusStreamResponse
+	err    error
+}
+
+func (f *fakeInvoiceStream) InvoiceStatusStream(req *pb_v1.InvoiceStatusStreamRequest, stream pb_v1.InvoiceService_InvoiceStatusStreamServer) error {
+	f.req = req
+	for _, e := range f.events {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+
+	return f.err
+}
+
+func newTestInvoiceEventsSse(t *testing.T, srv pb_v1.InvoiceServiceServer) *InvoiceEventsSse {
+	lis := bufconn.Listen(1024 * 1024)
+	g := grpc.NewServer()
+	pb_v1.RegisterInvoiceServiceServer(g, srv)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///sse",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewInvoiceEventsSse(runtime.NewServeMux(), pb_v1.NewInvoiceServiceClient(conn), &zerolog.Logger{})
+}
+
+func serveSse(sse *InvoiceEventsSse, target string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		r.Header.Set(k, v[0])
+	}
+	w := httptest.NewRecorder()
+	sse.Handle(w, r, nil)
+	return w
+}
+
+func TestInvoiceEventsSse(t *testing.T) {
+	t.Run("Should Stream Invoice Events", func(t *testing.T) {
+		srv := &fakeInvoiceStream{events: []*pb_v1.InvoiceStatusStreamResponse{
+			{Invoice: &pb_v1.Invoice{Id: "id1", Status: pb_v1.InvoiceStatusType_PENDING_MEMPOOL}, Sequence: 42},
+			{Invoice: &pb_v1.Invoice{Id: "id1", Status: pb_v1.InvoiceStatusType_CONFIRMED}, Sequence: 43},
+		}}
+		sse := newTestInvoiceEventsSse(t, srv)
+
+		w := serveSse(sse, INVOICE_EVENTS_SSE_PATH+"?coins=XMR&statuses=CONFIRMED&userIds=u1&userIds=u2", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+		assert.True(t, proto.Equal(&pb_v1.InvoiceStatusStreamRequest{
+			UserIds:  []string{"u1", "u2"},
+			Coins:    []pb_v1.CoinType{pb_v1.CoinType_XMR},
+			Statuses: []pb_v1.InvoiceStatusType{pb_v1.InvoiceStatusType_CONFIRMED},
+		}, srv.req), "request %v", srv.req)
+
+		events := strings.Split(strings.TrimSpace(w.Body.String()), "\n\n")
+		if assert.Equal(t, 2, len(events)) {
+			assert.True(t, strings.HasPrefix(events[0], "id: 42\nevent: invoice\ndata: {"))
+			assert.Contains(t, events[0], `"status":"PENDING_MEMPOOL"`)
+			assert.True(t, strings.HasPrefix(events[1], "id: 43\nevent: invoice\ndata: {"))
+			assert.Contains(t, events[1], `"status":"CONFIRMED"`)
+		}
+	})
+
+	t.Run("Should Resume From Last-Event-ID", func(t *testing.T) {
+		srv := &fakeInvoiceStream{}
+		sse := newTestInvoiceEventsSse(t, srv)
+
+		w := serveSse(sse, INVOICE_EVENTS_SSE_PATH, http.Header{"Last-Event-ID": {"41"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, uint64(41), srv.req.GetFromSequence())
+
+		// fromSequence takes precedence over the header
+		w = serveSse(sse, INVOICE_EVENTS_SSE_PATH+"?fromSequence=7", http.Header{"Last-Event-ID": {"41"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, uint64(7), srv.req.GetFromSequence())
+	})
+
+	t.Run("Should Return 400 (invalid Last-Event-ID)", func(t *testing.T) {
+		srv := &fakeInvoiceStream{}
+		sse := newTestInvoiceEventsSse(t, srv)
+
+		w := serveSse(sse, INVOICE_EVENTS_SSE_PATH, http.Header{"Last-Event-ID": {"abc"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Nil(t, srv.req)
+	})
+
+	t.Run("Should Return 400 (invalid query parameter)", func(t *testing.T) {
+		sse := newTestInvoiceEventsSse(t, &fakeInvoiceStream{})
+
+		w := serveSse(sse, INVOICE_EVENTS_SSE_PATH+"?coins=DOGE", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Should Map Status Before Streaming", func(t *testing.T) {
+		sse := newTestInvoiceEventsSse(t, &fakeInvoiceStream{err: status.Error(codes.PermissionDenied, "denied")})
+
+		w := serveSse(sse, INVOICE_EVENTS_SSE_PATH, nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.NotEqual(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "denied")
+	})
+
+	t.Run("Should Send Error Event (stream failed midway)", func(t *testing.T) {
+		sse := newTestInvoiceEventsSse(t, &fakeInvoiceStream{
+			events: []*pb_v1.InvoiceStatusStreamResponse{{Invoice: &pb_v1.Invoice{Id: "id1"}, Sequence: 1}},
+			err:    status.Error(codes.Internal, "broken"),
+		})
+
+		w := serveSse(sse, INVOICE_EVENTS_SSE_PATH, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		events := strings.Split(strings.TrimSpace(w.Body.String()), "\n\n")
+		if assert.Equal(t, 2, len(events)) {
+			assert.True(t, strings.HasPrefix(events[0], "id: 1\nevent: invoice\n"))
+			assert.True(t, strings.HasPrefix(events[1], "event: error\ndata: {"))
+			assert.Contains(t, events[1], "broken")
+		}
+	})
+}
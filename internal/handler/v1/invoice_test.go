@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/chekist32/goipay/internal/processor"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewInvoiceErrToStatus(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected codes.Code
+	}{
+		{err: processor.UnimplementedCoinErr, expected: codes.Unimplemented},
+		{err: fmt.Errorf("wrapped: %w", processor.UnimplementedCoinErr), expected: codes.Unimplemented},
+		{err: processor.UnsupportedTokenErr, expected: codes.InvalidArgument},
+		{err: processor.InvalidToleranceErr, expected: codes.InvalidArgument},
+		{err: processor.InvalidAmountErr, expected: codes.InvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tc.expected, status.Code(newInvoiceErrToStatus(tc.err, pb_v1.CoinType_BTC)))
+		})
+	}
+
+	t.Run("Unexpected Error", func(t *testing.T) {
+		assert.Nil(t, newInvoiceErrToStatus(errors.New("unexpected"), pb_v1.CoinType_BTC))
+	})
+}
@@ -2,11 +2,17 @@ package v1
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/chekist32/go-monero/utils"
+	"github.com/chekist32/goipay/internal/auth"
 	"github.com/chekist32/goipay/internal/db"
 	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	"github.com/chekist32/goipay/internal/processor"
 	"github.com/chekist32/goipay/internal/util"
+	"github.com/chekist32/goipay/internal/webhook"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
@@ -15,8 +21,9 @@ import (
 )
 
 type UserGrpc struct {
-	dbConnPool *pgxpool.Pool
-	log        *zerolog.Logger
+	dbConnPool       *pgxpool.Pool
+	log              *zerolog.Logger
+	paymentProcessor *processor.PaymentProcessor
 	pb_v1.UnimplementedUserServiceServer
 }
 
@@ -49,6 +56,10 @@ func (u *UserGrpc) createUser(ctx context.Context, q *db.Queries, in *pb_v1.Regi
 }
 
 func (u *UserGrpc) RegisterUser(ctx context.Context, in *pb_v1.RegisterUserRequest) (*pb_v1.RegisterUserResponse, error) {
+	if err := checkIfAdmin(ctx); err != nil {
+		return nil, err
+	}
+
 	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
 	if err != nil {
 		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
@@ -73,38 +84,188 @@ func (u *UserGrpc) RegisterUser(ctx context.Context, in *pb_v1.RegisterUserReque
 	return &pb_v1.RegisterUserResponse{UserId: util.PgUUIDToString(*userId)}, nil
 }
 
-func (u *UserGrpc) handleXmrCryptoDataUpdate(ctx context.Context, q *db.Queries, in *pb_v1.XmrKeysUpdateRequest, cryptData *db.CryptoDatum) error {
-	_, err := utils.NewPrivateKey(in.PrivViewKey)
+// UpdateXmrCryptoData validates the XMR keys and stores them as the XMR crypto data of the user, the private view key is passed through encrypt first.
+// The addresses derived from the old keys are dropped. It's shared by UpdateCryptoKeys and the admin cli talking to the database directly.
+func UpdateXmrCryptoData(ctx context.Context, log *zerolog.Logger, q *db.Queries, encrypt func(privViewKey string) (string, error), cryptData *db.CryptoDatum, privViewKey string, pubSpendKey string) error {
+	_, err := utils.NewPrivateKey(privViewKey)
 	if err != nil {
-		u.log.Err(err).Msg("An error occurred while creating the XMR private view key.")
+		log.Err(err).Msg("An error occurred while creating the XMR private view key.")
 		return status.Error(codes.InvalidArgument, "invalid private view key")
 	}
-	_, err = utils.NewPublicKey(in.PubSpendKey)
+	_, err = utils.NewPublicKey(pubSpendKey)
 	if err != nil {
-		u.log.Err(err).Msg("An error occurred while creating the XMR public spend key.")
+		log.Err(err).Msg("An error occurred while creating the XMR public spend key.")
 		return status.Error(codes.InvalidArgument, "invalid public spend key")
 	}
 
+	privViewKey, err = encrypt(privViewKey)
+	if err != nil {
+		log.Err(err).Msg("An error occurred while encrypting the XMR private view key.")
+		return status.Error(codes.Internal, "An error occurred while encrypting the XMR private view key.")
+	}
+
 	_, err = q.DeleteAllCryptoAddressByUserIdAndCoin(ctx, db.DeleteAllCryptoAddressByUserIdAndCoinParams{Coin: db.CoinTypeXMR, UserID: cryptData.UserID})
 	if err != nil {
-		u.log.Err(err).Str("queryName", "DeleteAllCryptoAddressByUserIdAndCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		log.Err(err).Str("queryName", "DeleteAllCryptoAddressByUserIdAndCoin").Msg(util.DefaultFailedSqlQueryMsg)
 		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
 	}
 
 	if !cryptData.XmrID.Valid {
-		xmrData, err := q.CreateXMRCryptoData(ctx, db.CreateXMRCryptoDataParams{PrivViewKey: in.PrivViewKey, PubSpendKey: in.PubSpendKey})
+		xmrData, err := q.CreateXMRCryptoData(ctx, db.CreateXMRCryptoDataParams{PrivViewKey: privViewKey, PubSpendKey: pubSpendKey})
 		if err != nil {
-			u.log.Err(err).Str("queryName", "CreateXMRCryptoData").Msg(util.DefaultFailedSqlQueryMsg)
+			log.Err(err).Str("queryName", "CreateXMRCryptoData").Msg(util.DefaultFailedSqlQueryMsg)
 			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
 		}
 		_, err = q.SetXMRCryptoDataByUserId(ctx, db.SetXMRCryptoDataByUserIdParams{UserID: cryptData.UserID, XmrID: xmrData.ID})
 		if err != nil {
-			u.log.Err(err).Str("queryName", "SetXMRCryptoDataByUserId").Msg(util.DefaultFailedSqlQueryMsg)
+			log.Err(err).Str("queryName", "SetXMRCryptoDataByUserId").Msg(util.DefaultFailedSqlQueryMsg)
+			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+		}
+		return nil
+	}
+	_, err = q.UpdateKeysXMRCryptoDataById(ctx, db.UpdateKeysXMRCryptoDataByIdParams{ID: cryptData.XmrID, PrivViewKey: privViewKey, PubSpendKey: pubSpendKey})
+	if err != nil {
+		log.Err(err).Str("queryName", "UpdateKeysXMRCryptoDataById").Msg(util.DefaultFailedSqlQueryMsg)
+		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	return nil
+}
+
+// utxoCryptoData is everything specific to the crypto data of a UTXO coin, the coins differ only in the chain and the queries of their table.
+type utxoCryptoData struct {
+	coin   db.CoinType
+	chain  *util.UtxoChainParams
+	id     func(cd *db.CryptoDatum) pgtype.UUID
+	create func(ctx context.Context, q *db.Queries, masterPubKey string) (pgtype.UUID, error)
+	set    func(ctx context.Context, q *db.Queries, userId pgtype.UUID, id pgtype.UUID) error
+	update func(ctx context.Context, q *db.Queries, id pgtype.UUID, masterPubKey string) error
+}
+
+var (
+	btcCryptoData utxoCryptoData = utxoCryptoData{
+		coin:  db.CoinTypeBTC,
+		chain: &util.BtcChainParams,
+		id:    func(cd *db.CryptoDatum) pgtype.UUID { return cd.BtcID },
+		create: func(ctx context.Context, q *db.Queries, masterPubKey string) (pgtype.UUID, error) {
+			btcData, err := q.CreateBTCCryptoData(ctx, masterPubKey)
+			return btcData.ID, err
+		},
+		set: func(ctx context.Context, q *db.Queries, userId pgtype.UUID, id pgtype.UUID) error {
+			_, err := q.SetBTCCryptoDataByUserId(ctx, db.SetBTCCryptoDataByUserIdParams{UserID: userId, BtcID: id})
+			return err
+		},
+		update: func(ctx context.Context, q *db.Queries, id pgtype.UUID, masterPubKey string) error {
+			_, err := q.UpdateKeysBTCCryptoDataById(ctx, db.UpdateKeysBTCCryptoDataByIdParams{ID: id, MasterPubKey: masterPubKey})
+			return err
+		},
+	}
+	ltcCryptoData utxoCryptoData = utxoCryptoData{
+		coin:  db.CoinTypeLTC,
+		chain: &util.LtcChainParams,
+		id:    func(cd *db.CryptoDatum) pgtype.UUID { return cd.LtcID },
+		create: func(ctx context.Context, q *db.Queries, masterPubKey string) (pgtype.UUID, error) {
+			ltcData, err := q.CreateLTCCryptoData(ctx, masterPubKey)
+			return ltcData.ID, err
+		},
+		set: func(ctx context.Context, q *db.Queries, userId pgtype.UUID, id pgtype.UUID) error {
+			_, err := q.SetLTCCryptoDataByUserId(ctx, db.SetLTCCryptoDataByUserIdParams{UserID: userId, LtcID: id})
+			return err
+		},
+		update: func(ctx context.Context, q *db.Queries, id pgtype.UUID, masterPubKey string) error {
+			_, err := q.UpdateKeysLTCCryptoDataById(ctx, db.UpdateKeysLTCCryptoDataByIdParams{ID: id, MasterPubKey: masterPubKey})
+			return err
+		},
+	}
+)
+
+func (u *UserGrpc) handleUtxoCryptoDataUpdate(ctx context.Context, q *db.Queries, c *utxoCryptoData, masterPubKey string, cryptData *db.CryptoDatum) error {
+	_, err := c.chain.NewMasterPubKey(masterPubKey)
+	if err != nil {
+		u.log.Err(err).Msgf("An error occurred while creating the %v master public key.", c.coin)
+		return status.Error(codes.InvalidArgument, "invalid master public key")
+	}
+
+	_, err = q.DeleteAllCryptoAddressByUserIdAndCoin(ctx, db.DeleteAllCryptoAddressByUserIdAndCoinParams{Coin: c.coin, UserID: cryptData.UserID})
+	if err != nil {
+		u.log.Err(err).Str("queryName", "DeleteAllCryptoAddressByUserIdAndCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	if !c.id(cryptData).Valid {
+		id, err := c.create(ctx, q, masterPubKey)
+		if err != nil {
+			u.log.Err(err).Str("queryName", fmt.Sprintf("Create%vCryptoData", c.coin)).Msg(util.DefaultFailedSqlQueryMsg)
+			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+		}
+		if err := c.set(ctx, q, cryptData.UserID, id); err != nil {
+			u.log.Err(err).Str("queryName", fmt.Sprintf("Set%vCryptoDataByUserId", c.coin)).Msg(util.DefaultFailedSqlQueryMsg)
 			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
 		}
 		return nil
 	}
-	_, err = q.UpdateKeysXMRCryptoDataById(ctx, db.UpdateKeysXMRCryptoDataByIdParams{ID: cryptData.XmrID, PrivViewKey: in.PrivViewKey, PubSpendKey: in.PubSpendKey})
+	if err := c.update(ctx, q, c.id(cryptData), masterPubKey); err != nil {
+		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	return nil
+}
+
+func (u *UserGrpc) handleEthCryptoDataUpdate(ctx context.Context, q *db.Queries, in *pb_v1.EthKeysUpdateRequest, cryptData *db.CryptoDatum) error {
+	_, err := util.NewEthMasterPubKey(in.MasterPubKey)
+	if err != nil {
+		u.log.Err(err).Msg("An error occurred while creating the ETH master public key.")
+		return status.Error(codes.InvalidArgument, "invalid master public key")
+	}
+
+	_, err = q.DeleteAllCryptoAddressByUserIdAndCoin(ctx, db.DeleteAllCryptoAddressByUserIdAndCoinParams{Coin: db.CoinTypeETH, UserID: cryptData.UserID})
+	if err != nil {
+		u.log.Err(err).Str("queryName", "DeleteAllCryptoAddressByUserIdAndCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	if !cryptData.EthID.Valid {
+		ethData, err := q.CreateETHCryptoData(ctx, in.MasterPubKey)
+		if err != nil {
+			u.log.Err(err).Str("queryName", "CreateETHCryptoData").Msg(util.DefaultFailedSqlQueryMsg)
+			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+		}
+		_, err = q.SetETHCryptoDataByUserId(ctx, db.SetETHCryptoDataByUserIdParams{UserID: cryptData.UserID, EthID: ethData.ID})
+		if err != nil {
+			u.log.Err(err).Str("queryName", "SetETHCryptoDataByUserId").Msg(util.DefaultFailedSqlQueryMsg)
+			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+		}
+		return nil
+	}
+	_, err = q.UpdateKeysETHCryptoDataById(ctx, db.UpdateKeysETHCryptoDataByIdParams{ID: cryptData.EthID, MasterPubKey: in.MasterPubKey})
+	if err != nil {
+		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	return nil
+}
+
+func (u *UserGrpc) handleTonCryptoDataUpdate(ctx context.Context, q *db.Queries, in *pb_v1.TonKeysUpdateRequest, cryptData *db.CryptoDatum) error {
+	_, err := util.ParseTonAddress(in.Address)
+	if err != nil {
+		u.log.Err(err).Msg("An error occurred while parsing the TON address.")
+		return status.Error(codes.InvalidArgument, "invalid address")
+	}
+
+	if !cryptData.TonID.Valid {
+		tonData, err := q.CreateTONCryptoData(ctx, in.Address)
+		if err != nil {
+			u.log.Err(err).Str("queryName", "CreateTONCryptoData").Msg(util.DefaultFailedSqlQueryMsg)
+			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+		}
+		_, err = q.SetTONCryptoDataByUserId(ctx, db.SetTONCryptoDataByUserIdParams{UserID: cryptData.UserID, TonID: tonData.ID})
+		if err != nil {
+			u.log.Err(err).Str("queryName", "SetTONCryptoDataByUserId").Msg(util.DefaultFailedSqlQueryMsg)
+			return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+		}
+		return nil
+	}
+	_, err = q.UpdateAddressTONCryptoDataById(ctx, db.UpdateAddressTONCryptoDataByIdParams{ID: cryptData.TonID, Address: in.Address})
 	if err != nil {
 		return status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
 	}
@@ -113,6 +274,10 @@ func (u *UserGrpc) handleXmrCryptoDataUpdate(ctx context.Context, q *db.Queries,
 }
 
 func (u *UserGrpc) UpdateCryptoKeys(ctx context.Context, in *pb_v1.UpdateCryptoKeysRequest) (*pb_v1.UpdateCryptoKeysResponse, error) {
+	if err := checkIfUserAccessibleString(ctx, u.log, in.UserId); err != nil {
+		return nil, err
+	}
+
 	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
 	if err != nil {
 		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
@@ -139,7 +304,39 @@ func (u *UserGrpc) UpdateCryptoKeys(ctx context.Context, in *pb_v1.UpdateCryptoK
 	}
 
 	if in.XmrReq != nil {
-		if err := u.handleXmrCryptoDataUpdate(ctx, q, in.XmrReq, &cryptData); err != nil {
+		if err := UpdateXmrCryptoData(ctx, u.log, q, u.paymentProcessor.EncryptXmrPrivViewKey, &cryptData, in.XmrReq.PrivViewKey, in.XmrReq.PubSpendKey); err != nil {
+			tx.Rollback(ctx)
+			u.log.Err(err).Msg("")
+			return nil, err
+		}
+	}
+
+	if in.BtcReq != nil {
+		if err := u.handleUtxoCryptoDataUpdate(ctx, q, &btcCryptoData, in.BtcReq.MasterPubKey, &cryptData); err != nil {
+			tx.Rollback(ctx)
+			u.log.Err(err).Msg("")
+			return nil, err
+		}
+	}
+
+	if in.LtcReq != nil {
+		if err := u.handleUtxoCryptoDataUpdate(ctx, q, &ltcCryptoData, in.LtcReq.MasterPubKey, &cryptData); err != nil {
+			tx.Rollback(ctx)
+			u.log.Err(err).Msg("")
+			return nil, err
+		}
+	}
+
+	if in.EthReq != nil {
+		if err := u.handleEthCryptoDataUpdate(ctx, q, in.EthReq, &cryptData); err != nil {
+			tx.Rollback(ctx)
+			u.log.Err(err).Msg("")
+			return nil, err
+		}
+	}
+
+	if in.TonReq != nil {
+		if err := u.handleTonCryptoDataUpdate(ctx, q, in.TonReq, &cryptData); err != nil {
 			tx.Rollback(ctx)
 			u.log.Err(err).Msg("")
 			return nil, err
@@ -152,6 +349,15 @@ func (u *UserGrpc) UpdateCryptoKeys(ctx context.Context, in *pb_v1.UpdateCryptoK
 }
 
 func (u *UserGrpc) GetCryptoKeys(ctx context.Context, in *pb_v1.GetCryptoKeysRequest) (*pb_v1.GetCryptoKeysResponse, error) {
+	if err := checkIfUserAccessibleString(ctx, u.log, in.UserId); err != nil {
+		return nil, err
+	}
+	if in.GetIncludePrivViewKey() {
+		if err := checkIfAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
 	if err != nil {
 		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
@@ -179,14 +385,258 @@ func (u *UserGrpc) GetCryptoKeys(ctx context.Context, in *pb_v1.GetCryptoKeysReq
 
 	tx.Commit(ctx)
 
+	// The private view key is only returned on an explicit request
+	privViewKey := ""
+	if in.GetIncludePrivViewKey() {
+		privViewKey, err = u.paymentProcessor.RevealXmrPrivViewKey(ctx, *userId)
+		if err != nil {
+			u.log.Err(err).Msg("An error occurred while decrypting the XMR private view key.")
+			if errors.Is(err, processor.UnimplementedCoinErr) {
+				return nil, status.Error(codes.Unimplemented, "XMR is not enabled")
+			}
+			return nil, status.Error(codes.Internal, "An error occurred while decrypting the XMR private view key.")
+		}
+	}
+
 	return &pb_v1.GetCryptoKeysResponse{
 		XmrKeys: &pb_v1.XmrKeys{
-			PrivViewKey: cryptoKeys.PrivViewKey,
+			PrivViewKey: privViewKey,
 			PubSpendKey: cryptoKeys.PubSpendKey,
 		},
+		BtcKeys: &pb_v1.BtcKeys{
+			MasterPubKey: cryptoKeys.BtcMasterPubKey,
+		},
+		LtcKeys: &pb_v1.LtcKeys{
+			MasterPubKey: cryptoKeys.LtcMasterPubKey,
+		},
+		EthKeys: &pb_v1.EthKeys{
+			MasterPubKey: cryptoKeys.EthMasterPubKey,
+		},
+		TonKeys: &pb_v1.TonKeys{
+			Address: cryptoKeys.TonAddress,
+		},
 	}, nil
 }
 
-func NewUserGrpc(dbConnPool *pgxpool.Pool, log *zerolog.Logger) *UserGrpc {
-	return &UserGrpc{dbConnPool: dbConnPool, log: log}
+func (u *UserGrpc) RegisterWebhook(ctx context.Context, in *pb_v1.RegisterWebhookRequest) (*pb_v1.RegisterWebhookResponse, error) {
+	if err := checkIfUserAccessibleString(ctx, u.log, in.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := webhook.ValidateUrl(in.Url); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "url must be an absolute http(s) url")
+	}
+
+	secret := in.GetSecret()
+	if secret == "" {
+		s, err := webhook.NewSecret()
+		if err != nil {
+			errMsg := "An error occurred while generating the webhook secret."
+			u.log.Err(err).Msg(errMsg)
+			return nil, status.Error(codes.Internal, errMsg)
+		}
+		secret = s
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
+	if err != nil {
+		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	userId, err := util.StringToPgUUID(in.UserId)
+	if err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Msg("An error occurred while converting the string to the PostgreSQL UUID data type.")
+		return nil, status.Error(codes.InvalidArgument, "invalid userId")
+	}
+
+	if err := checkIfUserExistsUUID(ctx, u.log, q, *userId); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	wh, err := q.CreateWebhook(ctx, db.CreateWebhookParams{UserID: *userId, Url: in.Url, Secret: secret})
+	if err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Str("queryName", "CreateWebhook").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	tx.Commit(ctx)
+
+	return &pb_v1.RegisterWebhookResponse{Webhook: util.DbWebhookToPbWebhook(&wh), Secret: secret}, nil
+}
+
+func (u *UserGrpc) ListWebhooks(ctx context.Context, in *pb_v1.ListWebhooksRequest) (*pb_v1.ListWebhooksResponse, error) {
+	if err := checkIfUserAccessibleString(ctx, u.log, in.UserId); err != nil {
+		return nil, err
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
+	if err != nil {
+		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	userId, err := util.StringToPgUUID(in.UserId)
+	if err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Msg("An error occurred while converting the string to the PostgreSQL UUID data type.")
+		return nil, status.Error(codes.InvalidArgument, "invalid userId")
+	}
+
+	webhooks, err := q.FindAllWebhooksByUserId(ctx, *userId)
+	if err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Str("queryName", "FindAllWebhooksByUserId").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	tx.Commit(ctx)
+
+	pbWebhooks := make([]*pb_v1.Webhook, 0, len(webhooks))
+	for i := 0; i < len(webhooks); i++ {
+		pbWebhooks = append(pbWebhooks, util.DbWebhookToPbWebhook(&webhooks[i]))
+	}
+
+	return &pb_v1.ListWebhooksResponse{Webhooks: pbWebhooks}, nil
+}
+
+func (u *UserGrpc) DeleteWebhook(ctx context.Context, in *pb_v1.DeleteWebhookRequest) (*pb_v1.DeleteWebhookResponse, error) {
+	if err := checkIfUserAccessibleString(ctx, u.log, in.UserId); err != nil {
+		return nil, err
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
+	if err != nil {
+		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	userId, err := util.StringToPgUUID(in.UserId)
+	if err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Msg("An error occurred while converting the string to the PostgreSQL UUID data type.")
+		return nil, status.Error(codes.InvalidArgument, "invalid userId")
+	}
+
+	webhookId, err := util.StringToPgUUID(in.WebhookId)
+	if err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Msg("An error occurred while converting the string to the PostgreSQL UUID data type.")
+		return nil, status.Error(codes.InvalidArgument, "invalid webhookId")
+	}
+
+	if _, err := q.DeleteWebhookByIdAndUserId(ctx, db.DeleteWebhookByIdAndUserIdParams{ID: *webhookId, UserID: *userId}); err != nil {
+		tx.Rollback(ctx)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "webhook not found")
+		}
+		u.log.Err(err).Str("queryName", "DeleteWebhookByIdAndUserId").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	tx.Commit(ctx)
+
+	return &pb_v1.DeleteWebhookResponse{}, nil
+}
+
+func (u *UserGrpc) CreateApiKey(ctx context.Context, in *pb_v1.CreateApiKeyRequest) (*pb_v1.CreateApiKeyResponse, error) {
+	if in.UserId == nil {
+		if err := checkIfAdmin(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := checkIfUserAccessibleString(ctx, u.log, in.GetUserId()); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := auth.NewKey()
+	if err != nil {
+		errMsg := "An error occurred while generating the api key."
+		u.log.Err(err).Msg(errMsg)
+		return nil, status.Error(codes.Internal, errMsg)
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
+	if err != nil {
+		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	params := db.CreateApiKeyParams{KeyHash: auth.HashKey(key), Role: db.ApiKeyRoleTypeADMIN}
+	if in.UserId != nil {
+		userId, err := util.StringToPgUUID(in.GetUserId())
+		if err != nil {
+			tx.Rollback(ctx)
+			u.log.Err(err).Msg("An error occurred while converting the string to the PostgreSQL UUID data type.")
+			return nil, status.Error(codes.InvalidArgument, "invalid userId")
+		}
+
+		if err := checkIfUserExistsUUID(ctx, u.log, q, *userId); err != nil {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+
+		params.Role = db.ApiKeyRoleTypeUSER
+		params.UserID = *userId
+	}
+
+	apiKey, err := q.CreateApiKey(ctx, params)
+	if err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Str("queryName", "CreateApiKey").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	tx.Commit(ctx)
+
+	return &pb_v1.CreateApiKeyResponse{Id: util.PgUUIDToString(apiKey.ID), Key: key}, nil
+}
+
+func (u *UserGrpc) RevokeApiKey(ctx context.Context, in *pb_v1.RevokeApiKeyRequest) (*pb_v1.RevokeApiKeyResponse, error) {
+	id, err := util.StringToPgUUID(in.Id)
+	if err != nil {
+		u.log.Err(err).Msg("An error occurred while converting the string to the PostgreSQL UUID data type.")
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, u.dbConnPool)
+	if err != nil {
+		u.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlTxInitMsg)
+	}
+
+	apiKey, err := q.FindActiveApiKeyByIdForUpdate(ctx, *id)
+	if err != nil {
+		tx.Rollback(ctx)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "api key not found")
+		}
+		u.log.Err(err).Str("queryName", "FindActiveApiKeyByIdForUpdate").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	// Admin keys have no user, so only admins can revoke them.
+	// The keys of other users are reported as missing, so a key id can't be probed.
+	if err := checkIfUserAccessibleUUID(ctx, apiKey.UserID); err != nil {
+		tx.Rollback(ctx)
+		return nil, status.Error(codes.NotFound, "api key not found")
+	}
+
+	if _, err := q.RevokeApiKeyById(ctx, apiKey.ID); err != nil {
+		tx.Rollback(ctx)
+		u.log.Err(err).Str("queryName", "RevokeApiKeyById").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, status.Error(codes.Internal, util.DefaultFailedSqlQueryMsg)
+	}
+
+	tx.Commit(ctx)
+
+	return &pb_v1.RevokeApiKeyResponse{}, nil
+}
+
+func NewUserGrpc(dbConnPool *pgxpool.Pool, paymentProcessor *processor.PaymentProcessor, log *zerolog.Logger) *UserGrpc {
+	return &UserGrpc{dbConnPool: dbConnPool, paymentProcessor: paymentProcessor, log: log}
 }
@@ -0,0 +1,34 @@
+package v2
+
+import (
+	"context"
+
+	handler_v1 "github.com/chekist32/goipay/internal/handler/v1"
+	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	pb_v2 "github.com/chekist32/goipay/internal/pb/v2"
+	"github.com/chekist32/goipay/internal/util"
+)
+
+// InvoiceGrpc serves the v2 invoices with the exact atomic amounts. The v1 handler does the work, so the access checks are the same.
+type InvoiceGrpc struct {
+	v1 *handler_v1.InvoiceGrpc
+	pb_v2.UnimplementedInvoiceServiceServer
+}
+
+func (i *InvoiceGrpc) GetInvoices(ctx context.Context, req *pb_v2.GetInvoicesRequest) (*pb_v2.GetInvoicesResponse, error) {
+	res, err := i.v1.GetInvoices(ctx, &pb_v1.GetInvoicesRequest{PaymentIds: req.PaymentIds})
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*pb_v2.Invoice, 0, len(res.Invoices))
+	for _, invoice := range res.Invoices {
+		invoices = append(invoices, util.PbInvoiceToPbV2Invoice(invoice))
+	}
+
+	return &pb_v2.GetInvoicesResponse{Invoices: invoices}, nil
+}
+
+func NewInvoiceGrpc(v1 *handler_v1.InvoiceGrpc) *InvoiceGrpc {
+	return &InvoiceGrpc{v1: v1}
+}
@@ -0,0 +1,133 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/eth"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// EthDaemonRpcClientExecutor follows the new heads of an Ethereum node.
+type EthDaemonRpcClientExecutor struct {
+	log *zerolog.Logger
+
+	client eth.IDaemonRpcClient
+
+	newBlockChns *util.SyncMapTypeSafe[string, chan eth.Block]
+
+	isStarted bool
+	stop      chan struct{}
+	// The sync loops and the notifications of the subscribers
+	routines util.Routines
+
+	blockSync blockSync
+}
+
+func (d *EthDaemonRpcClientExecutor) syncBlock(ctx context.Context) {
+	height, err := d.client.BlockNumber()
+	if err != nil {
+		d.log.Err(err).Str("method", "eth_blockNumber").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_blockNumber").Inc()
+		return
+	}
+	defer d.blockSync.reportHeights(string(db.CoinTypeETH), height)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if height < d.blockSync.lastBlockHeight.Load() {
+				return
+			}
+
+			block, err := d.client.GetBlockByNumber(d.blockSync.lastBlockHeight.Load())
+			if err != nil {
+				d.log.Err(err).Str("method", "eth_getBlockByNumber").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+				metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_getBlockByNumber").Inc()
+				return
+			}
+			// The node hasn't caught up with the reported head yet
+			if block == nil {
+				return
+			}
+			d.log.Info().Msgf("Synced ETH blockheight: %v", block.Number)
+
+			notifySubscribers(ctx, &d.routines, d.newBlockChns, *block, string(db.CoinTypeETH), metrics.NEW_BLOCK_CHANNEL)
+
+			d.blockSync.lastBlockHeight.Add(1)
+		}
+	}
+}
+
+func (d *EthDaemonRpcClientExecutor) sync(blockTimeout time.Duration) {
+	t := time.NewTicker(blockTimeout)
+	defer t.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d.routines.Go(func() {
+		for {
+			s := ctx.Done()
+			select {
+			case <-s:
+				return
+			case <-t.C:
+				d.syncBlock(ctx)
+			}
+		}
+	})
+
+	<-d.stop
+	d.isStarted = false
+}
+
+func (d *EthDaemonRpcClientExecutor) Start(startBlock uint64) {
+	if d.isStarted {
+		return
+	}
+	d.isStarted = true
+	d.blockSync.lastBlockHeight.Store(startBlock)
+
+	d.routines.Go(func() { d.sync(MIN_SYNC_TIMEOUT / 2) })
+}
+
+// Stop stops the syncing and returns once every goroutine of the executor has exited.
+func (d *EthDaemonRpcClientExecutor) Stop() {
+	if !d.isStarted {
+		return
+	}
+	d.stop <- struct{}{}
+	d.routines.Wait(context.Background())
+}
+
+func (d *EthDaemonRpcClientExecutor) NewBlockChan() <-chan eth.Block {
+	cn := make(chan eth.Block)
+	d.newBlockChns.Store(uuid.NewString(), cn)
+	return cn
+}
+
+func (d *EthDaemonRpcClientExecutor) LastSyncedBlockHeight() uint64 {
+	return d.blockSync.lastBlockHeight.Load()
+}
+
+// SyncLag returns the number of the daemon blocks not synced yet.
+func (d *EthDaemonRpcClientExecutor) SyncLag() uint64 {
+	return d.blockSync.syncLag()
+}
+
+func NewEthDaemonRpcClientExecutor(client eth.IDaemonRpcClient, log *zerolog.Logger) *EthDaemonRpcClientExecutor {
+	return &EthDaemonRpcClientExecutor{
+		log:          log,
+		client:       client,
+		isStarted:    false,
+		stop:         make(chan struct{}),
+		newBlockChns: &util.SyncMapTypeSafe[string, chan eth.Block]{},
+	}
+}
@@ -0,0 +1,74 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/eth"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEthDaemonRpcClient struct {
+	mock.Mock
+}
+
+func (m *MockEthDaemonRpcClient) ChainId() (uint64, error) {
+	args := m.Called()
+	return args.Get(0).(uint64), args.Error(1)
+}
+func (m *MockEthDaemonRpcClient) BlockNumber() (uint64, error) {
+	args := m.Called()
+	return args.Get(0).(uint64), args.Error(1)
+}
+func (m *MockEthDaemonRpcClient) GetBlockByNumber(number uint64) (*eth.Block, error) {
+	args := m.Called(number)
+	return args.Get(0).(*eth.Block), args.Error(1)
+}
+func (m *MockEthDaemonRpcClient) GetTransactionByHash(hash string) (*eth.Tx, error) {
+	args := m.Called(hash)
+	return args.Get(0).(*eth.Tx), args.Error(1)
+}
+func (m *MockEthDaemonRpcClient) GetTransactionReceipt(hash string) (*eth.Receipt, error) {
+	args := m.Called(hash)
+	return args.Get(0).(*eth.Receipt), args.Error(1)
+}
+
+func (m *MockEthDaemonRpcClient) GetLogs(filter *eth.LogFilter) ([]eth.Log, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]eth.Log), args.Error(1)
+}
+
+func TestEthSyncBlock(t *testing.T) {
+	lastBlockHeight := uint64(rand.Uint32())
+	expectedBlock := eth.Block{Hash: uuid.NewString(), Number: eth.Quantity(lastBlockHeight)}
+
+	d := new(MockEthDaemonRpcClient)
+	d.On("BlockNumber").Return(lastBlockHeight+1, error(nil))
+	d.On("GetBlockByNumber", lastBlockHeight).Return(&expectedBlock, error(nil))
+	// The node reported the head but hasn't served it yet
+	d.On("GetBlockByNumber", lastBlockHeight+1).Return((*eth.Block)(nil), error(nil))
+
+	ex := NewEthDaemonRpcClientExecutor(d, &zerolog.Logger{})
+	ex.blockSync.lastBlockHeight.Store(lastBlockHeight)
+	blockCn := ex.NewBlockChan()
+
+	ex.syncBlock(context.Background())
+
+	actualBlock := eth.Block{}
+	select {
+	case actualBlock = <-blockCn:
+		break
+	case <-time.After(MIN_SYNC_TIMEOUT):
+		log.Fatal(errors.New("Timeout has been expired"))
+	}
+
+	assert.Equal(t, lastBlockHeight+1, ex.LastSyncedBlockHeight())
+	assert.Equal(t, expectedBlock, actualBlock)
+}
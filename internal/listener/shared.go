@@ -1,9 +1,41 @@
 package listener
 
 import (
+	"context"
 	"time"
+
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
 )
 
 const (
 	MIN_SYNC_TIMEOUT time.Duration = 10 * time.Second
+
+	// How many of the last synced block hashes are kept to find the fork point of a reorg
+	RECENT_BLOCKS_DEPTH uint64 = 100
 )
+
+// ChainReorg is emitted when an executor finds out that blocks it has already emitted were orphaned.
+type ChainReorg struct {
+	// The first height at which the old and the new chains differ
+	ForkHeight uint64
+	// Hashes of the orphaned blocks, the lowest height first
+	OrphanedBlockHashes []string
+}
+
+// notifySubscribers sends the value to every subscriber of the channel in its own goroutine.
+// A subscriber which doesn't receive it within MIN_SYNC_TIMEOUT is dropped, the sending is abandoned once ctx is done.
+func notifySubscribers[T any](ctx context.Context, routines *util.Routines, chns *util.SyncMapTypeSafe[string, chan T], value T, coin string, channel string) {
+	chns.Range(func(key string, cn chan T) bool {
+		routines.Go(func() {
+			select {
+			case cn <- value:
+			case <-time.After(MIN_SYNC_TIMEOUT):
+				chns.Delete(key)
+				metrics.SubscriberDropsTotal.WithLabelValues(coin, channel).Inc()
+			case <-ctx.Done():
+			}
+		})
+		return true
+	})
+}
@@ -0,0 +1,121 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/ton"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// TonDaemonRpcClientExecutor follows the masterchain of a toncenter-compatible API.
+// Only the latest masterchain block is reported, intermediate ones are skipped.
+type TonDaemonRpcClientExecutor struct {
+	log *zerolog.Logger
+
+	client ton.IDaemonRpcClient
+
+	newBlockChns *util.SyncMapTypeSafe[string, chan ton.BlockId]
+
+	isStarted bool
+	stop      chan struct{}
+	// The sync loops and the notifications of the subscribers
+	routines util.Routines
+
+	blockSync blockSync
+}
+
+func (d *TonDaemonRpcClientExecutor) syncBlock(ctx context.Context) {
+	info, err := d.client.GetMasterchainInfo()
+	if err != nil {
+		d.log.Err(err).Str("method", "getMasterchainInfo").Msg(util.DefaultFailedFetchingTONDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeTON), "getMasterchainInfo").Inc()
+		return
+	}
+	defer d.blockSync.reportHeights(string(db.CoinTypeTON), info.Last.Seqno)
+
+	select {
+	case <-ctx.Done():
+		return
+	default:
+		if info.Last.Seqno < d.blockSync.lastBlockHeight.Load() {
+			return
+		}
+		d.log.Info().Msgf("Synced TON masterchain seqno: %v", info.Last.Seqno)
+
+		notifySubscribers(ctx, &d.routines, d.newBlockChns, info.Last, string(db.CoinTypeTON), metrics.NEW_BLOCK_CHANNEL)
+
+		d.blockSync.lastBlockHeight.Store(info.Last.Seqno + 1)
+	}
+}
+
+func (d *TonDaemonRpcClientExecutor) sync(blockTimeout time.Duration) {
+	t := time.NewTicker(blockTimeout)
+	defer t.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d.routines.Go(func() {
+		for {
+			s := ctx.Done()
+			select {
+			case <-s:
+				return
+			case <-t.C:
+				d.syncBlock(ctx)
+			}
+		}
+	})
+
+	<-d.stop
+	d.isStarted = false
+}
+
+func (d *TonDaemonRpcClientExecutor) Start(startBlock uint64) {
+	if d.isStarted {
+		return
+	}
+	d.isStarted = true
+	d.blockSync.lastBlockHeight.Store(startBlock)
+
+	d.routines.Go(func() { d.sync(MIN_SYNC_TIMEOUT / 2) })
+}
+
+// Stop stops the syncing and returns once every goroutine of the executor has exited.
+func (d *TonDaemonRpcClientExecutor) Stop() {
+	if !d.isStarted {
+		return
+	}
+	d.stop <- struct{}{}
+	d.routines.Wait(context.Background())
+}
+
+func (d *TonDaemonRpcClientExecutor) NewBlockChan() <-chan ton.BlockId {
+	cn := make(chan ton.BlockId)
+	d.newBlockChns.Store(uuid.NewString(), cn)
+	return cn
+}
+
+func (d *TonDaemonRpcClientExecutor) LastSyncedBlockHeight() uint64 {
+	return d.blockSync.lastBlockHeight.Load()
+}
+
+// SyncLag returns the number of the daemon blocks not synced yet.
+func (d *TonDaemonRpcClientExecutor) SyncLag() uint64 {
+	return d.blockSync.syncLag()
+}
+
+func NewTonDaemonRpcClientExecutor(client ton.IDaemonRpcClient, log *zerolog.Logger) *TonDaemonRpcClientExecutor {
+	return &TonDaemonRpcClientExecutor{
+		log:          log,
+		client:       client,
+		isStarted:    false,
+		stop:         make(chan struct{}),
+		newBlockChns: &util.SyncMapTypeSafe[string, chan ton.BlockId]{},
+	}
+}
@@ -0,0 +1,70 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/ton"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTonDaemonRpcClient struct {
+	mock.Mock
+}
+
+func (m *MockTonDaemonRpcClient) GetMasterchainInfo() (*ton.MasterchainInfo, error) {
+	args := m.Called()
+	return args.Get(0).(*ton.MasterchainInfo), args.Error(1)
+}
+func (m *MockTonDaemonRpcClient) GetTransactions(address string, limit uint32, lt uint64, hash string) ([]ton.Transaction, error) {
+	args := m.Called(address, limit, lt, hash)
+	return args.Get(0).([]ton.Transaction), args.Error(1)
+}
+
+func TestTonSyncBlock(t *testing.T) {
+	t.Run("Should Report New Masterchain Block", func(t *testing.T) {
+		lastBlockHeight := uint64(rand.Uint32())
+		expectedBlock := ton.BlockId{Workchain: -1, Seqno: lastBlockHeight + 5, RootHash: uuid.NewString()}
+
+		d := new(MockTonDaemonRpcClient)
+		d.On("GetMasterchainInfo").Return(&ton.MasterchainInfo{Last: expectedBlock}, error(nil))
+
+		ex := NewTonDaemonRpcClientExecutor(d, &zerolog.Logger{})
+		ex.blockSync.lastBlockHeight.Store(lastBlockHeight)
+		blockCn := ex.NewBlockChan()
+
+		ex.syncBlock(context.Background())
+
+		actualBlock := ton.BlockId{}
+		select {
+		case actualBlock = <-blockCn:
+			break
+		case <-time.After(MIN_SYNC_TIMEOUT):
+			log.Fatal(errors.New("Timeout has been expired"))
+		}
+
+		assert.Equal(t, expectedBlock.Seqno+1, ex.LastSyncedBlockHeight())
+		assert.Equal(t, expectedBlock, actualBlock)
+	})
+
+	t.Run("Should Skip Already Synced Block", func(t *testing.T) {
+		lastBlockHeight := uint64(rand.Uint32()) + 1
+
+		d := new(MockTonDaemonRpcClient)
+		d.On("GetMasterchainInfo").Return(&ton.MasterchainInfo{Last: ton.BlockId{Seqno: lastBlockHeight - 1}}, error(nil))
+
+		ex := NewTonDaemonRpcClientExecutor(d, &zerolog.Logger{})
+		ex.blockSync.lastBlockHeight.Store(lastBlockHeight)
+
+		ex.syncBlock(context.Background())
+
+		assert.Equal(t, lastBlockHeight, ex.LastSyncedBlockHeight())
+	})
+}
@@ -0,0 +1,194 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/utxo"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// UtxoDaemonRpcClientExecutor polls a bitcoind-like daemon (bitcoind, litecoind, ...).
+type UtxoDaemonRpcClientExecutor struct {
+	log *zerolog.Logger
+
+	coin string
+
+	client utxo.IDaemonRpcClient
+
+	txPoolChns   *util.SyncMapTypeSafe[string, chan utxo.Tx]
+	newBlockChns *util.SyncMapTypeSafe[string, chan utxo.Block]
+
+	isStarted bool
+	stop      chan struct{}
+	// The sync loops and the notifications of the subscribers
+	routines util.Routines
+
+	blockSync           blockSync
+	transactionPoolSync transactionPoolSync
+}
+
+func (d *UtxoDaemonRpcClientExecutor) syncBlock(ctx context.Context) {
+	height, err := d.client.GetBlockCount()
+	if err != nil {
+		d.log.Err(err).Str("coin", d.coin).Str("method", "getblockcount").Msg(util.DefaultFailedFetchingUtxoDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(d.coin, "getblockcount").Inc()
+		return
+	}
+	defer d.blockSync.reportHeights(d.coin, height)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if height < d.blockSync.lastBlockHeight.Load() {
+				return
+			}
+
+			hash, err := d.client.GetBlockHash(d.blockSync.lastBlockHeight.Load())
+			if err != nil {
+				d.log.Err(err).Str("coin", d.coin).Str("method", "getblockhash").Msg(util.DefaultFailedFetchingUtxoDaemonMsg)
+				metrics.DaemonRpcErrorsTotal.WithLabelValues(d.coin, "getblockhash").Inc()
+				return
+			}
+
+			block, err := d.client.GetBlock(hash)
+			if err != nil {
+				d.log.Err(err).Str("coin", d.coin).Str("method", "getblock").Msg(util.DefaultFailedFetchingUtxoDaemonMsg)
+				metrics.DaemonRpcErrorsTotal.WithLabelValues(d.coin, "getblock").Inc()
+				return
+			}
+			d.log.Info().Msgf("Synced %v blockheight: %v", d.coin, block.Height)
+
+			notifySubscribers(ctx, &d.routines, d.newBlockChns, *block, d.coin, metrics.NEW_BLOCK_CHANNEL)
+
+			d.blockSync.lastBlockHeight.Add(1)
+		}
+	}
+}
+
+func (d *UtxoDaemonRpcClientExecutor) syncTransactionPool(ctx context.Context) {
+	txIds, err := d.client.GetRawMempool()
+	if err != nil {
+		d.log.Err(err).Str("coin", d.coin).Str("method", "getrawmempool").Msg(util.DefaultFailedFetchingUtxoDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(d.coin, "getrawmempool").Inc()
+		return
+	}
+
+	prevTxs := d.transactionPoolSync.txs
+	newTxs := make(map[string]bool)
+
+	for i := 0; i < len(txIds); i++ {
+		newTxs[txIds[i]] = true
+
+		if prevTxs[txIds[i]] {
+			continue
+		}
+
+		tx, err := d.client.GetRawTransaction(txIds[i])
+		if err != nil {
+			// The tx might have been already mined or evicted from the mempool
+			d.log.Debug().Err(err).Str("coin", d.coin).Str("method", "getrawtransaction").Msg(util.DefaultFailedFetchingUtxoDaemonMsg)
+			delete(newTxs, txIds[i])
+			continue
+		}
+
+		notifySubscribers(ctx, &d.routines, d.txPoolChns, *tx, d.coin, metrics.TX_POOL_CHANNEL)
+	}
+
+	d.transactionPoolSync.txs = newTxs
+}
+
+func (d *UtxoDaemonRpcClientExecutor) sync(blockTimeout time.Duration, txPoolTimeout time.Duration) {
+	t1 := time.NewTicker(blockTimeout)
+	defer t1.Stop()
+	t2 := time.NewTicker(txPoolTimeout)
+	defer t2.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d.routines.Go(func() {
+		for {
+			s := ctx.Done()
+			select {
+			case <-s:
+				return
+			case <-t1.C:
+				d.syncBlock(ctx)
+			}
+		}
+	})
+
+	d.routines.Go(func() {
+		for {
+			s := ctx.Done()
+			select {
+			case <-s:
+				return
+			case <-t2.C:
+				d.syncTransactionPool(ctx)
+			}
+		}
+	})
+
+	<-d.stop
+	d.isStarted = false
+}
+
+func (d *UtxoDaemonRpcClientExecutor) Start(startBlock uint64) {
+	if d.isStarted {
+		return
+	}
+	d.isStarted = true
+	d.blockSync.lastBlockHeight.Store(startBlock)
+
+	d.routines.Go(func() { d.sync(MIN_SYNC_TIMEOUT, MIN_SYNC_TIMEOUT/2) })
+}
+
+// Stop stops the syncing and returns once every goroutine of the executor has exited.
+func (d *UtxoDaemonRpcClientExecutor) Stop() {
+	if !d.isStarted {
+		return
+	}
+	d.stop <- struct{}{}
+	d.routines.Wait(context.Background())
+}
+
+func (d *UtxoDaemonRpcClientExecutor) NewBlockChan() <-chan utxo.Block {
+	cn := make(chan utxo.Block)
+	d.newBlockChns.Store(uuid.NewString(), cn)
+	return cn
+}
+
+func (d *UtxoDaemonRpcClientExecutor) NewTxPoolChan() <-chan utxo.Tx {
+	cn := make(chan utxo.Tx)
+	d.txPoolChns.Store(uuid.NewString(), cn)
+	return cn
+}
+
+func (d *UtxoDaemonRpcClientExecutor) LastSyncedBlockHeight() uint64 {
+	return d.blockSync.lastBlockHeight.Load()
+}
+
+// SyncLag returns the number of the daemon blocks not synced yet.
+func (d *UtxoDaemonRpcClientExecutor) SyncLag() uint64 {
+	return d.blockSync.syncLag()
+}
+
+func NewUtxoDaemonRpcClientExecutor(coin string, client utxo.IDaemonRpcClient, log *zerolog.Logger) *UtxoDaemonRpcClientExecutor {
+	return &UtxoDaemonRpcClientExecutor{
+		log:                 log,
+		coin:                coin,
+		client:              client,
+		transactionPoolSync: transactionPoolSync{txs: make(map[string]bool)},
+		isStarted:           false,
+		stop:                make(chan struct{}),
+		txPoolChns:          &util.SyncMapTypeSafe[string, chan utxo.Tx]{},
+		newBlockChns:        &util.SyncMapTypeSafe[string, chan utxo.Block]{},
+	}
+}
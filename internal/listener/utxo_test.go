@@ -0,0 +1,124 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/utxo"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUtxoDaemonRpcClient struct {
+	mock.Mock
+}
+
+func (m *MockUtxoDaemonRpcClient) GetBlockchainInfo() (*utxo.GetBlockchainInfoResult, error) {
+	args := m.Called()
+	return args.Get(0).(*utxo.GetBlockchainInfoResult), args.Error(1)
+}
+func (m *MockUtxoDaemonRpcClient) GetIndexInfo() (map[string]utxo.IndexInfo, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]utxo.IndexInfo), args.Error(1)
+}
+func (m *MockUtxoDaemonRpcClient) GetBlockCount() (uint64, error) {
+	args := m.Called()
+	return args.Get(0).(uint64), args.Error(1)
+}
+func (m *MockUtxoDaemonRpcClient) GetBlockHash(height uint64) (string, error) {
+	args := m.Called(height)
+	return args.Get(0).(string), args.Error(1)
+}
+func (m *MockUtxoDaemonRpcClient) GetBlock(hash string) (*utxo.Block, error) {
+	args := m.Called(hash)
+	return args.Get(0).(*utxo.Block), args.Error(1)
+}
+func (m *MockUtxoDaemonRpcClient) GetRawMempool() ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+func (m *MockUtxoDaemonRpcClient) GetRawTransaction(txId string) (*utxo.Tx, error) {
+	args := m.Called(txId)
+	return args.Get(0).(*utxo.Tx), args.Error(1)
+}
+
+func TestUtxoSyncBlock(t *testing.T) {
+	lastBlockHeight := uint64(rand.Uint32())
+	blockHash := uuid.NewString()
+	expectedBlock := utxo.Block{Hash: blockHash, Height: lastBlockHeight}
+
+	d := new(MockUtxoDaemonRpcClient)
+	d.On("GetBlockCount").Return(lastBlockHeight, error(nil))
+	d.On("GetBlockHash", lastBlockHeight).Return(blockHash, error(nil))
+	d.On("GetBlock", blockHash).Return(&expectedBlock, error(nil))
+
+	ex := NewUtxoDaemonRpcClientExecutor("BTC", d, &zerolog.Logger{})
+	ex.blockSync.lastBlockHeight.Store(lastBlockHeight)
+	blockCn := ex.NewBlockChan()
+
+	ex.syncBlock(context.Background())
+
+	actualBlock := utxo.Block{}
+	select {
+	case actualBlock = <-blockCn:
+		break
+	case <-time.After(MIN_SYNC_TIMEOUT):
+		log.Fatal(errors.New("Timeout has been expired"))
+	}
+
+	assert.Equal(t, lastBlockHeight+1, ex.LastSyncedBlockHeight())
+	assert.Equal(t, expectedBlock, actualBlock)
+}
+
+func TestUtxoSyncTransactionPool(t *testing.T) {
+	// 1
+	d := new(MockUtxoDaemonRpcClient)
+	d.On("GetRawMempool").Once().Return([]string{"tx1", "tx2", "tx3"}, error(nil))
+	for _, id := range []string{"tx1", "tx2", "tx3", "tx4", "tx5"} {
+		d.On("GetRawTransaction", id).Return(&utxo.Tx{TxId: id}, error(nil))
+	}
+	d.On("GetRawTransaction", "tx6").Return((*utxo.Tx)(nil), &utxo.JsonRpcError{Code: utxo.RPC_INVALID_ADDRESS_OR_KEY})
+
+	ex := NewUtxoDaemonRpcClientExecutor("BTC", d, &zerolog.Logger{})
+	txPoolCn := ex.NewTxPoolChan()
+
+	ex.syncTransactionPool(context.Background())
+
+	txs1 := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		select {
+		case tx := <-txPoolCn:
+			txs1[tx.TxId] = true
+		case <-time.After(MIN_SYNC_TIMEOUT):
+			log.Fatal(errors.New("Timeout has been expired"))
+		}
+	}
+
+	assert.Equal(t, map[string]bool{"tx1": true, "tx2": true, "tx3": true}, txs1)
+	assert.Equal(t, txs1, ex.transactionPoolSync.txs)
+
+	// 2
+	d.On("GetRawMempool").Return([]string{"tx1", "tx4", "tx5", "tx6"}, error(nil))
+
+	ex.syncTransactionPool(context.Background())
+
+	txs2 := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case tx := <-txPoolCn:
+			txs2[tx.TxId] = true
+		case <-time.After(MIN_SYNC_TIMEOUT):
+			log.Fatal(errors.New("Timeout has been expired"))
+		}
+	}
+
+	assert.Equal(t, map[string]bool{"tx4": true, "tx5": true}, txs2)
+	// tx6 couldn't be fetched, so it must be retried on the next sync
+	assert.Equal(t, map[string]bool{"tx1": true, "tx4": true, "tx5": true}, ex.transactionPoolSync.txs)
+}
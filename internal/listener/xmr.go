@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/chekist32/go-monero/daemon"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/tracing"
 	"github.com/chekist32/goipay/internal/util"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -17,6 +20,27 @@ type transactionPoolSync struct {
 
 type blockSync struct {
 	lastBlockHeight atomic.Uint64
+	// Height of the daemon tip as of the last sync, 0 until the daemon is reached
+	daemonBlockHeight atomic.Uint64
+	// Hashes of the last RECENT_BLOCKS_DEPTH synced blocks by height, the ones below the start height are loaded on Start
+	recentBlockHashes map[uint64]string
+}
+
+// reportHeights exposes the daemon height along with the next height to be synced.
+func (b *blockSync) reportHeights(coin string, daemonHeight uint64) {
+	b.daemonBlockHeight.Store(daemonHeight)
+	metrics.DaemonBlockHeight.WithLabelValues(coin).Set(float64(daemonHeight))
+	metrics.LastSyncedBlockHeight.WithLabelValues(coin).Set(float64(b.lastBlockHeight.Load()))
+}
+
+// syncLag returns how far the daemon tip is ahead of the next block to be synced.
+func (b *blockSync) syncLag() uint64 {
+	next, tip := b.lastBlockHeight.Load(), b.daemonBlockHeight.Load()
+	if tip <= next {
+		return 0
+	}
+
+	return tip - next
 }
 
 type DaemonRpcClientExecutor struct {
@@ -26,20 +50,30 @@ type DaemonRpcClientExecutor struct {
 
 	txPoolChns   *util.SyncMapTypeSafe[string, chan daemon.MoneroTx]
 	newBlockChns *util.SyncMapTypeSafe[string, chan daemon.GetBlockResult]
+	reorgChns    *util.SyncMapTypeSafe[string, chan ChainReorg]
 
 	isStarted bool
 	stop      chan struct{}
+	// The sync loops and the notifications of the subscribers
+	routines util.Routines
 
 	blockSync           blockSync
 	transactionPoolSync transactionPoolSync
 }
 
 func (d *DaemonRpcClientExecutor) syncBlock(ctx context.Context) {
-	height, err := d.client.GetLastBlockHeader(true)
+	ctx, span := tracing.Tracer().Start(ctx, "DaemonRpcClientExecutor.syncBlock")
+	defer span.End()
+
+	height, err := tracing.Call(ctx, string(db.CoinTypeXMR), "last_block_header", func() (*daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult], error) {
+		return d.client.GetLastBlockHeader(true)
+	})
 	if err != nil {
 		d.log.Err(err).Str("method", "last_block_header").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "last_block_header").Inc()
 		return
 	}
+	defer d.blockSync.reportHeights(string(db.CoinTypeXMR), height.Result.BlockHeader.Height)
 
 	for {
 		select {
@@ -50,35 +84,122 @@ func (d *DaemonRpcClientExecutor) syncBlock(ctx context.Context) {
 				return
 			}
 
-			block, err := d.client.GetBlockByHeight(true, d.blockSync.lastBlockHeight.Load())
+			blockHeight := d.blockSync.lastBlockHeight.Load()
+
+			block, err := tracing.Call(ctx, string(db.CoinTypeXMR), "get_block", func() (*daemon.JsonRpcGenericResponse[daemon.GetBlockResult], error) {
+				return d.client.GetBlockByHeight(true, blockHeight)
+			})
 			if err != nil {
 				d.log.Err(err).Str("method", "get_block").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+				metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_block").Inc()
 				return
 			}
+
+			if prevHash, ok := d.blockSync.recentBlockHashes[blockHeight-1]; blockHeight > 0 && ok && prevHash != block.Result.BlockHeader.PrevHash {
+				forkHeight, err := d.findForkHeight(ctx, blockHeight-1)
+				if err != nil {
+					d.log.Err(err).Str("method", "get_block_header_by_height").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+					metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_block_header_by_height").Inc()
+					return
+				}
+
+				d.rewind(ctx, forkHeight)
+				continue
+			}
 			d.log.Info().Msgf("Synced blockheight: %v", block.Result.BlockHeader.Height)
 
-			d.newBlockChns.Range(func(key string, cn chan daemon.GetBlockResult) bool {
-				go func() {
-					select {
-					case cn <- block.Result:
-						return
-					case <-time.After(MIN_SYNC_TIMEOUT):
-						d.newBlockChns.Delete(key)
-						return
-					}
-				}()
-				return true
-			})
+			notifySubscribers(ctx, &d.routines, d.newBlockChns, block.Result, string(db.CoinTypeXMR), metrics.NEW_BLOCK_CHANNEL)
+
+			d.blockSync.recentBlockHashes[blockHeight] = block.Result.BlockHeader.Hash
+			if blockHeight >= RECENT_BLOCKS_DEPTH {
+				delete(d.blockSync.recentBlockHashes, blockHeight-RECENT_BLOCKS_DEPTH)
+			}
 
 			d.blockSync.lastBlockHeight.Add(1)
 		}
 	}
 }
 
-func (d *DaemonRpcClientExecutor) syncTransactionPool() {
-	txs, err := d.client.GetTransactionPool()
+// findForkHeight walks back from the given height through the recently synced blocks
+// and returns the lowest height whose block is no longer part of the main chain.
+// If the fork is deeper than RECENT_BLOCKS_DEPTH, the oldest known height is returned.
+func (d *DaemonRpcClientExecutor) findForkHeight(ctx context.Context, height uint64) (uint64, error) {
+	forkHeight := height + 1
+
+	for {
+		hash, ok := d.blockSync.recentBlockHashes[height]
+		if !ok {
+			return forkHeight, nil
+		}
+
+		header, err := tracing.Call(ctx, string(db.CoinTypeXMR), "get_block_header_by_height", func() (*daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult], error) {
+			return d.client.GetBlockHeaderByHeight(false, height)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if header.Result.BlockHeader.Hash == hash {
+			return forkHeight, nil
+		}
+
+		forkHeight = height
+		if height == 0 {
+			return forkHeight, nil
+		}
+		height--
+	}
+}
+
+// rewind forgets the blocks starting from forkHeight, notifies the subscribers about the reorg
+// and makes the next sync start over from forkHeight.
+func (d *DaemonRpcClientExecutor) rewind(ctx context.Context, forkHeight uint64) {
+	reorg := ChainReorg{ForkHeight: forkHeight, OrphanedBlockHashes: make([]string, 0)}
+	for height := forkHeight; height < d.blockSync.lastBlockHeight.Load(); height++ {
+		if hash, ok := d.blockSync.recentBlockHashes[height]; ok {
+			reorg.OrphanedBlockHashes = append(reorg.OrphanedBlockHashes, hash)
+			delete(d.blockSync.recentBlockHashes, height)
+		}
+	}
+	d.log.Warn().Msgf("Chain reorganization detected at blockheight: %v, orphaned blocks: %v", forkHeight, len(reorg.OrphanedBlockHashes))
+
+	notifySubscribers(ctx, &d.routines, d.reorgChns, reorg, string(db.CoinTypeXMR), metrics.REORG_CHANNEL)
+
+	d.blockSync.lastBlockHeight.Store(forkHeight)
+}
+
+// loadRecentBlockHashes fills the reorg detection window with the blocks below startBlock synced before a restart,
+// so a reorg orphaning them is still detected. The window is left empty if the daemon can't be reached.
+func (d *DaemonRpcClientExecutor) loadRecentBlockHashes(ctx context.Context, startBlock uint64) {
+	if startBlock == 0 {
+		return
+	}
+	from := uint64(0)
+	if startBlock > RECENT_BLOCKS_DEPTH {
+		from = startBlock - RECENT_BLOCKS_DEPTH
+	}
+
+	headers, err := tracing.Call(ctx, string(db.CoinTypeXMR), "get_block_headers_range", func() (*daemon.JsonRpcGenericResponse[daemon.GetBlockHeadersRangeResult], error) {
+		return d.client.GetBlockHeadersRange(false, from, startBlock-1)
+	})
+	if err != nil {
+		d.log.Err(err).Str("method", "get_block_headers_range").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_block_headers_range").Inc()
+		return
+	}
+
+	for i := 0; i < len(headers.Result.Headers); i++ {
+		d.blockSync.recentBlockHashes[headers.Result.Headers[i].Height] = headers.Result.Headers[i].Hash
+	}
+}
+
+func (d *DaemonRpcClientExecutor) syncTransactionPool(ctx context.Context) {
+	ctx, span := tracing.Tracer().Start(ctx, "DaemonRpcClientExecutor.syncTransactionPool")
+	defer span.End()
+
+	txs, err := tracing.Call(ctx, string(db.CoinTypeXMR), "get_transaction_pool", d.client.GetTransactionPool)
 	if err != nil {
 		d.log.Err(err).Str("method", "get_transaction_pool").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_transaction_pool").Inc()
 		return
 	}
 
@@ -93,19 +214,7 @@ func (d *DaemonRpcClientExecutor) syncTransactionPool() {
 			continue
 		}
 
-		d.txPoolChns.Range(func(key string, cn chan daemon.MoneroTx) bool {
-			go func() {
-				select {
-				case cn <- fetchedTxs[i]:
-					return
-				case <-time.After(MIN_SYNC_TIMEOUT):
-					d.txPoolChns.Delete(key)
-					return
-				}
-			}()
-
-			return true
-		})
+		notifySubscribers(ctx, &d.routines, d.txPoolChns, fetchedTxs[i], string(db.CoinTypeXMR), metrics.TX_POOL_CHANNEL)
 	}
 
 	d.transactionPoolSync.txs = newTxs
@@ -113,12 +222,14 @@ func (d *DaemonRpcClientExecutor) syncTransactionPool() {
 
 func (d *DaemonRpcClientExecutor) sync(blockTimeout time.Duration, txPoolTimeout time.Duration) {
 	t1 := time.NewTicker(blockTimeout)
+	defer t1.Stop()
 	t2 := time.NewTicker(txPoolTimeout)
+	defer t2.Stop()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go func() {
+	d.routines.Go(func() {
 		for {
 			s := ctx.Done()
 			select {
@@ -128,19 +239,19 @@ func (d *DaemonRpcClientExecutor) sync(blockTimeout time.Duration, txPoolTimeout
 				d.syncBlock(ctx)
 			}
 		}
-	}()
+	})
 
-	go func() {
+	d.routines.Go(func() {
 		for {
 			s := ctx.Done()
 			select {
 			case <-s:
 				return
 			case <-t2.C:
-				d.syncTransactionPool()
+				d.syncTransactionPool(ctx)
 			}
 		}
-	}()
+	})
 
 	<-d.stop
 	d.isStarted = false
@@ -152,12 +263,18 @@ func (d *DaemonRpcClientExecutor) Start(startBlock uint64) {
 	}
 	d.isStarted = true
 	d.blockSync.lastBlockHeight.Store(startBlock)
+	d.loadRecentBlockHashes(context.Background(), startBlock)
 
-	go d.sync(MIN_SYNC_TIMEOUT, MIN_SYNC_TIMEOUT/2)
+	d.routines.Go(func() { d.sync(MIN_SYNC_TIMEOUT, MIN_SYNC_TIMEOUT/2) })
 }
 
+// Stop stops the syncing and returns once every goroutine of the executor has exited.
 func (d *DaemonRpcClientExecutor) Stop() {
+	if !d.isStarted {
+		return
+	}
 	d.stop <- struct{}{}
+	d.routines.Wait(context.Background())
 }
 
 func (d *DaemonRpcClientExecutor) NewBlockChan() <-chan daemon.GetBlockResult {
@@ -166,6 +283,13 @@ func (d *DaemonRpcClientExecutor) NewBlockChan() <-chan daemon.GetBlockResult {
 	return cn
 }
 
+// NewReorgChan returns a channel notified whenever already emitted blocks get orphaned.
+func (d *DaemonRpcClientExecutor) NewReorgChan() <-chan ChainReorg {
+	cn := make(chan ChainReorg)
+	d.reorgChns.Store(uuid.NewString(), cn)
+	return cn
+}
+
 func (d *DaemonRpcClientExecutor) NewTxPoolChan() <-chan daemon.MoneroTx {
 	cn := make(chan daemon.MoneroTx)
 	d.txPoolChns.Store(uuid.NewString(), cn)
@@ -176,6 +300,11 @@ func (d *DaemonRpcClientExecutor) LastSyncedBlockHeight() uint64 {
 	return d.blockSync.lastBlockHeight.Load()
 }
 
+// SyncLag returns the number of the daemon blocks not synced yet.
+func (d *DaemonRpcClientExecutor) SyncLag() uint64 {
+	return d.blockSync.syncLag()
+}
+
 func NewDaemonRpcClientExecutor(client daemon.IDaemonRpcClient, log *zerolog.Logger) *DaemonRpcClientExecutor {
 	return &DaemonRpcClientExecutor{
 		log:                 log,
@@ -185,5 +314,7 @@ func NewDaemonRpcClientExecutor(client daemon.IDaemonRpcClient, log *zerolog.Log
 		stop:                make(chan struct{}),
 		txPoolChns:          &util.SyncMapTypeSafe[string, chan daemon.MoneroTx]{},
 		newBlockChns:        &util.SyncMapTypeSafe[string, chan daemon.GetBlockResult]{},
+		reorgChns:           &util.SyncMapTypeSafe[string, chan ChainReorg]{},
+		blockSync:           blockSync{recentBlockHashes: make(map[uint64]string)},
 	}
 }
@@ -3,6 +3,7 @@ package listener
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"math/rand"
 	"sync/atomic"
@@ -14,6 +15,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/goleak"
 )
 
 type MockDaemonRpcClient struct {
@@ -48,11 +50,11 @@ func (m *MockDaemonRpcClient) GetBlockHeaderByHash(fillPowHash bool, hash string
 	return args.Get(0).(*daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult]), args.Error(1)
 }
 func (m *MockDaemonRpcClient) GetBlockHeaderByHeight(fillPowHash bool, height uint64) (*daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult], error) {
-	args := m.Called()
+	args := m.Called(fillPowHash, height)
 	return args.Get(0).(*daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult]), args.Error(1)
 }
 func (m *MockDaemonRpcClient) GetBlockHeadersRange(fillPowHash bool, startHeight uint64, endHeight uint64) (*daemon.JsonRpcGenericResponse[daemon.GetBlockHeadersRangeResult], error) {
-	args := m.Called()
+	args := m.Called(fillPowHash, startHeight, endHeight)
 	return args.Get(0).(*daemon.JsonRpcGenericResponse[daemon.GetBlockHeadersRangeResult]), args.Error(1)
 }
 func (m *MockDaemonRpcClient) GetBlockTemplate(wallet string, reverseSize uint64) (*daemon.JsonRpcGenericResponse[daemon.GetBlockTemplateResult], error) {
@@ -210,6 +212,153 @@ func TestSyncBlock(t *testing.T) {
 	assert.Equal(t, expectedBlockResult, actualBlockResult)
 }
 
+func TestSyncBlockReorg(t *testing.T) {
+	blockResponse := func(height uint64, hash string, prevHash string) *daemon.JsonRpcGenericResponse[daemon.GetBlockResult] {
+		return &daemon.JsonRpcGenericResponse[daemon.GetBlockResult]{
+			Result: daemon.GetBlockResult{GetBlockHeaderResult: daemon.GetBlockHeaderResult{BlockHeader: daemon.BlockHeader{Height: height, Hash: hash, PrevHash: prevHash}}},
+		}
+	}
+	headerResponse := func(height uint64, hash string) *daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult] {
+		return &daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult]{
+			Result: daemon.GetBlockHeaderResult{BlockHeader: daemon.BlockHeader{Height: height, Hash: hash}},
+		}
+	}
+
+	d := new(MockDaemonRpcClient)
+	d.On("GetLastBlockHeader", true).Return(headerResponse(12, "b12"), error(nil))
+
+	// Block 10 of the new chain doesn't point to the synced block 9
+	d.On("GetBlockByHeight", true, uint64(10)).Once().Return(blockResponse(10, "b10", "b9"), error(nil))
+	d.On("GetBlockHeaderByHeight", false, uint64(9)).Return(headerResponse(9, "b9"), error(nil))
+	d.On("GetBlockHeaderByHeight", false, uint64(8)).Return(headerResponse(8, "a8"), error(nil))
+
+	d.On("GetBlockByHeight", true, uint64(9)).Return(blockResponse(9, "b9", "a8"), error(nil))
+	d.On("GetBlockByHeight", true, uint64(10)).Return(blockResponse(10, "b10", "b9"), error(nil))
+	d.On("GetBlockByHeight", true, uint64(11)).Return(blockResponse(11, "b11", "b10"), error(nil))
+
+	xmr := NewDaemonRpcClientExecutor(d, &zerolog.Logger{})
+	xmr.blockSync.lastBlockHeight.Store(10)
+	xmr.blockSync.recentBlockHashes[7] = "a7"
+	xmr.blockSync.recentBlockHashes[8] = "a8"
+	xmr.blockSync.recentBlockHashes[9] = "a9"
+
+	blockCn := xmr.NewBlockChan()
+	reorgCn := xmr.NewReorgChan()
+
+	xmr.syncBlock(context.Background())
+
+	actualReorg := ChainReorg{}
+	select {
+	case actualReorg = <-reorgCn:
+		break
+	case <-time.After(MIN_SYNC_TIMEOUT):
+		log.Fatal(errors.New("Timeout has been expired"))
+	}
+
+	blockHashes := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		select {
+		case block := <-blockCn:
+			blockHashes[block.BlockHeader.Hash] = true
+		case <-time.After(MIN_SYNC_TIMEOUT):
+			log.Fatal(errors.New("Timeout has been expired"))
+		}
+	}
+
+	assert.Equal(t, ChainReorg{ForkHeight: 9, OrphanedBlockHashes: []string{"a9"}}, actualReorg)
+	assert.Equal(t, map[string]bool{"b9": true, "b10": true, "b11": true}, blockHashes)
+	assert.Equal(t, uint64(12), xmr.blockSync.lastBlockHeight.Load())
+	assert.Equal(t, map[uint64]string{7: "a7", 8: "a8", 9: "b9", 10: "b10", 11: "b11"}, xmr.blockSync.recentBlockHashes)
+	d.AssertNotCalled(t, "GetBlockHeaderByHeight", false, uint64(7))
+}
+
+func TestSyncBlockReorgAfterRestart(t *testing.T) {
+	header := func(height uint64, hash string) daemon.BlockHeader {
+		return daemon.BlockHeader{Height: height, Hash: hash}
+	}
+	headersResponse := func(headers ...daemon.BlockHeader) *daemon.JsonRpcGenericResponse[daemon.GetBlockHeadersRangeResult] {
+		return &daemon.JsonRpcGenericResponse[daemon.GetBlockHeadersRangeResult]{Result: daemon.GetBlockHeadersRangeResult{Headers: headers}}
+	}
+
+	t.Run("Should Detect Reorg Of Blocks Synced Before Restart", func(t *testing.T) {
+		d := new(MockDaemonRpcClient)
+		d.On("GetBlockHeadersRange", false, uint64(0), uint64(9)).Return(headersResponse(header(8, "a8"), header(9, "a9")), error(nil))
+		d.On("GetLastBlockHeader", true).Return(&daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult]{Result: daemon.GetBlockHeaderResult{BlockHeader: header(11, "b11")}}, error(nil))
+		for height, prevHash := range map[uint64]string{9: "a8", 10: "b9"} {
+			d.On("GetBlockByHeight", true, height).Return(&daemon.JsonRpcGenericResponse[daemon.GetBlockResult]{
+				Result: daemon.GetBlockResult{GetBlockHeaderResult: daemon.GetBlockHeaderResult{BlockHeader: daemon.BlockHeader{Height: height, Hash: fmt.Sprintf("b%v", height), PrevHash: prevHash}}},
+			}, error(nil))
+		}
+		d.On("GetBlockHeaderByHeight", false, uint64(9)).Return(&daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult]{Result: daemon.GetBlockHeaderResult{BlockHeader: header(9, "b9")}}, error(nil))
+		d.On("GetBlockHeaderByHeight", false, uint64(8)).Return(&daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult]{Result: daemon.GetBlockHeaderResult{BlockHeader: header(8, "a8")}}, error(nil))
+
+		xmr := NewDaemonRpcClientExecutor(d, &zerolog.Logger{})
+		xmr.blockSync.lastBlockHeight.Store(10)
+		xmr.loadRecentBlockHashes(context.Background(), 10)
+		assert.Equal(t, map[uint64]string{8: "a8", 9: "a9"}, xmr.blockSync.recentBlockHashes)
+
+		reorgCn := xmr.NewReorgChan()
+		xmr.syncBlock(context.Background())
+
+		select {
+		case reorg := <-reorgCn:
+			assert.Equal(t, ChainReorg{ForkHeight: 9, OrphanedBlockHashes: []string{"a9"}}, reorg)
+		case <-time.After(MIN_SYNC_TIMEOUT):
+			log.Fatal(errors.New("Timeout has been expired"))
+		}
+		assert.Equal(t, uint64(11), xmr.blockSync.lastBlockHeight.Load())
+		assert.Equal(t, map[uint64]string{8: "a8", 9: "b9", 10: "b10"}, xmr.blockSync.recentBlockHashes)
+	})
+
+	t.Run("Should Load Last RECENT_BLOCKS_DEPTH Heights", func(t *testing.T) {
+		d := new(MockDaemonRpcClient)
+		d.On("GetBlockHeadersRange", false, uint64(50), uint64(149)).Return(headersResponse(header(149, "a149")), error(nil))
+
+		xmr := NewDaemonRpcClientExecutor(d, &zerolog.Logger{})
+		xmr.loadRecentBlockHashes(context.Background(), 150)
+		assert.Equal(t, map[uint64]string{149: "a149"}, xmr.blockSync.recentBlockHashes)
+	})
+
+	t.Run("Should Start With Empty Window (daemon error)", func(t *testing.T) {
+		d := new(MockDaemonRpcClient)
+		d.On("GetBlockHeadersRange", false, uint64(0), uint64(9)).Return((*daemon.JsonRpcGenericResponse[daemon.GetBlockHeadersRangeResult])(nil), errors.New("unreachable"))
+
+		xmr := NewDaemonRpcClientExecutor(d, &zerolog.Logger{})
+		xmr.loadRecentBlockHashes(context.Background(), 10)
+		assert.Empty(t, xmr.blockSync.recentBlockHashes)
+	})
+}
+
+func TestReorgChan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Check NewReorgChan Func", func(t *testing.T) {
+		d := new(MockDaemonRpcClient)
+		xmr := NewDaemonRpcClientExecutor(d, zerolog.DefaultContextLogger)
+
+		expectedReorg := ChainReorg{ForkHeight: rand.Uint64(), OrphanedBlockHashes: []string{uuid.NewString()}}
+
+		reorgCn := xmr.NewReorgChan()
+		xmr.reorgChns.Range(func(key string, value chan ChainReorg) bool {
+			go func() {
+				value <- expectedReorg
+			}()
+
+			return true
+		})
+
+		actualReorg := ChainReorg{}
+		select {
+		case actualReorg = <-reorgCn:
+			break
+		case <-time.After(MIN_SYNC_TIMEOUT):
+			log.Fatal(errors.New("Timeout has been expired"))
+		}
+
+		assert.Equal(t, expectedReorg, actualReorg)
+	})
+}
+
 func TestSyncTransactionPool(t *testing.T) {
 	// 1
 	expectedTxs1Map := map[string]daemon.MoneroTx{
@@ -235,7 +384,7 @@ func TestSyncTransactionPool(t *testing.T) {
 	xmr := NewDaemonRpcClientExecutor(d, &zerolog.Logger{})
 	txPoolCn := xmr.NewTxPoolChan()
 
-	xmr.syncTransactionPool()
+	xmr.syncTransactionPool(context.Background())
 
 	txs1 := make(map[string]daemon.MoneroTx, 0)
 	for i := 0; i < len(expectedTxs1Map); i++ {
@@ -277,7 +426,7 @@ func TestSyncTransactionPool(t *testing.T) {
 		error(nil),
 	)
 
-	xmr.syncTransactionPool()
+	xmr.syncTransactionPool(context.Background())
 
 	txs2 := make(map[string]daemon.MoneroTx, 0)
 	for i := 0; i < 2; i++ {
@@ -300,3 +449,54 @@ func TestSyncTransactionPool(t *testing.T) {
 		return true
 	})
 }
+
+func TestSyncLag(t *testing.T) {
+	xmr := NewDaemonRpcClientExecutor(new(MockDaemonRpcClient), &zerolog.Logger{})
+
+	// The daemon hasn't been reached yet
+	xmr.blockSync.lastBlockHeight.Store(100)
+	assert.Equal(t, uint64(0), xmr.SyncLag())
+
+	xmr.blockSync.reportHeights("XMR", 110)
+	assert.Equal(t, uint64(10), xmr.SyncLag())
+
+	xmr.blockSync.lastBlockHeight.Store(110)
+	assert.Equal(t, uint64(0), xmr.SyncLag())
+
+	// Rolled back by a reorg
+	xmr.blockSync.reportHeights("XMR", 105)
+	assert.Equal(t, uint64(0), xmr.SyncLag())
+}
+
+func TestStopLeaksNoGoroutines(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	lastBlockHeight := uint64(100)
+	d := new(MockDaemonRpcClient)
+	d.On("GetLastBlockHeader", true).Return(
+		&daemon.JsonRpcGenericResponse[daemon.GetBlockHeaderResult]{
+			Result: daemon.GetBlockHeaderResult{BlockHeader: daemon.BlockHeader{Height: lastBlockHeight}},
+		},
+		error(nil),
+	)
+	d.On("GetBlockByHeight", true, mock.Anything).Return(
+		&daemon.JsonRpcGenericResponse[daemon.GetBlockResult]{},
+		error(nil),
+	)
+	d.On("GetTransactionPool").Return(&daemon.GetTransactionPoolResponse{}, error(nil))
+
+	xmr := NewDaemonRpcClientExecutor(d, &zerolog.Logger{})
+	// Nobody receives, so the notification is pending on Stop
+	xmr.NewBlockChan()
+
+	// Start with short sync intervals
+	xmr.isStarted = true
+	xmr.blockSync.lastBlockHeight.Store(lastBlockHeight - 1)
+	xmr.routines.Go(func() { xmr.sync(time.Millisecond, time.Millisecond) })
+
+	assert.Eventually(t, func() bool { return xmr.LastSyncedBlockHeight() == lastBlockHeight }, time.Second, time.Millisecond)
+	assert.Greater(t, xmr.routines.Running(), 0)
+
+	xmr.Stop()
+	assert.Equal(t, 0, xmr.routines.Running())
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+const collect_timeout time.Duration = 5 * time.Second
+
+// InvoiceCollector reads the invoice counts from the database and the pending invoices from the processor on every scrape.
+type InvoiceCollector struct {
+	log *zerolog.Logger
+
+	dbConnPool      *pgxpool.Pool
+	pendingInvoices func() map[db.CoinType]int
+
+	invoicesDesc        *prometheus.Desc
+	pendingInvoicesDesc *prometheus.Desc
+}
+
+func (c *InvoiceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.invoicesDesc
+	ch <- c.pendingInvoicesDesc
+}
+
+func (c *InvoiceCollector) Collect(ch chan<- prometheus.Metric) {
+	for coin, count := range c.pendingInvoices() {
+		ch <- prometheus.MustNewConstMetric(c.pendingInvoicesDesc, prometheus.GaugeValue, float64(count), string(coin))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), collect_timeout)
+	defer cancel()
+
+	counts, err := db.New(c.dbConnPool).CountInvoicesByCoinAndStatus(ctx)
+	if err != nil {
+		c.log.Err(err).Str("queryName", "CountInvoicesByCoinAndStatus").Msg(util.DefaultFailedSqlQueryMsg)
+		ch <- prometheus.NewInvalidMetric(c.invoicesDesc, err)
+		return
+	}
+
+	for i := 0; i < len(counts); i++ {
+		ch <- prometheus.MustNewConstMetric(c.invoicesDesc, prometheus.GaugeValue, float64(counts[i].Count), string(counts[i].Coin), string(counts[i].Status))
+	}
+}
+
+// NewInvoiceCollector creates the collector, pendingInvoices returns the number of the invoices being watched by coin.
+func NewInvoiceCollector(dbConnPool *pgxpool.Pool, pendingInvoices func() map[db.CoinType]int, log *zerolog.Logger) *InvoiceCollector {
+	return &InvoiceCollector{
+		log:             log,
+		dbConnPool:      dbConnPool,
+		pendingInvoices: pendingInvoices,
+		invoicesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "invoices"),
+			"Number of the invoices by coin and status.",
+			[]string{"coin", "status"}, nil,
+		),
+		pendingInvoicesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "pending_invoices"),
+			"Number of the invoices being watched for payments by coin.",
+			[]string{"coin"}, nil,
+		),
+	}
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	METRICS_PATH string = "/metrics"
+
+	NEW_BLOCK_CHANNEL string = "new_block"
+	TX_POOL_CHANNEL   string = "tx_pool"
+	REORG_CHANNEL     string = "reorg"
+	INVOICE_CHANNEL   string = "invoice"
+
+	namespace string = "goipay"
+)
+
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return r
+}
+
+var (
+	// Registry holds the goipay metrics along with the Go runtime and process ones.
+	Registry *prometheus.Registry = newRegistry()
+
+	GrpcRequestsTotal *prometheus.CounterVec = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Number of the handled gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+
+	GrpcRequestDurationSeconds *prometheus.HistogramVec = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the gRPC calls by method, for streams it's the lifetime of the stream.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	DaemonRpcErrorsTotal *prometheus.CounterVec = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "daemon",
+		Name:      "rpc_errors_total",
+		Help:      "Number of the failed daemon RPC calls by coin and method.",
+	}, []string{"coin", "method"})
+
+	DaemonBlockHeight *prometheus.GaugeVec = promauto.With(Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "daemon",
+		Name:      "block_height",
+		Help:      "Height of the last block reported by the daemon.",
+	}, []string{"coin"})
+
+	LastSyncedBlockHeight *prometheus.GaugeVec = promauto.With(Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "sync",
+		Name:      "last_synced_block_height",
+		Help:      "Height of the next block to be synced from the daemon.",
+	}, []string{"coin"})
+
+	SubscriberDropsTotal *prometheus.CounterVec = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "subscriber_drops_total",
+		Help:      "Number of the subscribers dropped for not receiving within the send timeout, by coin and channel.",
+	}, []string{"coin", "channel"})
+)
+
+// Handler serves the Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,107 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path"
+
+	"github.com/chekist32/goipay/sql/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/rs/zerolog"
+)
+
+const (
+	UP_CMD     string = "up"
+	DOWN_CMD   string = "down"
+	STATUS_CMD string = "status"
+)
+
+var InvalidCommandErr = errors.New("invalid migrate command, expected up, down or status")
+
+// NewProvider creates the goose provider of the embedded migrations.
+// Up and Down hold a Postgres advisory lock, so the instances started at once apply the migrations only once.
+func NewProvider(db *sql.DB) (*goose.Provider, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	return goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+}
+
+// Up applies all the pending migrations.
+func Up(ctx context.Context, db *sql.DB, log *zerolog.Logger) error {
+	p, err := NewProvider(db)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.Up(ctx)
+	for i := 0; i < len(res); i++ {
+		log.Info().Msgf("Migration %v", res[i])
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		log.Info().Msg("The database is up to date")
+	}
+
+	return nil
+}
+
+// Down rolls back the latest applied migration.
+func Down(ctx context.Context, db *sql.DB, log *zerolog.Logger) error {
+	p, err := NewProvider(db)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.Down(ctx)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Migration %v", res)
+
+	return nil
+}
+
+// Status logs whether every migration is applied or pending.
+func Status(ctx context.Context, db *sql.DB, log *zerolog.Logger) error {
+	p, err := NewProvider(db)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := p.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(statuses); i++ {
+		event := log.Info().Str("state", string(statuses[i].State))
+		if statuses[i].State == goose.StateApplied {
+			event = event.Time("appliedAt", statuses[i].AppliedAt)
+		}
+		event.Msg(path.Base(statuses[i].Source.Path))
+	}
+
+	return nil
+}
+
+// Run runs the migrate command, either UP_CMD, DOWN_CMD or STATUS_CMD.
+func Run(ctx context.Context, db *sql.DB, command string, log *zerolog.Logger) error {
+	switch command {
+	case UP_CMD:
+		return Up(ctx, db, log)
+	case DOWN_CMD:
+		return Down(ctx, db, log)
+	case STATUS_CMD:
+		return Status(ctx, db, log)
+	default:
+		return InvalidCommandErr
+	}
+}
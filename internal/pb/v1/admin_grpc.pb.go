@@ -0,0 +1,270 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.28.2
+// source: admin.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	AdminService_ListInvoices_FullMethodName     = "/admin.v1.AdminService/ListInvoices"
+	AdminService_ExpireInvoice_FullMethodName    = "/admin.v1.AdminService/ExpireInvoice"
+	AdminService_RecheckInvoice_FullMethodName   = "/admin.v1.AdminService/RecheckInvoice"
+	AdminService_GetSyncStates_FullMethodName    = "/admin.v1.AdminService/GetSyncStates"
+	AdminService_ReleaseAddresses_FullMethodName = "/admin.v1.AdminService/ReleaseAddresses"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Every call requires an admin api key
+type AdminServiceClient interface {
+	ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error)
+	// Expires a pending invoice right away, it's still watched for late payments during the grace period
+	ExpireInvoice(ctx context.Context, in *ExpireInvoiceRequest, opts ...grpc.CallOption) (*ExpireInvoiceResponse, error)
+	// Starts tracking a pending invoice again if it isn't and verifies its payments without waiting for the next block
+	RecheckInvoice(ctx context.Context, in *RecheckInvoiceRequest, opts ...grpc.CallOption) (*RecheckInvoiceResponse, error)
+	GetSyncStates(ctx context.Context, in *GetSyncStatesRequest, opts ...grpc.CallOption) (*GetSyncStatesResponse, error)
+	ReleaseAddresses(ctx context.Context, in *ReleaseAddressesRequest, opts ...grpc.CallOption) (*ReleaseAddressesResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInvoicesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListInvoices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExpireInvoice(ctx context.Context, in *ExpireInvoiceRequest, opts ...grpc.CallOption) (*ExpireInvoiceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExpireInvoiceResponse)
+	err := c.cc.Invoke(ctx, AdminService_ExpireInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RecheckInvoice(ctx context.Context, in *RecheckInvoiceRequest, opts ...grpc.CallOption) (*RecheckInvoiceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecheckInvoiceResponse)
+	err := c.cc.Invoke(ctx, AdminService_RecheckInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetSyncStates(ctx context.Context, in *GetSyncStatesRequest, opts ...grpc.CallOption) (*GetSyncStatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSyncStatesResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetSyncStates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ReleaseAddresses(ctx context.Context, in *ReleaseAddressesRequest, opts ...grpc.CallOption) (*ReleaseAddressesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseAddressesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ReleaseAddresses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+//
+// Every call requires an admin api key
+type AdminServiceServer interface {
+	ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error)
+	// Expires a pending invoice right away, it's still watched for late payments during the grace period
+	ExpireInvoice(context.Context, *ExpireInvoiceRequest) (*ExpireInvoiceResponse, error)
+	// Starts tracking a pending invoice again if it isn't and verifies its payments without waiting for the next block
+	RecheckInvoice(context.Context, *RecheckInvoiceRequest) (*RecheckInvoiceResponse, error)
+	GetSyncStates(context.Context, *GetSyncStatesRequest) (*GetSyncStatesResponse, error)
+	ReleaseAddresses(context.Context, *ReleaseAddressesRequest) (*ReleaseAddressesResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
+}
+func (UnimplementedAdminServiceServer) ExpireInvoice(context.Context, *ExpireInvoiceRequest) (*ExpireInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExpireInvoice not implemented")
+}
+func (UnimplementedAdminServiceServer) RecheckInvoice(context.Context, *RecheckInvoiceRequest) (*RecheckInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecheckInvoice not implemented")
+}
+func (UnimplementedAdminServiceServer) GetSyncStates(context.Context, *GetSyncStatesRequest) (*GetSyncStatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSyncStates not implemented")
+}
+func (UnimplementedAdminServiceServer) ReleaseAddresses(context.Context, *ReleaseAddressesRequest) (*ReleaseAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseAddresses not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListInvoices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListInvoices(ctx, req.(*ListInvoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExpireInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExpireInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExpireInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExpireInvoice(ctx, req.(*ExpireInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RecheckInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecheckInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RecheckInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RecheckInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RecheckInvoice(ctx, req.(*RecheckInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetSyncStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSyncStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetSyncStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetSyncStates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetSyncStates(ctx, req.(*GetSyncStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ReleaseAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ReleaseAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ReleaseAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ReleaseAddresses(ctx, req.(*ReleaseAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "admin.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListInvoices",
+			Handler:    _AdminService_ListInvoices_Handler,
+		},
+		{
+			MethodName: "ExpireInvoice",
+			Handler:    _AdminService_ExpireInvoice_Handler,
+		},
+		{
+			MethodName: "RecheckInvoice",
+			Handler:    _AdminService_RecheckInvoice_Handler,
+		},
+		{
+			MethodName: "GetSyncStates",
+			Handler:    _AdminService_GetSyncStates_Handler,
+		},
+		{
+			MethodName: "ReleaseAddresses",
+			Handler:    _AdminService_ReleaseAddresses_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
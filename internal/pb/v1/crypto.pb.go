@@ -75,6 +75,78 @@ func (CoinType) EnumDescriptor() ([]byte, []int) {
 	return file_crypto_proto_rawDescGZIP(), []int{0}
 }
 
+type Asset struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Coin   CoinType `protobuf:"varint,1,opt,name=coin,proto3,enum=crypto.v1.CoinType" json:"coin,omitempty"`
+	Symbol string   `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// ERC-20 contract address, absent for native coins
+	Contract *string `protobuf:"bytes,3,opt,name=contract,proto3,oneof" json:"contract,omitempty"`
+	Decimals uint32  `protobuf:"varint,4,opt,name=decimals,proto3" json:"decimals,omitempty"`
+}
+
+func (x *Asset) Reset() {
+	*x = Asset{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Asset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Asset) ProtoMessage() {}
+
+func (x *Asset) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Asset.ProtoReflect.Descriptor instead.
+func (*Asset) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Asset) GetCoin() CoinType {
+	if x != nil {
+		return x.Coin
+	}
+	return CoinType_XMR
+}
+
+func (x *Asset) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Asset) GetContract() string {
+	if x != nil && x.Contract != nil {
+		return *x.Contract
+	}
+	return ""
+}
+
+func (x *Asset) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
 type XmrKeysUpdateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -87,7 +159,7 @@ type XmrKeysUpdateRequest struct {
 func (x *XmrKeysUpdateRequest) Reset() {
 	*x = XmrKeysUpdateRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_crypto_proto_msgTypes[0]
+		mi := &file_crypto_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -100,7 +172,7 @@ func (x *XmrKeysUpdateRequest) String() string {
 func (*XmrKeysUpdateRequest) ProtoMessage() {}
 
 func (x *XmrKeysUpdateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_crypto_proto_msgTypes[0]
+	mi := &file_crypto_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,7 +185,7 @@ func (x *XmrKeysUpdateRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use XmrKeysUpdateRequest.ProtoReflect.Descriptor instead.
 func (*XmrKeysUpdateRequest) Descriptor() ([]byte, []int) {
-	return file_crypto_proto_rawDescGZIP(), []int{0}
+	return file_crypto_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *XmrKeysUpdateRequest) GetPrivViewKey() string {
@@ -135,6 +207,7 @@ type XmrKeys struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// Empty unless includePrivViewKey is requested
 	PrivViewKey string `protobuf:"bytes,1,opt,name=privViewKey,proto3" json:"privViewKey,omitempty"`
 	PubSpendKey string `protobuf:"bytes,2,opt,name=pubSpendKey,proto3" json:"pubSpendKey,omitempty"`
 }
@@ -142,7 +215,7 @@ type XmrKeys struct {
 func (x *XmrKeys) Reset() {
 	*x = XmrKeys{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_crypto_proto_msgTypes[1]
+		mi := &file_crypto_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -155,7 +228,7 @@ func (x *XmrKeys) String() string {
 func (*XmrKeys) ProtoMessage() {}
 
 func (x *XmrKeys) ProtoReflect() protoreflect.Message {
-	mi := &file_crypto_proto_msgTypes[1]
+	mi := &file_crypto_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,7 +241,7 @@ func (x *XmrKeys) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use XmrKeys.ProtoReflect.Descriptor instead.
 func (*XmrKeys) Descriptor() ([]byte, []int) {
-	return file_crypto_proto_rawDescGZIP(), []int{1}
+	return file_crypto_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *XmrKeys) GetPrivViewKey() string {
@@ -185,26 +258,438 @@ func (x *XmrKeys) GetPubSpendKey() string {
 	return ""
 }
 
+type BtcKeysUpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MasterPubKey string `protobuf:"bytes,1,opt,name=masterPubKey,proto3" json:"masterPubKey,omitempty"`
+}
+
+func (x *BtcKeysUpdateRequest) Reset() {
+	*x = BtcKeysUpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BtcKeysUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BtcKeysUpdateRequest) ProtoMessage() {}
+
+func (x *BtcKeysUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BtcKeysUpdateRequest.ProtoReflect.Descriptor instead.
+func (*BtcKeysUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BtcKeysUpdateRequest) GetMasterPubKey() string {
+	if x != nil {
+		return x.MasterPubKey
+	}
+	return ""
+}
+
+type BtcKeys struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MasterPubKey string `protobuf:"bytes,1,opt,name=masterPubKey,proto3" json:"masterPubKey,omitempty"`
+}
+
+func (x *BtcKeys) Reset() {
+	*x = BtcKeys{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BtcKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BtcKeys) ProtoMessage() {}
+
+func (x *BtcKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BtcKeys.ProtoReflect.Descriptor instead.
+func (*BtcKeys) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BtcKeys) GetMasterPubKey() string {
+	if x != nil {
+		return x.MasterPubKey
+	}
+	return ""
+}
+
+type LtcKeysUpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MasterPubKey string `protobuf:"bytes,1,opt,name=masterPubKey,proto3" json:"masterPubKey,omitempty"`
+}
+
+func (x *LtcKeysUpdateRequest) Reset() {
+	*x = LtcKeysUpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LtcKeysUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LtcKeysUpdateRequest) ProtoMessage() {}
+
+func (x *LtcKeysUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LtcKeysUpdateRequest.ProtoReflect.Descriptor instead.
+func (*LtcKeysUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LtcKeysUpdateRequest) GetMasterPubKey() string {
+	if x != nil {
+		return x.MasterPubKey
+	}
+	return ""
+}
+
+type LtcKeys struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MasterPubKey string `protobuf:"bytes,1,opt,name=masterPubKey,proto3" json:"masterPubKey,omitempty"`
+}
+
+func (x *LtcKeys) Reset() {
+	*x = LtcKeys{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LtcKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LtcKeys) ProtoMessage() {}
+
+func (x *LtcKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LtcKeys.ProtoReflect.Descriptor instead.
+func (*LtcKeys) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *LtcKeys) GetMasterPubKey() string {
+	if x != nil {
+		return x.MasterPubKey
+	}
+	return ""
+}
+
+type EthKeysUpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MasterPubKey string `protobuf:"bytes,1,opt,name=masterPubKey,proto3" json:"masterPubKey,omitempty"`
+}
+
+func (x *EthKeysUpdateRequest) Reset() {
+	*x = EthKeysUpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EthKeysUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EthKeysUpdateRequest) ProtoMessage() {}
+
+func (x *EthKeysUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EthKeysUpdateRequest.ProtoReflect.Descriptor instead.
+func (*EthKeysUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EthKeysUpdateRequest) GetMasterPubKey() string {
+	if x != nil {
+		return x.MasterPubKey
+	}
+	return ""
+}
+
+type EthKeys struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MasterPubKey string `protobuf:"bytes,1,opt,name=masterPubKey,proto3" json:"masterPubKey,omitempty"`
+}
+
+func (x *EthKeys) Reset() {
+	*x = EthKeys{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EthKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EthKeys) ProtoMessage() {}
+
+func (x *EthKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EthKeys.ProtoReflect.Descriptor instead.
+func (*EthKeys) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EthKeys) GetMasterPubKey() string {
+	if x != nil {
+		return x.MasterPubKey
+	}
+	return ""
+}
+
+type TonKeysUpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The merchant wallet address shared by every TON invoice of the user
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *TonKeysUpdateRequest) Reset() {
+	*x = TonKeysUpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TonKeysUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TonKeysUpdateRequest) ProtoMessage() {}
+
+func (x *TonKeysUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TonKeysUpdateRequest.ProtoReflect.Descriptor instead.
+func (*TonKeysUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TonKeysUpdateRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type TonKeys struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *TonKeys) Reset() {
+	*x = TonKeys{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_crypto_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TonKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TonKeys) ProtoMessage() {}
+
+func (x *TonKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TonKeys.ProtoReflect.Descriptor instead.
+func (*TonKeys) Descriptor() ([]byte, []int) {
+	return file_crypto_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TonKeys) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
 var File_crypto_proto protoreflect.FileDescriptor
 
 var file_crypto_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
-	0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x22, 0x5a, 0x0a, 0x14, 0x58, 0x6d, 0x72,
+	0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x22, 0x92, 0x01, 0x0a, 0x05, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x63, 0x6f, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x13, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x69, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1f, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c,
+	0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x22, 0x5a,
+	0x0a, 0x14, 0x58, 0x6d, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x56, 0x69,
+	0x65, 0x77, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x69,
+	0x76, 0x56, 0x69, 0x65, 0x77, 0x4b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x75, 0x62, 0x53,
+	0x70, 0x65, 0x6e, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x75, 0x62, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x4b, 0x65, 0x79, 0x22, 0x4d, 0x0a, 0x07, 0x58, 0x6d,
+	0x72, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x56, 0x69, 0x65,
+	0x77, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x69, 0x76,
+	0x56, 0x69, 0x65, 0x77, 0x4b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x75, 0x62, 0x53, 0x70,
+	0x65, 0x6e, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x75,
+	0x62, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x4b, 0x65, 0x79, 0x22, 0x3a, 0x0a, 0x14, 0x42, 0x74, 0x63,
 	0x4b, 0x65, 0x79, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x56, 0x69, 0x65, 0x77, 0x4b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x56, 0x69, 0x65, 0x77,
-	0x4b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x75, 0x62, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x4b,
-	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x75, 0x62, 0x53, 0x70, 0x65,
-	0x6e, 0x64, 0x4b, 0x65, 0x79, 0x22, 0x4d, 0x0a, 0x07, 0x58, 0x6d, 0x72, 0x4b, 0x65, 0x79, 0x73,
-	0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x56, 0x69, 0x65, 0x77, 0x4b, 0x65, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x56, 0x69, 0x65, 0x77, 0x4b,
-	0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x75, 0x62, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x4b, 0x65,
-	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x75, 0x62, 0x53, 0x70, 0x65, 0x6e,
-	0x64, 0x4b, 0x65, 0x79, 0x2a, 0x37, 0x0a, 0x08, 0x43, 0x6f, 0x69, 0x6e, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x07, 0x0a, 0x03, 0x58, 0x4d, 0x52, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x54, 0x43,
-	0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x54, 0x43, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x45,
-	0x54, 0x48, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x4f, 0x4e, 0x10, 0x04, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0x2d, 0x0a, 0x07, 0x42, 0x74, 0x63, 0x4b, 0x65, 0x79, 0x73,
+	0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75,
+	0x62, 0x4b, 0x65, 0x79, 0x22, 0x3a, 0x0a, 0x14, 0x4c, 0x74, 0x63, 0x4b, 0x65, 0x79, 0x73, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x0c,
+	0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x22, 0x2d, 0x0a, 0x07, 0x4c, 0x74, 0x63, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x6d,
+	0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22,
+	0x3a, 0x0a, 0x14, 0x45, 0x74, 0x68, 0x4b, 0x65, 0x79, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65,
+	0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d,
+	0x61, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0x2d, 0x0a, 0x07, 0x45,
+	0x74, 0x68, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x61,
+	0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0x30, 0x0a, 0x14, 0x54, 0x6f,
+	0x6e, 0x4b, 0x65, 0x79, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x23, 0x0a, 0x07,
+	0x54, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x2a, 0x37, 0x0a, 0x08, 0x43, 0x6f, 0x69, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a,
+	0x03, 0x58, 0x4d, 0x52, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x54, 0x43, 0x10, 0x01, 0x12,
+	0x07, 0x0a, 0x03, 0x4c, 0x54, 0x43, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x54, 0x48, 0x10,
+	0x03, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x4f, 0x4e, 0x10, 0x04, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -220,18 +705,28 @@ func file_crypto_proto_rawDescGZIP() []byte {
 }
 
 var file_crypto_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_crypto_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_crypto_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_crypto_proto_goTypes = []any{
 	(CoinType)(0),                // 0: crypto.v1.CoinType
-	(*XmrKeysUpdateRequest)(nil), // 1: crypto.v1.XmrKeysUpdateRequest
-	(*XmrKeys)(nil),              // 2: crypto.v1.XmrKeys
+	(*Asset)(nil),                // 1: crypto.v1.Asset
+	(*XmrKeysUpdateRequest)(nil), // 2: crypto.v1.XmrKeysUpdateRequest
+	(*XmrKeys)(nil),              // 3: crypto.v1.XmrKeys
+	(*BtcKeysUpdateRequest)(nil), // 4: crypto.v1.BtcKeysUpdateRequest
+	(*BtcKeys)(nil),              // 5: crypto.v1.BtcKeys
+	(*LtcKeysUpdateRequest)(nil), // 6: crypto.v1.LtcKeysUpdateRequest
+	(*LtcKeys)(nil),              // 7: crypto.v1.LtcKeys
+	(*EthKeysUpdateRequest)(nil), // 8: crypto.v1.EthKeysUpdateRequest
+	(*EthKeys)(nil),              // 9: crypto.v1.EthKeys
+	(*TonKeysUpdateRequest)(nil), // 10: crypto.v1.TonKeysUpdateRequest
+	(*TonKeys)(nil),              // 11: crypto.v1.TonKeys
 }
 var file_crypto_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: crypto.v1.Asset.coin:type_name -> crypto.v1.CoinType
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_crypto_proto_init() }
@@ -241,7 +736,7 @@ func file_crypto_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_crypto_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*XmrKeysUpdateRequest); i {
+			switch v := v.(*Asset); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -253,6 +748,18 @@ func file_crypto_proto_init() {
 			}
 		}
 		file_crypto_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*XmrKeysUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[2].Exporter = func(v any, i int) any {
 			switch v := v.(*XmrKeys); i {
 			case 0:
 				return &v.state
@@ -264,14 +771,111 @@ func file_crypto_proto_init() {
 				return nil
 			}
 		}
+		file_crypto_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*BtcKeysUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*BtcKeys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*LtcKeysUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*LtcKeys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*EthKeysUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*EthKeys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*TonKeysUpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_crypto_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*TonKeys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
+	file_crypto_proto_msgTypes[0].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_crypto_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   2,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
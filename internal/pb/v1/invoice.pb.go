@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -28,6 +29,12 @@ const (
 	InvoiceStatusType_PENDING_MEMPOOL InvoiceStatusType = 1
 	InvoiceStatusType_EXPIRED         InvoiceStatusType = 2
 	InvoiceStatusType_CONFIRMED       InvoiceStatusType = 3
+	// Some payments were received but their total doesn't cover the required amount yet
+	InvoiceStatusType_PARTIALLY_PAID InvoiceStatusType = 4
+	// Funds arrived within the late payment grace period after the invoice had expired
+	InvoiceStatusType_PAID_AFTER_EXPIRY InvoiceStatusType = 5
+	// The block with the payment was orphaned by a chain reorganization and the tx is gone
+	InvoiceStatusType_REORGED InvoiceStatusType = 6
 )
 
 // Enum value maps for InvoiceStatusType.
@@ -37,12 +44,18 @@ var (
 		1: "PENDING_MEMPOOL",
 		2: "EXPIRED",
 		3: "CONFIRMED",
+		4: "PARTIALLY_PAID",
+		5: "PAID_AFTER_EXPIRY",
+		6: "REORGED",
 	}
 	InvoiceStatusType_value = map[string]int32{
-		"PENDING":         0,
-		"PENDING_MEMPOOL": 1,
-		"EXPIRED":         2,
-		"CONFIRMED":       3,
+		"PENDING":           0,
+		"PENDING_MEMPOOL":   1,
+		"EXPIRED":           2,
+		"CONFIRMED":         3,
+		"PARTIALLY_PAID":    4,
+		"PAID_AFTER_EXPIRY": 5,
+		"REORGED":           6,
 	}
 )
 
@@ -73,15 +86,156 @@ func (InvoiceStatusType) EnumDescriptor() ([]byte, []int) {
 	return file_invoice_proto_rawDescGZIP(), []int{0}
 }
 
+// How the paid amount compares to the required one, set once the invoice is paid
+type InvoiceOutcomeType int32
+
+const (
+	InvoiceOutcomeType_NONE  InvoiceOutcomeType = 0
+	InvoiceOutcomeType_EXACT InvoiceOutcomeType = 1
+	// Paid less than required but within the tolerance
+	InvoiceOutcomeType_UNDERPAID InvoiceOutcomeType = 2
+	InvoiceOutcomeType_OVERPAID  InvoiceOutcomeType = 3
+)
+
+// Enum value maps for InvoiceOutcomeType.
+var (
+	InvoiceOutcomeType_name = map[int32]string{
+		0: "NONE",
+		1: "EXACT",
+		2: "UNDERPAID",
+		3: "OVERPAID",
+	}
+	InvoiceOutcomeType_value = map[string]int32{
+		"NONE":      0,
+		"EXACT":     1,
+		"UNDERPAID": 2,
+		"OVERPAID":  3,
+	}
+)
+
+func (x InvoiceOutcomeType) Enum() *InvoiceOutcomeType {
+	p := new(InvoiceOutcomeType)
+	*p = x
+	return p
+}
+
+func (x InvoiceOutcomeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InvoiceOutcomeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_invoice_proto_enumTypes[1].Descriptor()
+}
+
+func (InvoiceOutcomeType) Type() protoreflect.EnumType {
+	return &file_invoice_proto_enumTypes[1]
+}
+
+func (x InvoiceOutcomeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InvoiceOutcomeType.Descriptor instead.
+func (InvoiceOutcomeType) EnumDescriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{1}
+}
+
+type InvoicePayment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TxId string `protobuf:"bytes,1,opt,name=txId,proto3" json:"txId,omitempty"`
+	// The index of the paid output within the tx (the log index for ERC-20 transfers)
+	OutputIndex  uint32 `protobuf:"varint,2,opt,name=outputIndex,proto3" json:"outputIndex,omitempty"`
+	AmountAtomic string `protobuf:"bytes,3,opt,name=amountAtomic,proto3" json:"amountAtomic,omitempty"`
+	// Absent while the tx is in the mempool
+	BlockHeight *uint64                `protobuf:"varint,4,opt,name=blockHeight,proto3,oneof" json:"blockHeight,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+}
+
+func (x *InvoicePayment) Reset() {
+	*x = InvoicePayment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_invoice_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InvoicePayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoicePayment) ProtoMessage() {}
+
+func (x *InvoicePayment) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoicePayment.ProtoReflect.Descriptor instead.
+func (*InvoicePayment) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InvoicePayment) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+func (x *InvoicePayment) GetOutputIndex() uint32 {
+	if x != nil {
+		return x.OutputIndex
+	}
+	return 0
+}
+
+func (x *InvoicePayment) GetAmountAtomic() string {
+	if x != nil {
+		return x.AmountAtomic
+	}
+	return ""
+}
+
+func (x *InvoicePayment) GetBlockHeight() uint64 {
+	if x != nil && x.BlockHeight != nil {
+		return *x.BlockHeight
+	}
+	return 0
+}
+
+func (x *InvoicePayment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type Invoice struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	CryptoAddress         string                 `protobuf:"bytes,2,opt,name=cryptoAddress,proto3" json:"cryptoAddress,omitempty"`
-	Coin                  CoinType               `protobuf:"varint,3,opt,name=coin,proto3,enum=crypto.v1.CoinType" json:"coin,omitempty"`
-	RequiredAmount        float64                `protobuf:"fixed64,4,opt,name=requiredAmount,proto3" json:"requiredAmount,omitempty"`
+	Id            string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CryptoAddress string   `protobuf:"bytes,2,opt,name=cryptoAddress,proto3" json:"cryptoAddress,omitempty"`
+	Coin          CoinType `protobuf:"varint,3,opt,name=coin,proto3,enum=crypto.v1.CoinType" json:"coin,omitempty"`
+	// Deprecated: lossy, use requiredAmountAtomic
+	//
+	// Deprecated: Marked as deprecated in invoice.proto.
+	RequiredAmount float64 `protobuf:"fixed64,4,opt,name=requiredAmount,proto3" json:"requiredAmount,omitempty"`
+	// Deprecated: lossy, use actualAmountAtomic
+	//
+	// Deprecated: Marked as deprecated in invoice.proto.
 	ActualAmount          float64                `protobuf:"fixed64,5,opt,name=actualAmount,proto3" json:"actualAmount,omitempty"`
 	ConfirmationsRequired uint32                 `protobuf:"varint,6,opt,name=confirmationsRequired,proto3" json:"confirmationsRequired,omitempty"`
 	CreatedAt             *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
@@ -90,12 +244,30 @@ type Invoice struct {
 	ExpiresAt             *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
 	TxId                  string                 `protobuf:"bytes,11,opt,name=txId,proto3" json:"txId,omitempty"`
 	UserId                string                 `protobuf:"bytes,12,opt,name=userId,proto3" json:"userId,omitempty"`
+	// ERC-20 contract address, absent for native coin invoices
+	Token *string `protobuf:"bytes,13,opt,name=token,proto3,oneof" json:"token,omitempty"`
+	// The comment a payer must attach to a TON payment
+	Memo *string `protobuf:"bytes,14,opt,name=memo,proto3,oneof" json:"memo,omitempty"`
+	// Amounts in atomic units of the asset (piconero, satoshi, wei, nanoton or token units) as decimal strings
+	RequiredAmountAtomic string `protobuf:"bytes,15,opt,name=requiredAmountAtomic,proto3" json:"requiredAmountAtomic,omitempty"`
+	ActualAmountAtomic   string `protobuf:"bytes,16,opt,name=actualAmountAtomic,proto3" json:"actualAmountAtomic,omitempty"`
+	// The number of decimals of the asset, e.g. 12 for XMR
+	Decimals uint32 `protobuf:"varint,17,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	// Every tx output matched to the invoice, actualAmountAtomic is their sum
+	Payments []*InvoicePayment `protobuf:"bytes,18,rep,name=payments,proto3" json:"payments,omitempty"`
+	// How much less than the required amount is still accepted, in atomic units
+	ToleranceAtomic string             `protobuf:"bytes,19,opt,name=toleranceAtomic,proto3" json:"toleranceAtomic,omitempty"`
+	Outcome         InvoiceOutcomeType `protobuf:"varint,20,opt,name=outcome,proto3,enum=invoice.v1.InvoiceOutcomeType" json:"outcome,omitempty"`
+	// actualAmountAtomic - requiredAmountAtomic once the invoice is paid: negative for UNDERPAID, positive for OVERPAID
+	AmountDeltaAtomic *string `protobuf:"bytes,21,opt,name=amountDeltaAtomic,proto3,oneof" json:"amountDeltaAtomic,omitempty"`
+	// Confirmations of the least confirmed payment tx, up to confirmationsRequired
+	Confirmations uint32 `protobuf:"varint,22,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
 }
 
 func (x *Invoice) Reset() {
 	*x = Invoice{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_invoice_proto_msgTypes[0]
+		mi := &file_invoice_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -108,7 +280,7 @@ func (x *Invoice) String() string {
 func (*Invoice) ProtoMessage() {}
 
 func (x *Invoice) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[0]
+	mi := &file_invoice_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -121,7 +293,7 @@ func (x *Invoice) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Invoice.ProtoReflect.Descriptor instead.
 func (*Invoice) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{0}
+	return file_invoice_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Invoice) GetId() string {
@@ -145,6 +317,7 @@ func (x *Invoice) GetCoin() CoinType {
 	return CoinType_XMR
 }
 
+// Deprecated: Marked as deprecated in invoice.proto.
 func (x *Invoice) GetRequiredAmount() float64 {
 	if x != nil {
 		return x.RequiredAmount
@@ -152,6 +325,7 @@ func (x *Invoice) GetRequiredAmount() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in invoice.proto.
 func (x *Invoice) GetActualAmount() float64 {
 	if x != nil {
 		return x.ActualAmount
@@ -208,22 +382,184 @@ func (x *Invoice) GetUserId() string {
 	return ""
 }
 
+func (x *Invoice) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *Invoice) GetMemo() string {
+	if x != nil && x.Memo != nil {
+		return *x.Memo
+	}
+	return ""
+}
+
+func (x *Invoice) GetRequiredAmountAtomic() string {
+	if x != nil {
+		return x.RequiredAmountAtomic
+	}
+	return ""
+}
+
+func (x *Invoice) GetActualAmountAtomic() string {
+	if x != nil {
+		return x.ActualAmountAtomic
+	}
+	return ""
+}
+
+func (x *Invoice) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *Invoice) GetPayments() []*InvoicePayment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *Invoice) GetToleranceAtomic() string {
+	if x != nil {
+		return x.ToleranceAtomic
+	}
+	return ""
+}
+
+func (x *Invoice) GetOutcome() InvoiceOutcomeType {
+	if x != nil {
+		return x.Outcome
+	}
+	return InvoiceOutcomeType_NONE
+}
+
+func (x *Invoice) GetAmountDeltaAtomic() string {
+	if x != nil && x.AmountDeltaAtomic != nil {
+		return *x.AmountDeltaAtomic
+	}
+	return ""
+}
+
+func (x *Invoice) GetConfirmations() uint32 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+// The underpayment an invoice still accepts as paid
+type AmountTolerance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Tolerance:
+	//	*AmountTolerance_AbsoluteAtomic
+	//	*AmountTolerance_Percent
+	Tolerance isAmountTolerance_Tolerance `protobuf_oneof:"tolerance"`
+}
+
+func (x *AmountTolerance) Reset() {
+	*x = AmountTolerance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_invoice_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AmountTolerance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmountTolerance) ProtoMessage() {}
+
+func (x *AmountTolerance) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmountTolerance.ProtoReflect.Descriptor instead.
+func (*AmountTolerance) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *AmountTolerance) GetTolerance() isAmountTolerance_Tolerance {
+	if m != nil {
+		return m.Tolerance
+	}
+	return nil
+}
+
+func (x *AmountTolerance) GetAbsoluteAtomic() string {
+	if x, ok := x.GetTolerance().(*AmountTolerance_AbsoluteAtomic); ok {
+		return x.AbsoluteAtomic
+	}
+	return ""
+}
+
+func (x *AmountTolerance) GetPercent() float64 {
+	if x, ok := x.GetTolerance().(*AmountTolerance_Percent); ok {
+		return x.Percent
+	}
+	return 0
+}
+
+type isAmountTolerance_Tolerance interface {
+	isAmountTolerance_Tolerance()
+}
+
+type AmountTolerance_AbsoluteAtomic struct {
+	// Absolute tolerance in atomic units as a decimal string
+	AbsoluteAtomic string `protobuf:"bytes,1,opt,name=absoluteAtomic,proto3,oneof"`
+}
+
+type AmountTolerance_Percent struct {
+	// Percentage of the required amount, from 0 to 100
+	Percent float64 `protobuf:"fixed64,2,opt,name=percent,proto3,oneof"`
+}
+
+func (*AmountTolerance_AbsoluteAtomic) isAmountTolerance_Tolerance() {}
+
+func (*AmountTolerance_Percent) isAmountTolerance_Tolerance() {}
+
 type CreateInvoiceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserId        string   `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
-	Coin          CoinType `protobuf:"varint,2,opt,name=coin,proto3,enum=crypto.v1.CoinType" json:"coin,omitempty"`
-	Amount        float64  `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
-	Timeout       uint64   `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Confirmations uint32   `protobuf:"varint,5,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	UserId string   `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Coin   CoinType `protobuf:"varint,2,opt,name=coin,proto3,enum=crypto.v1.CoinType" json:"coin,omitempty"`
+	// Deprecated: lossy, use amountAtomic
+	//
+	// Deprecated: Marked as deprecated in invoice.proto.
+	Amount        float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Timeout       uint64  `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Confirmations uint32  `protobuf:"varint,5,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	// Symbol or contract address of a configured token (see ListSupportedAssets)
+	Token *string `protobuf:"bytes,6,opt,name=token,proto3,oneof" json:"token,omitempty"`
+	// Amount in atomic units of the asset as a decimal string, takes precedence over amount
+	AmountAtomic *string `protobuf:"bytes,7,opt,name=amountAtomic,proto3,oneof" json:"amountAtomic,omitempty"`
+	// No underpayment is accepted if absent
+	Tolerance *AmountTolerance `protobuf:"bytes,8,opt,name=tolerance,proto3" json:"tolerance,omitempty"`
 }
 
 func (x *CreateInvoiceRequest) Reset() {
 	*x = CreateInvoiceRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_invoice_proto_msgTypes[1]
+		mi := &file_invoice_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -236,7 +572,7 @@ func (x *CreateInvoiceRequest) String() string {
 func (*CreateInvoiceRequest) ProtoMessage() {}
 
 func (x *CreateInvoiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[1]
+	mi := &file_invoice_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -249,7 +585,7 @@ func (x *CreateInvoiceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateInvoiceRequest.ProtoReflect.Descriptor instead.
 func (*CreateInvoiceRequest) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{1}
+	return file_invoice_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateInvoiceRequest) GetUserId() string {
@@ -266,6 +602,7 @@ func (x *CreateInvoiceRequest) GetCoin() CoinType {
 	return CoinType_XMR
 }
 
+// Deprecated: Marked as deprecated in invoice.proto.
 func (x *CreateInvoiceRequest) GetAmount() float64 {
 	if x != nil {
 		return x.Amount
@@ -287,6 +624,27 @@ func (x *CreateInvoiceRequest) GetConfirmations() uint32 {
 	return 0
 }
 
+func (x *CreateInvoiceRequest) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *CreateInvoiceRequest) GetAmountAtomic() string {
+	if x != nil && x.AmountAtomic != nil {
+		return *x.AmountAtomic
+	}
+	return ""
+}
+
+func (x *CreateInvoiceRequest) GetTolerance() *AmountTolerance {
+	if x != nil {
+		return x.Tolerance
+	}
+	return nil
+}
+
 type CreateInvoiceResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -294,12 +652,14 @@ type CreateInvoiceResponse struct {
 
 	PaymentId string `protobuf:"bytes,1,opt,name=paymentId,proto3" json:"paymentId,omitempty"`
 	Address   string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// The comment a payer must attach to the payment, set for TON invoices only
+	Memo *string `protobuf:"bytes,3,opt,name=memo,proto3,oneof" json:"memo,omitempty"`
 }
 
 func (x *CreateInvoiceResponse) Reset() {
 	*x = CreateInvoiceResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_invoice_proto_msgTypes[2]
+		mi := &file_invoice_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -312,7 +672,7 @@ func (x *CreateInvoiceResponse) String() string {
 func (*CreateInvoiceResponse) ProtoMessage() {}
 
 func (x *CreateInvoiceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[2]
+	mi := &file_invoice_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,7 +685,7 @@ func (x *CreateInvoiceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateInvoiceResponse.ProtoReflect.Descriptor instead.
 func (*CreateInvoiceResponse) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{2}
+	return file_invoice_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateInvoiceResponse) GetPaymentId() string {
@@ -342,6 +702,13 @@ func (x *CreateInvoiceResponse) GetAddress() string {
 	return ""
 }
 
+func (x *CreateInvoiceResponse) GetMemo() string {
+	if x != nil && x.Memo != nil {
+		return *x.Memo
+	}
+	return ""
+}
+
 type GetInvoicesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -353,7 +720,7 @@ type GetInvoicesRequest struct {
 func (x *GetInvoicesRequest) Reset() {
 	*x = GetInvoicesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_invoice_proto_msgTypes[3]
+		mi := &file_invoice_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -366,7 +733,7 @@ func (x *GetInvoicesRequest) String() string {
 func (*GetInvoicesRequest) ProtoMessage() {}
 
 func (x *GetInvoicesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[3]
+	mi := &file_invoice_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -379,7 +746,7 @@ func (x *GetInvoicesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInvoicesRequest.ProtoReflect.Descriptor instead.
 func (*GetInvoicesRequest) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{3}
+	return file_invoice_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetInvoicesRequest) GetPaymentIds() []string {
@@ -400,7 +767,7 @@ type GetInvoicesResponse struct {
 func (x *GetInvoicesResponse) Reset() {
 	*x = GetInvoicesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_invoice_proto_msgTypes[4]
+		mi := &file_invoice_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -413,7 +780,7 @@ func (x *GetInvoicesResponse) String() string {
 func (*GetInvoicesResponse) ProtoMessage() {}
 
 func (x *GetInvoicesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[4]
+	mi := &file_invoice_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -426,7 +793,7 @@ func (x *GetInvoicesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInvoicesResponse.ProtoReflect.Descriptor instead.
 func (*GetInvoicesResponse) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{4}
+	return file_invoice_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetInvoicesResponse) GetInvoices() []*Invoice {
@@ -436,16 +803,109 @@ func (x *GetInvoicesResponse) GetInvoices() []*Invoice {
 	return nil
 }
 
+type ListSupportedAssetsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListSupportedAssetsRequest) Reset() {
+	*x = ListSupportedAssetsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_invoice_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSupportedAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSupportedAssetsRequest) ProtoMessage() {}
+
+func (x *ListSupportedAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSupportedAssetsRequest.ProtoReflect.Descriptor instead.
+func (*ListSupportedAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{7}
+}
+
+type ListSupportedAssetsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Assets []*Asset `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+}
+
+func (x *ListSupportedAssetsResponse) Reset() {
+	*x = ListSupportedAssetsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_invoice_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSupportedAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSupportedAssetsResponse) ProtoMessage() {}
+
+func (x *ListSupportedAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_invoice_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSupportedAssetsResponse.ProtoReflect.Descriptor instead.
+func (*ListSupportedAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_invoice_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSupportedAssetsResponse) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+// Every filter narrows the stream down, an empty one matches all invoices
 type InvoiceStatusStreamRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	UserIds    []string            `protobuf:"bytes,1,rep,name=userIds,proto3" json:"userIds,omitempty"`
+	PaymentIds []string            `protobuf:"bytes,2,rep,name=paymentIds,proto3" json:"paymentIds,omitempty"`
+	Coins      []CoinType          `protobuf:"varint,3,rep,packed,name=coins,proto3,enum=crypto.v1.CoinType" json:"coins,omitempty"`
+	Statuses   []InvoiceStatusType `protobuf:"varint,4,rep,packed,name=statuses,proto3,enum=invoice.v1.InvoiceStatusType" json:"statuses,omitempty"`
+	// Replays the persisted events after this sequence before switching to live ones, 0 replays all of them
+	FromSequence *uint64 `protobuf:"varint,5,opt,name=fromSequence,proto3,oneof" json:"fromSequence,omitempty"`
 }
 
 func (x *InvoiceStatusStreamRequest) Reset() {
 	*x = InvoiceStatusStreamRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_invoice_proto_msgTypes[5]
+		mi := &file_invoice_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -458,7 +918,7 @@ func (x *InvoiceStatusStreamRequest) String() string {
 func (*InvoiceStatusStreamRequest) ProtoMessage() {}
 
 func (x *InvoiceStatusStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[5]
+	mi := &file_invoice_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -471,7 +931,42 @@ func (x *InvoiceStatusStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InvoiceStatusStreamRequest.ProtoReflect.Descriptor instead.
 func (*InvoiceStatusStreamRequest) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{5}
+	return file_invoice_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *InvoiceStatusStreamRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *InvoiceStatusStreamRequest) GetPaymentIds() []string {
+	if x != nil {
+		return x.PaymentIds
+	}
+	return nil
+}
+
+func (x *InvoiceStatusStreamRequest) GetCoins() []CoinType {
+	if x != nil {
+		return x.Coins
+	}
+	return nil
+}
+
+func (x *InvoiceStatusStreamRequest) GetStatuses() []InvoiceStatusType {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *InvoiceStatusStreamRequest) GetFromSequence() uint64 {
+	if x != nil && x.FromSequence != nil {
+		return *x.FromSequence
+	}
+	return 0
 }
 
 type InvoiceStatusStreamResponse struct {
@@ -480,12 +975,14 @@ type InvoiceStatusStreamResponse struct {
 	unknownFields protoimpl.UnknownFields
 
 	Invoice *Invoice `protobuf:"bytes,1,opt,name=invoice,proto3" json:"invoice,omitempty"`
+	// Position of the event in the event log, pass it as fromSequence to resume the stream
+	Sequence uint64 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
 }
 
 func (x *InvoiceStatusStreamResponse) Reset() {
 	*x = InvoiceStatusStreamResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_invoice_proto_msgTypes[6]
+		mi := &file_invoice_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -498,7 +995,7 @@ func (x *InvoiceStatusStreamResponse) String() string {
 func (*InvoiceStatusStreamResponse) ProtoMessage() {}
 
 func (x *InvoiceStatusStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_invoice_proto_msgTypes[6]
+	mi := &file_invoice_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,7 +1008,7 @@ func (x *InvoiceStatusStreamResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InvoiceStatusStreamResponse.ProtoReflect.Descriptor instead.
 func (*InvoiceStatusStreamResponse) Descriptor() ([]byte, []int) {
-	return file_invoice_proto_rawDescGZIP(), []int{6}
+	return file_invoice_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *InvoiceStatusStreamResponse) GetInvoice() *Invoice {
@@ -521,101 +1018,212 @@ func (x *InvoiceStatusStreamResponse) GetInvoice() *Invoice {
 	return nil
 }
 
+func (x *InvoiceStatusStreamResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 var File_invoice_proto protoreflect.FileDescriptor
 
 var file_invoice_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
 	0x0a, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x63, 0x72,
-	0x79, 0x70, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xff, 0x03, 0x0a, 0x07, 0x49,
-	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f,
-	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63,
-	0x72, 0x79, 0x70, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x27, 0x0a, 0x04,
-	0x63, 0x6f, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x63, 0x72, 0x79,
-	0x70, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x04, 0x63, 0x6f, 0x69, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65,
-	0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x72,
-	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x63, 0x72, 0x79, 0x70,
+	0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdb, 0x01, 0x0a, 0x0e, 0x49, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x78, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x78, 0x49, 0x64, 0x12,
+	0x20, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69,
+	0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x41,
+	0x74, 0x6f, 0x6d, 0x69, 0x63, 0x12, 0x25, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x38, 0x0a, 0x09,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0xd9, 0x07, 0x0a, 0x07, 0x49, 0x6e, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x63, 0x6f, 0x69, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x69,
+	0x6e, 0x12, 0x2a, 0x0a, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x41, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0e, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x26, 0x0a,
 	0x0c, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
-	0x74, 0x12, 0x34, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x64, 0x41, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x01, 0x28, 0x01, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x15, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3c, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d,
+	0x65, 0x64, 0x41, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41,
-	0x74, 0x12, 0x3c, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x41, 0x74,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65, 0x64, 0x41, 0x74, 0x12,
-	0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x1d, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76,
-	0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
-	0x73, 0x41, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x74, 0x78, 0x49, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x74, 0x78, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x0c,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0xaf, 0x01, 0x0a,
-	0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x27, 0x0a,
-	0x04, 0x63, 0x6f, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x63, 0x72,
-	0x79, 0x70, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x04, 0x63, 0x6f, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x73, 0x41, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x78, 0x49, 0x64, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x78, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72,
+	0x49, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x19, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6d,
+	0x65, 0x6d, 0x6f, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x6d,
+	0x6f, 0x88, 0x01, 0x01, 0x12, 0x32, 0x0a, 0x14, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x14, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x12, 0x2e, 0x0a, 0x12, 0x61, 0x63, 0x74, 0x75,
+	0x61, 0x6c, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x63, 0x69,
+	0x6d, 0x61, 0x6c, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x63, 0x69,
+	0x6d, 0x61, 0x6c, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x0f,
+	0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65,
+	0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x12, 0x38, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4f, 0x75, 0x74, 0x63,
+	0x6f, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65,
+	0x12, 0x31, 0x0a, 0x11, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x41,
+	0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x11, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63,
+	0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x42, 0x14, 0x0a, 0x12,
+	0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x41, 0x74, 0x6f, 0x6d,
+	0x69, 0x63, 0x22, 0x64, 0x0a, 0x0f, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x54, 0x6f, 0x6c, 0x65,
+	0x72, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0e, 0x61, 0x62, 0x73, 0x6f, 0x6c, 0x75, 0x74,
+	0x65, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x0e, 0x61, 0x62, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x65, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x12,
+	0x1a, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x00, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x74,
+	0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x22, 0xcd, 0x02, 0x0a, 0x14, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x04, 0x63, 0x6f, 0x69,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x63, 0x6f,
+	0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18,
 	0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
 	0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x66,
 	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x4f,
-	0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22,
-	0x34, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x49, 0x64, 0x73, 0x22, 0x46, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f,
-	0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08,
-	0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x19,
+	0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x01, 0x52, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x88,
+	0x01, 0x01, 0x12, 0x39, 0x0a, 0x09, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x09, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x41, 0x74, 0x6f, 0x6d, 0x69, 0x63, 0x22, 0x71, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x04, 0x6d, 0x65, 0x6d,
+	0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6d, 0x65, 0x6d, 0x6f, 0x88,
+	0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x22, 0x34, 0x0a, 0x12, 0x47,
+	0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x73, 0x22, 0x46, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x69, 0x6e, 0x76, 0x6f,
+	0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x69, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52,
+	0x08, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x22, 0x1c, 0x0a, 0x1a, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x22, 0xf6, 0x01, 0x0a, 0x1a, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x73, 0x12, 0x29, 0x0a, 0x05, 0x63, 0x6f, 0x69,
+	0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x63,
+	0x6f, 0x69, 0x6e, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x12,
+	0x27, 0x0a, 0x0c, 0x66, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0c, 0x66, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x66, 0x72, 0x6f,
+	0x6d, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x68, 0x0a, 0x1b, 0x49, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x69, 0x6e, 0x76, 0x6f,
+	0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x69, 0x6e, 0x76, 0x6f,
+	0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x07,
+	0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x2a, 0x89, 0x01, 0x0a, 0x11, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x45, 0x4e,
+	0x44, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e,
+	0x47, 0x5f, 0x4d, 0x45, 0x4d, 0x50, 0x4f, 0x4f, 0x4c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x45,
+	0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4e, 0x46,
+	0x49, 0x52, 0x4d, 0x45, 0x44, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x41, 0x52, 0x54, 0x49,
+	0x41, 0x4c, 0x4c, 0x59, 0x5f, 0x50, 0x41, 0x49, 0x44, 0x10, 0x04, 0x12, 0x15, 0x0a, 0x11, 0x50,
+	0x41, 0x49, 0x44, 0x5f, 0x41, 0x46, 0x54, 0x45, 0x52, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x59,
+	0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x4f, 0x52, 0x47, 0x45, 0x44, 0x10, 0x06, 0x2a,
+	0x46, 0x0a, 0x12, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12,
+	0x09, 0x0a, 0x05, 0x45, 0x58, 0x41, 0x43, 0x54, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x55, 0x4e,
+	0x44, 0x45, 0x52, 0x50, 0x41, 0x49, 0x44, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x56, 0x45,
+	0x52, 0x50, 0x41, 0x49, 0x44, 0x10, 0x03, 0x32, 0xcb, 0x03, 0x0a, 0x0e, 0x49, 0x6e, 0x76, 0x6f,
+	0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x6d, 0x0a, 0x0d, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x20, 0x2e, 0x69, 0x6e,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49,
+	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e,
+	0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x3a, 0x01, 0x2a, 0x22, 0x0c, 0x2f, 0x76, 0x31,
+	0x2f, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12, 0x64, 0x0a, 0x0b, 0x47, 0x65, 0x74,
+	0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x0e, 0x12, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12,
+	0x68, 0x0a, 0x13, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x26, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27,
 	0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f,
-	0x69, 0x63, 0x65, 0x52, 0x08, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x22, 0x1c, 0x0a,
-	0x1a, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74,
-	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4c, 0x0a, 0x1b, 0x49,
-	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x69, 0x6e,
-	0x76, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x69, 0x6e,
-	0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
-	0x52, 0x07, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2a, 0x51, 0x0a, 0x11, 0x49, 0x6e, 0x76,
-	0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b,
-	0x0a, 0x07, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x50,
-	0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x45, 0x4d, 0x50, 0x4f, 0x4f, 0x4c, 0x10, 0x01,
-	0x12, 0x0b, 0x0a, 0x07, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0d, 0x0a,
-	0x09, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x10, 0x03, 0x32, 0xa0, 0x02, 0x0a,
-	0x0e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x54, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
-	0x12, 0x20, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x21, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f,
-	0x69, 0x63, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x68, 0x0a, 0x13, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x26, 0x2e, 0x69,
-	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63,
-	0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x7a, 0x0a, 0x13, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x12, 0x26, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x69, 0x6e, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72,
+	0x74, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -630,40 +1238,55 @@ func file_invoice_proto_rawDescGZIP() []byte {
 	return file_invoice_proto_rawDescData
 }
 
-var file_invoice_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_invoice_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_invoice_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_invoice_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_invoice_proto_goTypes = []any{
 	(InvoiceStatusType)(0),              // 0: invoice.v1.InvoiceStatusType
-	(*Invoice)(nil),                     // 1: invoice.v1.Invoice
-	(*CreateInvoiceRequest)(nil),        // 2: invoice.v1.CreateInvoiceRequest
-	(*CreateInvoiceResponse)(nil),       // 3: invoice.v1.CreateInvoiceResponse
-	(*GetInvoicesRequest)(nil),          // 4: invoice.v1.GetInvoicesRequest
-	(*GetInvoicesResponse)(nil),         // 5: invoice.v1.GetInvoicesResponse
-	(*InvoiceStatusStreamRequest)(nil),  // 6: invoice.v1.InvoiceStatusStreamRequest
-	(*InvoiceStatusStreamResponse)(nil), // 7: invoice.v1.InvoiceStatusStreamResponse
-	(CoinType)(0),                       // 8: crypto.v1.CoinType
-	(*timestamppb.Timestamp)(nil),       // 9: google.protobuf.Timestamp
+	(InvoiceOutcomeType)(0),             // 1: invoice.v1.InvoiceOutcomeType
+	(*InvoicePayment)(nil),              // 2: invoice.v1.InvoicePayment
+	(*Invoice)(nil),                     // 3: invoice.v1.Invoice
+	(*AmountTolerance)(nil),             // 4: invoice.v1.AmountTolerance
+	(*CreateInvoiceRequest)(nil),        // 5: invoice.v1.CreateInvoiceRequest
+	(*CreateInvoiceResponse)(nil),       // 6: invoice.v1.CreateInvoiceResponse
+	(*GetInvoicesRequest)(nil),          // 7: invoice.v1.GetInvoicesRequest
+	(*GetInvoicesResponse)(nil),         // 8: invoice.v1.GetInvoicesResponse
+	(*ListSupportedAssetsRequest)(nil),  // 9: invoice.v1.ListSupportedAssetsRequest
+	(*ListSupportedAssetsResponse)(nil), // 10: invoice.v1.ListSupportedAssetsResponse
+	(*InvoiceStatusStreamRequest)(nil),  // 11: invoice.v1.InvoiceStatusStreamRequest
+	(*InvoiceStatusStreamResponse)(nil), // 12: invoice.v1.InvoiceStatusStreamResponse
+	(*timestamppb.Timestamp)(nil),       // 13: google.protobuf.Timestamp
+	(CoinType)(0),                       // 14: crypto.v1.CoinType
+	(*Asset)(nil),                       // 15: crypto.v1.Asset
 }
 var file_invoice_proto_depIdxs = []int32{
-	8,  // 0: invoice.v1.Invoice.coin:type_name -> crypto.v1.CoinType
-	9,  // 1: invoice.v1.Invoice.createdAt:type_name -> google.protobuf.Timestamp
-	9,  // 2: invoice.v1.Invoice.confirmedAt:type_name -> google.protobuf.Timestamp
-	0,  // 3: invoice.v1.Invoice.status:type_name -> invoice.v1.InvoiceStatusType
-	9,  // 4: invoice.v1.Invoice.expiresAt:type_name -> google.protobuf.Timestamp
-	8,  // 5: invoice.v1.CreateInvoiceRequest.coin:type_name -> crypto.v1.CoinType
-	1,  // 6: invoice.v1.GetInvoicesResponse.invoices:type_name -> invoice.v1.Invoice
-	1,  // 7: invoice.v1.InvoiceStatusStreamResponse.invoice:type_name -> invoice.v1.Invoice
-	2,  // 8: invoice.v1.InvoiceService.CreateInvoice:input_type -> invoice.v1.CreateInvoiceRequest
-	4,  // 9: invoice.v1.InvoiceService.GetInvoices:input_type -> invoice.v1.GetInvoicesRequest
-	6,  // 10: invoice.v1.InvoiceService.InvoiceStatusStream:input_type -> invoice.v1.InvoiceStatusStreamRequest
-	3,  // 11: invoice.v1.InvoiceService.CreateInvoice:output_type -> invoice.v1.CreateInvoiceResponse
-	5,  // 12: invoice.v1.InvoiceService.GetInvoices:output_type -> invoice.v1.GetInvoicesResponse
-	7,  // 13: invoice.v1.InvoiceService.InvoiceStatusStream:output_type -> invoice.v1.InvoiceStatusStreamResponse
-	11, // [11:14] is the sub-list for method output_type
-	8,  // [8:11] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	13, // 0: invoice.v1.InvoicePayment.createdAt:type_name -> google.protobuf.Timestamp
+	14, // 1: invoice.v1.Invoice.coin:type_name -> crypto.v1.CoinType
+	13, // 2: invoice.v1.Invoice.createdAt:type_name -> google.protobuf.Timestamp
+	13, // 3: invoice.v1.Invoice.confirmedAt:type_name -> google.protobuf.Timestamp
+	0,  // 4: invoice.v1.Invoice.status:type_name -> invoice.v1.InvoiceStatusType
+	13, // 5: invoice.v1.Invoice.expiresAt:type_name -> google.protobuf.Timestamp
+	2,  // 6: invoice.v1.Invoice.payments:type_name -> invoice.v1.InvoicePayment
+	1,  // 7: invoice.v1.Invoice.outcome:type_name -> invoice.v1.InvoiceOutcomeType
+	14, // 8: invoice.v1.CreateInvoiceRequest.coin:type_name -> crypto.v1.CoinType
+	4,  // 9: invoice.v1.CreateInvoiceRequest.tolerance:type_name -> invoice.v1.AmountTolerance
+	3,  // 10: invoice.v1.GetInvoicesResponse.invoices:type_name -> invoice.v1.Invoice
+	15, // 11: invoice.v1.ListSupportedAssetsResponse.assets:type_name -> crypto.v1.Asset
+	14, // 12: invoice.v1.InvoiceStatusStreamRequest.coins:type_name -> crypto.v1.CoinType
+	0,  // 13: invoice.v1.InvoiceStatusStreamRequest.statuses:type_name -> invoice.v1.InvoiceStatusType
+	3,  // 14: invoice.v1.InvoiceStatusStreamResponse.invoice:type_name -> invoice.v1.Invoice
+	5,  // 15: invoice.v1.InvoiceService.CreateInvoice:input_type -> invoice.v1.CreateInvoiceRequest
+	7,  // 16: invoice.v1.InvoiceService.GetInvoices:input_type -> invoice.v1.GetInvoicesRequest
+	11, // 17: invoice.v1.InvoiceService.InvoiceStatusStream:input_type -> invoice.v1.InvoiceStatusStreamRequest
+	9,  // 18: invoice.v1.InvoiceService.ListSupportedAssets:input_type -> invoice.v1.ListSupportedAssetsRequest
+	6,  // 19: invoice.v1.InvoiceService.CreateInvoice:output_type -> invoice.v1.CreateInvoiceResponse
+	8,  // 20: invoice.v1.InvoiceService.GetInvoices:output_type -> invoice.v1.GetInvoicesResponse
+	12, // 21: invoice.v1.InvoiceService.InvoiceStatusStream:output_type -> invoice.v1.InvoiceStatusStreamResponse
+	10, // 22: invoice.v1.InvoiceService.ListSupportedAssets:output_type -> invoice.v1.ListSupportedAssetsResponse
+	19, // [19:23] is the sub-list for method output_type
+	15, // [15:19] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
 }
 
 func init() { file_invoice_proto_init() }
@@ -674,7 +1297,7 @@ func file_invoice_proto_init() {
 	file_crypto_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_invoice_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*Invoice); i {
+			switch v := v.(*InvoicePayment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -686,7 +1309,7 @@ func file_invoice_proto_init() {
 			}
 		}
 		file_invoice_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*CreateInvoiceRequest); i {
+			switch v := v.(*Invoice); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -698,7 +1321,7 @@ func file_invoice_proto_init() {
 			}
 		}
 		file_invoice_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*CreateInvoiceResponse); i {
+			switch v := v.(*AmountTolerance); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -710,7 +1333,7 @@ func file_invoice_proto_init() {
 			}
 		}
 		file_invoice_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*GetInvoicesRequest); i {
+			switch v := v.(*CreateInvoiceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -722,7 +1345,7 @@ func file_invoice_proto_init() {
 			}
 		}
 		file_invoice_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*GetInvoicesResponse); i {
+			switch v := v.(*CreateInvoiceResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -734,7 +1357,7 @@ func file_invoice_proto_init() {
 			}
 		}
 		file_invoice_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*InvoiceStatusStreamRequest); i {
+			switch v := v.(*GetInvoicesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -746,6 +1369,54 @@ func file_invoice_proto_init() {
 			}
 		}
 		file_invoice_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*GetInvoicesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_invoice_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSupportedAssetsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_invoice_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ListSupportedAssetsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_invoice_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*InvoiceStatusStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_invoice_proto_msgTypes[10].Exporter = func(v any, i int) any {
 			switch v := v.(*InvoiceStatusStreamResponse); i {
 			case 0:
 				return &v.state
@@ -758,13 +1429,22 @@ func file_invoice_proto_init() {
 			}
 		}
 	}
+	file_invoice_proto_msgTypes[0].OneofWrappers = []any{}
+	file_invoice_proto_msgTypes[1].OneofWrappers = []any{}
+	file_invoice_proto_msgTypes[2].OneofWrappers = []any{
+		(*AmountTolerance_AbsoluteAtomic)(nil),
+		(*AmountTolerance_Percent)(nil),
+	}
+	file_invoice_proto_msgTypes[3].OneofWrappers = []any{}
+	file_invoice_proto_msgTypes[4].OneofWrappers = []any{}
+	file_invoice_proto_msgTypes[9].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_invoice_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   7,
+			NumEnums:      2,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,7 @@ const (
 	InvoiceService_CreateInvoice_FullMethodName       = "/invoice.v1.InvoiceService/CreateInvoice"
 	InvoiceService_GetInvoices_FullMethodName         = "/invoice.v1.InvoiceService/GetInvoices"
 	InvoiceService_InvoiceStatusStream_FullMethodName = "/invoice.v1.InvoiceService/InvoiceStatusStream"
+	InvoiceService_ListSupportedAssets_FullMethodName = "/invoice.v1.InvoiceService/ListSupportedAssets"
 )
 
 // InvoiceServiceClient is the client API for InvoiceService service.
@@ -30,7 +31,9 @@ const (
 type InvoiceServiceClient interface {
 	CreateInvoice(ctx context.Context, in *CreateInvoiceRequest, opts ...grpc.CallOption) (*CreateInvoiceResponse, error)
 	GetInvoices(ctx context.Context, in *GetInvoicesRequest, opts ...grpc.CallOption) (*GetInvoicesResponse, error)
+	// Over HTTP it's served as Server-Sent Events on GET /v1/invoices/events, see README
 	InvoiceStatusStream(ctx context.Context, in *InvoiceStatusStreamRequest, opts ...grpc.CallOption) (InvoiceService_InvoiceStatusStreamClient, error)
+	ListSupportedAssets(ctx context.Context, in *ListSupportedAssetsRequest, opts ...grpc.CallOption) (*ListSupportedAssetsResponse, error)
 }
 
 type invoiceServiceClient struct {
@@ -94,13 +97,25 @@ func (x *invoiceServiceInvoiceStatusStreamClient) Recv() (*InvoiceStatusStreamRe
 	return m, nil
 }
 
+func (c *invoiceServiceClient) ListSupportedAssets(ctx context.Context, in *ListSupportedAssetsRequest, opts ...grpc.CallOption) (*ListSupportedAssetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSupportedAssetsResponse)
+	err := c.cc.Invoke(ctx, InvoiceService_ListSupportedAssets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InvoiceServiceServer is the server API for InvoiceService service.
 // All implementations must embed UnimplementedInvoiceServiceServer
 // for forward compatibility
 type InvoiceServiceServer interface {
 	CreateInvoice(context.Context, *CreateInvoiceRequest) (*CreateInvoiceResponse, error)
 	GetInvoices(context.Context, *GetInvoicesRequest) (*GetInvoicesResponse, error)
+	// Over HTTP it's served as Server-Sent Events on GET /v1/invoices/events, see README
 	InvoiceStatusStream(*InvoiceStatusStreamRequest, InvoiceService_InvoiceStatusStreamServer) error
+	ListSupportedAssets(context.Context, *ListSupportedAssetsRequest) (*ListSupportedAssetsResponse, error)
 	mustEmbedUnimplementedInvoiceServiceServer()
 }
 
@@ -117,6 +132,9 @@ func (UnimplementedInvoiceServiceServer) GetInvoices(context.Context, *GetInvoic
 func (UnimplementedInvoiceServiceServer) InvoiceStatusStream(*InvoiceStatusStreamRequest, InvoiceService_InvoiceStatusStreamServer) error {
 	return status.Errorf(codes.Unimplemented, "method InvoiceStatusStream not implemented")
 }
+func (UnimplementedInvoiceServiceServer) ListSupportedAssets(context.Context, *ListSupportedAssetsRequest) (*ListSupportedAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSupportedAssets not implemented")
+}
 func (UnimplementedInvoiceServiceServer) mustEmbedUnimplementedInvoiceServiceServer() {}
 
 // UnsafeInvoiceServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -187,6 +205,24 @@ func (x *invoiceServiceInvoiceStatusStreamServer) Send(m *InvoiceStatusStreamRes
 	return x.ServerStream.SendMsg(m)
 }
 
+func _InvoiceService_ListSupportedAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSupportedAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ListSupportedAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ListSupportedAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ListSupportedAssets(ctx, req.(*ListSupportedAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InvoiceService_ServiceDesc is the grpc.ServiceDesc for InvoiceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -202,6 +238,10 @@ var InvoiceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetInvoices",
 			Handler:    _InvoiceService_GetInvoices_Handler,
 		},
+		{
+			MethodName: "ListSupportedAssets",
+			Handler:    _InvoiceService_ListSupportedAssets_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
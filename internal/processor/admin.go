@@ -0,0 +1,138 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/chekist32/goipay/internal/webhook"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (p *PaymentProcessor) coinProcessorOf(invoice *db.Invoice) (CoinProcessor, error) {
+	cp, ok := p.processors[invoice.Coin]
+	if !ok {
+		return nil, UnimplementedCoinErr
+	}
+
+	return cp, nil
+}
+
+// ExpireInvoice expires the pending invoice ahead of its timeout. The late payments are still watched for during the grace period,
+// so only the span of ctx is carried over like in HandleNewInvoice.
+func (p *PaymentProcessor) ExpireInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error) {
+	cp, err := p.coinProcessorOf(&invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return cp.ExpireInvoice(trace.ContextWithSpanContext(p.ctx, trace.SpanContextFromContext(ctx)), invoice)
+}
+
+// RecheckInvoice starts tracking the pending invoice again if it isn't tracked and verifies its known payments right away, see CoinProcessor.
+func (p *PaymentProcessor) RecheckInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error) {
+	cp, err := p.coinProcessorOf(&invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return cp.RecheckInvoice(trace.ContextWithSpanContext(p.ctx, trace.SpanContextFromContext(ctx)), invoice)
+}
+
+// LastSyncedBlockHeights reports the next block height to be synced by every enabled coin.
+func (p *PaymentProcessor) LastSyncedBlockHeights() map[db.CoinType]uint64 {
+	res := make(map[db.CoinType]uint64, len(p.coins))
+	for _, coin := range p.coins {
+		res[coin] = p.processors[coin].LastSyncedBlockHeight()
+	}
+
+	return res
+}
+
+// ReleaseStuckAddresses see the package level ReleaseStuckAddresses.
+func (p *PaymentProcessor) ReleaseStuckAddresses(ctx context.Context, coin db.NullCoinType, address pgtype.Text) ([]db.CryptoAddress, error) {
+	return ReleaseStuckAddresses(ctx, p.dbConnPool, coin, address, p.latePaymentGracePeriod, p.log)
+}
+
+// ReleaseStuckAddresses frees the occupied addresses, optionally filtered by the coin and the address,
+// which aren't used by any pending invoice or any expired one still within the late payment grace period.
+func ReleaseStuckAddresses(ctx context.Context, dbConnPool *pgxpool.Pool, coin db.NullCoinType, address pgtype.Text, gracePeriod time.Duration, log *zerolog.Logger) ([]db.CryptoAddress, error) {
+	var expiredSince pgtype.Timestamptz
+	if err := expiredSince.Scan(time.Now().UTC().Add(-gracePeriod)); err != nil {
+		log.Err(err).Str("fieldName", "expiredSince").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+		return nil, err
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, dbConnPool)
+	if err != nil {
+		log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	addrs, err := q.ReleaseStuckCryptoAddresses(ctx, db.ReleaseStuckCryptoAddressesParams{Coin: coin, Address: address, ExpiredSince: expiredSince})
+	if err != nil {
+		tx.Rollback(ctx)
+		log.Err(err).Str("queryName", "ReleaseStuckCryptoAddresses").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return addrs, nil
+}
+
+// ExpireStoredInvoice expires the pending invoice in the database only, it's meant for the server being stopped.
+// The address is released right away unless the late payments are watched for, then it's done once the server resumes the invoice.
+func ExpireStoredInvoice(ctx context.Context, dbConnPool *pgxpool.Pool, id pgtype.UUID, gracePeriod time.Duration, log *zerolog.Logger) (*db.Invoice, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, dbConnPool)
+	if err != nil {
+		log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	invoice, err := q.FindInvoiceByIdAndLock(ctx, id)
+	if err != nil {
+		tx.Rollback(ctx)
+		log.Err(err).Str("queryName", "FindInvoiceByIdAndLock").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+	if !isPending(&invoice) {
+		tx.Rollback(ctx)
+		return nil, InvoiceNotPendingErr
+	}
+
+	expiredInvoice, err := q.ExpireInvoiceById(ctx, id)
+	if err != nil {
+		tx.Rollback(ctx)
+		log.Err(err).Str("queryName", "ExpireInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	if err := webhook.Enqueue(ctx, q, webhook.INVOICE_EXPIRED_EVENT, &expiredInvoice); err != nil {
+		tx.Rollback(ctx)
+		log.Err(err).Msg(failed_enqueue_webhook_event_msg)
+		return nil, err
+	}
+
+	if gracePeriod == 0 && !expiredInvoice.Memo.Valid {
+		if _, err := q.UpdateIsOccupiedByCryptoAddress(ctx, db.UpdateIsOccupiedByCryptoAddressParams{IsOccupied: false, Address: expiredInvoice.CryptoAddress}); err != nil {
+			tx.Rollback(ctx)
+			log.Err(err).Str("queryName", "UpdateIsOccupiedByCryptoAddress").Msg(util.DefaultFailedSqlQueryMsg)
+			return nil, err
+		}
+	}
+
+	if _, err := createInvoiceEvent(ctx, q, &expiredInvoice); err != nil {
+		tx.Rollback(ctx)
+		log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return &expiredInvoice, nil
+}
@@ -0,0 +1,472 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/eth"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/dto"
+	"github.com/chekist32/goipay/internal/listener"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+const (
+	MAX_ERC20_DECIMALS uint32 = 36
+)
+
+var (
+	invalidEthTokenConfigErr error = errors.New("invalid ETH token config")
+)
+
+type ethCryptoDataStore struct{}
+
+func (s ethCryptoDataStore) findCryptoDataId(cd *db.CryptoDatum) pgtype.UUID {
+	return cd.EthID
+}
+
+func (s ethCryptoDataStore) findKeysAndLock(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error) {
+	return q.FindKeysAndLockETHCryptoDataById(ctx, id)
+}
+
+func (s ethCryptoDataStore) findIndicesAndLock(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error) {
+	indices, err := q.FindIndicesAndLockETHCryptoDataById(ctx, id)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return indices.LastMajorIndex, indices.LastMinorIndex, nil
+}
+
+func (s ethCryptoDataStore) updateIndices(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) error {
+	_, err := q.UpdateIndicesETHCryptoDataById(ctx, db.UpdateIndicesETHCryptoDataByIdParams{ID: id, LastMajorIndex: major, LastMinorIndex: minor})
+	return err
+}
+
+func newEthTokens(c []dto.EthTokenConfig) (map[string]dto.EthTokenConfig, error) {
+	tokens := make(map[string]dto.EthTokenConfig, len(c))
+	symbols := make(map[string]bool, len(c))
+
+	for i := 0; i < len(c); i++ {
+		contract, err := util.ToEthChecksumAddress(c[i].Contract)
+		if err != nil {
+			return nil, fmt.Errorf("token %v: %w", c[i].Symbol, err)
+		}
+
+		symbol := strings.ToUpper(c[i].Symbol)
+		if symbol == "" || symbol == string(db.CoinTypeETH) || symbols[symbol] || c[i].Decimals > MAX_ERC20_DECIMALS {
+			return nil, fmt.Errorf("token %v: %w", c[i].Symbol, invalidEthTokenConfigErr)
+		}
+		if _, ok := tokens[contract]; ok {
+			return nil, fmt.Errorf("token %v: %w", c[i].Symbol, invalidEthTokenConfigErr)
+		}
+		symbols[symbol] = true
+
+		tokens[contract] = dto.EthTokenConfig{Symbol: c[i].Symbol, Contract: contract, Decimals: c[i].Decimals}
+	}
+
+	return tokens, nil
+}
+
+// ethProcessor handles native ETH and ERC-20 token invoices. Amounts are compared
+// in atomic units (wei for ETH).
+type ethProcessor struct {
+	baseCryptoProcessor
+
+	daemon   eth.IDaemonRpcClient
+	daemonEx *listener.EthDaemonRpcClientExecutor
+	chainId  uint64
+
+	// ERC-20 tokens keyed by their checksummed contract address
+	tokens map[string]dto.EthTokenConfig
+}
+
+// verifyEthPayment records the payment made by the tx and checks the confirmations of the invoice.
+// The output index is the log index for token transfers and 0 for ETH transfers.
+func (p *ethProcessor) verifyEthPayment(ctx context.Context, value pendingInvoice, txHash string, outputIndex int32, actualAmount *big.Int) {
+	receipt, err := p.daemon.GetTransactionReceipt(txHash)
+	if err != nil {
+		p.log.Err(err).Str("method", "eth_getTransactionReceipt").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_getTransactionReceipt").Inc()
+		return
+	}
+	if receipt == nil || uint64(receipt.Status) != eth.TX_RECEIPT_STATUS_SUCCESSFUL {
+		return
+	}
+
+	payment := invoicePayment{
+		txId:        txHash,
+		outputIndex: outputIndex,
+		amount:      actualAmount,
+		blockHeight: pgtype.Int8{Int64: int64(receipt.BlockNumber), Valid: true},
+	}
+	if recorded, err := p.recordPayment(ctx, value, payment); err != nil || !recorded {
+		return
+	}
+
+	p.confirmInvoiceHelper(ctx, value)
+}
+
+func (p *ethProcessor) verifyEthTx(ctx context.Context, ethTx eth.Tx) {
+	// Contract creation
+	if ethTx.To == nil {
+		return
+	}
+
+	to, err := util.ToEthChecksumAddress(*ethTx.To)
+	if err != nil {
+		return
+	}
+
+	value, ok := p.pendingInvoices.Load(to)
+	if !ok {
+		return
+	}
+
+	invoice := value.invoice.Load()
+	// The invoice has been already paid or it expects a token
+	if !acceptsPayments(invoice) || invoice.TokenContract.Valid || ethTx.Value.Int.Sign() <= 0 {
+		return
+	}
+
+	p.verifyEthPayment(ctx, value, ethTx.Hash, 0, &ethTx.Value.Int)
+}
+
+func (p *ethProcessor) verifyErc20TransferLog(ctx context.Context, transferLog eth.Log) {
+	if transferLog.Removed {
+		return
+	}
+
+	contract, err := util.ToEthChecksumAddress(transferLog.Address)
+	if err != nil {
+		return
+	}
+	if _, ok := p.tokens[contract]; !ok {
+		return
+	}
+
+	to, transferred, err := util.DecodeErc20Transfer(transferLog.Topics, transferLog.Data)
+	if err != nil {
+		return
+	}
+
+	value, ok := p.pendingInvoices.Load(to)
+	if !ok {
+		return
+	}
+
+	invoice := value.invoice.Load()
+	// The invoice has been already paid or it expects another asset
+	if !acceptsPayments(invoice) || invoice.TokenContract.String != contract || transferred.Sign() <= 0 {
+		return
+	}
+
+	p.verifyEthPayment(ctx, value, transferLog.TransactionHash, int32(transferLog.LogIndex), transferred)
+}
+
+func (p *ethProcessor) verifyErc20Transfers(ctx context.Context, blockHash string) {
+	contracts := make([]string, 0, len(p.tokens))
+	for contract := range p.tokens {
+		contracts = append(contracts, contract)
+	}
+
+	logs, err := p.daemon.GetLogs(&eth.LogFilter{BlockHash: blockHash, Address: contracts, Topics: [][]string{{eth.ERC20_TRANSFER_EVENT_TOPIC}}})
+	if err != nil {
+		p.log.Err(err).Str("method", "eth_getLogs").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_getLogs").Inc()
+		return
+	}
+
+	for i := 0; i < len(logs); i++ {
+		p.tasks.Go(func() { p.verifyErc20TransferLog(ctx, logs[i]) })
+	}
+}
+
+func (p *ethProcessor) confirmInvoiceHelper(ctx context.Context, value pendingInvoice) {
+	invoice := value.invoice.Load()
+	if invoice.Status != db.InvoiceStatusTypePENDINGMEMPOOL {
+		return
+	}
+
+	txIds, err := p.findPaymentTxIds(ctx, invoice)
+	if err != nil {
+		return
+	}
+
+	height, err := p.daemon.BlockNumber()
+	if err != nil {
+		p.log.Err(err).Str("method", "eth_blockNumber").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_blockNumber").Inc()
+		return
+	}
+
+	confirmations := uint64(math.MaxUint64)
+	missedTxIds := make([]string, 0)
+	inMempool := false
+	for i := 0; i < len(txIds); i++ {
+		receipt, err := p.daemon.GetTransactionReceipt(txIds[i])
+		if err != nil {
+			p.log.Err(err).Str("method", "eth_getTransactionReceipt").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+			metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_getTransactionReceipt").Inc()
+			return
+		}
+
+		if receipt == nil {
+			ethTx, err := p.daemon.GetTransactionByHash(txIds[i])
+			if err != nil {
+				p.log.Err(err).Str("method", "eth_getTransactionByHash").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+				metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_getTransactionByHash").Inc()
+				return
+			}
+			// The block was reorged out and the tx didn't make it back to the pool
+			if ethTx == nil {
+				p.log.Info().Msgf("Tx %v was dropped by blockchain", txIds[i])
+				missedTxIds = append(missedTxIds, txIds[i])
+				continue
+			}
+			inMempool = true
+			continue
+		}
+		if uint64(receipt.Status) != eth.TX_RECEIPT_STATUS_SUCCESSFUL {
+			p.log.Info().Msgf("Tx %v was reverted", txIds[i])
+			missedTxIds = append(missedTxIds, txIds[i])
+			continue
+		}
+
+		// The node may lag behind the block of the receipt
+		if height < uint64(receipt.BlockNumber) {
+			confirmations = 0
+			continue
+		}
+		confirmations = min(confirmations, height-uint64(receipt.BlockNumber)+1)
+	}
+
+	if len(missedTxIds) > 0 {
+		updatedInvoice, paid, err := p.dropMissingPayments(ctx, invoice, missedTxIds)
+		if err != nil {
+			return
+		}
+		if !paid {
+			p.expireInvoice(ctx, updatedInvoice)
+			return
+		}
+	}
+	if inMempool {
+		return
+	}
+
+	if !p.trackConfirmations(ctx, value, confirmations) {
+		return
+	}
+
+	p.confirmInvoice(ctx, value)
+}
+
+func (p *ethProcessor) verifyEthTxOnNewBlock(ctx context.Context) {
+	p.pendingInvoices.Range(func(key string, value pendingInvoice) bool {
+		p.tasks.Go(func() { p.confirmInvoiceHelper(ctx, value) })
+		return true
+	})
+}
+
+func (p *ethProcessor) persistCryptoCacheHelper(ctx context.Context) {
+	p.baseCryptoProcessor.persistCryptoCacheHelper(ctx, db.CoinTypeETH, p.daemonEx.LastSyncedBlockHeight())
+}
+
+func (p *ethProcessor) Start(ctx context.Context) error {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return err
+	}
+
+	cache, err := q.FindCryptoCacheByCoin(ctx, db.CoinTypeETH)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindCryptoCacheByCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		return err
+	}
+
+	height, err := p.daemon.BlockNumber()
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("method", "eth_blockNumber").Msg(util.DefaultFailedFetchingETHDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeETH), "eth_blockNumber").Inc()
+		return err
+	}
+
+	if cache.LastSyncedBlockHeight.Valid {
+		height = uint64(cache.LastSyncedBlockHeight.Int64)
+	}
+
+	p.watchers.Go(func() {
+		p.watchers.Go(func() {
+			blockCn := p.daemonEx.NewBlockChan()
+
+			for {
+				select {
+				case res := <-blockCn:
+					p.tasks.Go(func() {
+						for i := 0; i < len(res.Transactions); i++ {
+							p.tasks.Go(func() { p.verifyEthTx(ctx, res.Transactions[i]) })
+						}
+					})
+
+					if len(p.tokens) > 0 {
+						p.tasks.Go(func() { p.verifyErc20Transfers(ctx, res.Hash) })
+					}
+
+					p.tasks.Go(func() { p.verifyEthTxOnNewBlock(ctx) })
+
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+
+		p.persistCryptoCacheHelper(ctx)
+		for {
+			select {
+			case <-time.After(persist_cache_timeout):
+				p.tasks.Go(func() { p.persistCryptoCacheHelper(ctx) })
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	tx.Commit(ctx)
+
+	p.daemonEx.Start(height)
+	return nil
+}
+
+func (p *ethProcessor) deriveAddress(masterPubKey string, major uint32, minor uint32) (string, error) {
+	key, err := util.NewEthMasterPubKey(masterPubKey)
+	if err != nil {
+		return "", err
+	}
+
+	return util.GenerateEthAddress(key, major, minor)
+}
+
+func (p *ethProcessor) generateAddress(ctx context.Context, q *db.Queries, userId pgtype.UUID) (string, error) {
+	return p.generateHdAddress(ctx, q, userId, ethCryptoDataStore{}, p.deriveAddress)
+}
+
+// findToken looks up a configured token by its symbol (case-insensitive) or contract address.
+func (p *ethProcessor) findToken(token string) (*dto.EthTokenConfig, error) {
+	if contract, err := util.ToEthChecksumAddress(token); err == nil {
+		if t, ok := p.tokens[contract]; ok {
+			return &t, nil
+		}
+		return nil, UnsupportedTokenErr
+	}
+
+	for _, t := range p.tokens {
+		if strings.EqualFold(t.Symbol, token) {
+			return &t, nil
+		}
+	}
+
+	return nil, UnsupportedTokenErr
+}
+
+func (p *ethProcessor) Stop() {
+	p.daemonEx.Stop()
+}
+
+func (p *ethProcessor) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx, db.CoinTypeETH, p.daemonEx.LastSyncedBlockHeight())
+}
+
+func (p *ethProcessor) Health() error {
+	_, err := p.daemon.BlockNumber()
+	return err
+}
+
+func (p *ethProcessor) SyncLag() uint64 {
+	return p.daemonEx.SyncLag()
+}
+
+func (p *ethProcessor) LastSyncedBlockHeight() uint64 {
+	return p.daemonEx.LastSyncedBlockHeight()
+}
+
+func (p *ethProcessor) RecheckInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error) {
+	return p.recheckInvoice(ctx, invoice, p.confirmInvoiceHelper)
+}
+
+func (p *ethProcessor) Assets() []dto.Asset {
+	tokens := make([]dto.Asset, 0, len(p.tokens))
+	for _, t := range p.tokens {
+		tokens = append(tokens, dto.Asset{Coin: db.CoinTypeETH, Symbol: t.Symbol, Contract: t.Contract, Decimals: t.Decimals})
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Symbol < tokens[j].Symbol })
+
+	return append([]dto.Asset{{Coin: db.CoinTypeETH, Symbol: string(db.CoinTypeETH), Decimals: uint32(util.ETH_DECIMALS)}}, tokens...)
+}
+
+func (p *ethProcessor) HandleNewInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
+	decimals := util.ETH_DECIMALS
+	if req.Token != "" {
+		token, err := p.findToken(req.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenReq := *req
+		tokenReq.Token = token.Contract
+		req = &tokenReq
+		decimals = int(token.Decimals)
+	}
+
+	invoice, err := p.createInvoice(ctx, req, decimals, p.generateAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	p.handleInvoice(ctx, *invoice)
+
+	return invoice, nil
+}
+
+func newEthProcessor(dbConnPool *pgxpool.Pool, invoiceCn chan<- dto.InvoiceEvent, c *dto.DaemonsConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (CoinProcessor, error) {
+	u, err := url.Parse(c.Eth.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	d := eth.NewDaemonRpcClient(eth.NewRpcConnection(u, c.Eth.User, c.Eth.Pass))
+
+	chainId, err := d.ChainId()
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Connected to the ETH daemon (chainId: %v)", chainId)
+
+	tokens, err := newEthTokens(c.EthTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethProcessor{
+			baseCryptoProcessor: newBaseCryptoProcessor(dbConnPool, invoiceCn, ic, log),
+			daemon:              d,
+			daemonEx:            listener.NewEthDaemonRpcClientExecutor(d, log),
+			chainId:             chainId,
+			tokens:              tokens,
+		},
+		nil
+}
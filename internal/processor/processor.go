@@ -2,35 +2,145 @@ package processor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/chekist32/goipay/internal/db"
 	"github.com/chekist32/goipay/internal/dto"
+	"github.com/chekist32/goipay/internal/envelope"
+	"github.com/chekist32/goipay/internal/metrics"
 	"github.com/chekist32/goipay/internal/util"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	persist_cache_timeout time.Duration = 1 * time.Minute
+	// How long the goroutines have to exit once the ctx is cancelled on shutdown
+	stop_routines_timeout time.Duration = 5 * time.Second
+
+	invoice_events_replay_batch_size int32 = 100
+
+	failed_enqueue_webhook_event_msg string = "An error occurred while enqueueing the webhook event."
 )
 
 var (
-	unimplementedError error = errors.New("The coin is unimplemented")
+	// UnimplementedCoinErr is returned for coins which aren't enabled in the config.
+	UnimplementedCoinErr error = errors.New("the coin is not enabled")
+	// UnsupportedTokenErr is returned for tokens which aren't configured or for coins without token support.
+	UnsupportedTokenErr error = errors.New("unsupported token")
+	// InvalidAmountErr is returned for amounts with more decimals than the asset has.
+	InvalidAmountErr error = errors.New("invalid amount")
+	// InvalidToleranceErr is returned for tolerances which are malformed or exceed the required amount.
+	InvalidToleranceErr error = errors.New("invalid tolerance")
+	// MissingMasterKeyErr is returned for encrypted XMR private view keys if no master key is configured.
+	MissingMasterKeyErr error = errors.New("the XMR private view key is encrypted but no master key is configured")
+	// InvoiceNotPendingErr is returned for invoices which don't wait for a payment anymore.
+	InvoiceNotPendingErr error = errors.New("the invoice is not pending")
+	// InvoiceAddressInUseErr is returned for invoices which can't be tracked as their address is used by another pending invoice.
+	InvoiceAddressInUseErr error = errors.New("the address of the invoice is used by another pending invoice")
+
+	invalidCoinTypeErr error = errors.New("invalid coin type")
 )
 
+// CoinProcessor processes the invoices of a single coin.
+type CoinProcessor interface {
+	// HandleNewInvoice creates an invoice and starts tracking it.
+	HandleNewInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error)
+	// ResumeInvoice starts tracking an invoice persisted before a restart.
+	ResumeInvoice(ctx context.Context, invoice db.Invoice)
+	// ExpireInvoice expires the pending invoice ahead of its timeout.
+	ExpireInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error)
+	// RecheckInvoice starts tracking the pending invoice again if it isn't tracked and verifies its known payments right away.
+	RecheckInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error)
+	// Start syncs with the daemon and starts processing invoices until ctx is done.
+	Start(ctx context.Context) error
+	// Stop stops syncing with the daemon.
+	Stop()
+	// Shutdown waits for the in-flight invoice verification until ctx is done and persists the last synced block height.
+	// It's called once the daemon sync is stopped.
+	Shutdown(ctx context.Context) error
+	// Wait waits until ctx is done for the goroutines which exit once the ctx passed to Start is done.
+	Wait(ctx context.Context) error
+	// Health reports whether the daemon is reachable.
+	Health() error
+	// Assets lists the assets accepted by the processor.
+	Assets() []dto.Asset
+	// PendingInvoicesCount returns the number of the invoices being watched for payments.
+	PendingInvoicesCount() int
+	// SyncLag returns the number of the daemon blocks not synced yet.
+	SyncLag() uint64
+	// LastSyncedBlockHeight returns the next block height to be synced.
+	LastSyncedBlockHeight() uint64
+}
+
+type coinProcessorFactory func(dbConnPool *pgxpool.Pool, invoiceCn chan<- dto.InvoiceEvent, c *dto.DaemonsConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (CoinProcessor, error)
+
+type coinProcessorRegistration struct {
+	coin    db.CoinType
+	daemon  func(c *dto.DaemonsConfig) *dto.DaemonConfig
+	factory coinProcessorFactory
+}
+
+// coinProcessorRegistry lists every supported coin. A coin is enabled when the url of its daemon is set.
+var coinProcessorRegistry = []coinProcessorRegistration{
+	{coin: db.CoinTypeXMR, daemon: func(c *dto.DaemonsConfig) *dto.DaemonConfig { return &c.Xmr }, factory: newXmrProcessor},
+	{coin: db.CoinTypeBTC, daemon: func(c *dto.DaemonsConfig) *dto.DaemonConfig { return &c.Btc }, factory: newUtxoProcessorFactory(db.CoinTypeBTC)},
+	{coin: db.CoinTypeLTC, daemon: func(c *dto.DaemonsConfig) *dto.DaemonConfig { return &c.Ltc }, factory: newUtxoProcessorFactory(db.CoinTypeLTC)},
+	{coin: db.CoinTypeETH, daemon: func(c *dto.DaemonsConfig) *dto.DaemonConfig { return &c.Eth }, factory: newEthProcessor},
+	{coin: db.CoinTypeTON, daemon: func(c *dto.DaemonsConfig) *dto.DaemonConfig { return &c.Ton }, factory: newTonProcessor},
+}
+
+type invoiceSubscription struct {
+	cn     chan dto.InvoiceEvent
+	filter *dto.InvoiceFilter
+}
+
+func containsUUID(ids []pgtype.UUID, id pgtype.UUID) bool {
+	return slices.ContainsFunc(ids, func(v pgtype.UUID) bool { return v.Bytes == id.Bytes })
+}
+
+// matchesInvoiceFilter reports whether the subscriber with the filter should be notified about the invoice.
+func matchesInvoiceFilter(filter *dto.InvoiceFilter, invoice *db.Invoice) bool {
+	if filter == nil {
+		return true
+	}
+
+	return (len(filter.UserIds) == 0 || containsUUID(filter.UserIds, invoice.UserID)) &&
+		(len(filter.InvoiceIds) == 0 || containsUUID(filter.InvoiceIds, invoice.ID)) &&
+		(len(filter.Coins) == 0 || slices.Contains(filter.Coins, invoice.Coin)) &&
+		(len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, invoice.Status))
+}
+
 type PaymentProcessor struct {
 	dbConnPool *pgxpool.Pool
 
-	ctx context.Context
-	log *zerolog.Logger
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	log       *zerolog.Logger
 
-	invoiceCn      chan db.Invoice
-	newInvoicesCns *util.SyncMapTypeSafe[string, chan db.Invoice]
+	invoiceCn      chan dto.InvoiceEvent
+	newInvoicesCns *util.SyncMapTypeSafe[string, invoiceSubscription]
+	// Closed by CloseSubscriptions
+	subscriptionsClosed chan struct{}
+	closeSubscriptions  sync.Once
+	// The invoice event loop and the notifications of the subscribers
+	routines util.Routines
 
-	xmr *xmrProcessor
+	latePaymentGracePeriod time.Duration
+
+	xmrKeyring *envelope.Keyring
+
+	// Enabled coins in the registry order
+	coins      []db.CoinType
+	processors map[db.CoinType]CoinProcessor
 }
 
 func (p *PaymentProcessor) loadPersistedPendingInvoices() error {
@@ -47,43 +157,101 @@ func (p *PaymentProcessor) loadPersistedPendingInvoices() error {
 		return err
 	}
 
+	// Expired invoices still within the late payment grace period
+	if p.latePaymentGracePeriod > 0 {
+		var since pgtype.Timestamptz
+		if err := since.Scan(time.Now().UTC().Add(-p.latePaymentGracePeriod)); err != nil {
+			tx.Rollback(p.ctx)
+			p.log.Err(err).Str("fieldName", "since").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+			return err
+		}
+
+		expiredInvoices, err := q.FindAllInvoicesExpiredSince(p.ctx, since)
+		if err != nil {
+			tx.Rollback(p.ctx)
+			p.log.Err(err).Str("queryName", "FindAllInvoicesExpiredSince").Msg(util.DefaultFailedSqlQueryMsg)
+			return err
+		}
+		invoices = append(invoices, expiredInvoices...)
+	}
+
 	tx.Commit(p.ctx)
 
 	for i := 0; i < len(invoices); i++ {
-		switch invoices[i].Coin {
-		case db.CoinTypeXMR:
-			go p.xmr.handleInvoice(p.ctx, invoices[i])
-		// TODO: Add impelmentation for BTC
-		case db.CoinTypeBTC:
-		// TODO: Add impelmentation for LTC
-		case db.CoinTypeLTC:
-		// TODO: Add impelmentation for ETH
-		case db.CoinTypeETH:
-		// TODO: Add impelmentation for TON
-		case db.CoinTypeTON:
+		cp, ok := p.processors[invoices[i].Coin]
+		if !ok {
+			p.log.Warn().Msgf("Invoice %v can't be resumed as %v is not enabled", util.PgUUIDToString(invoices[i].ID), invoices[i].Coin)
+			continue
 		}
+
+		go cp.ResumeInvoice(p.ctx, invoices[i])
 	}
 
 	return nil
 }
 
+// ReplayInvoiceEvents returns the next batch of persisted events after fromSequence matching the filter,
+// along with the sequence to continue from. The sequence equals fromSequence once there are no more events.
+// The events are committed in the order of their sequences (see createInvoiceEvent), so no lower sequence shows up after a greater one has been returned.
+func (p *PaymentProcessor) ReplayInvoiceEvents(ctx context.Context, fromSequence int64, filter *dto.InvoiceFilter) ([]dto.InvoiceEvent, int64, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, fromSequence, err
+	}
+
+	dbEvents, err := q.FindAllInvoiceEventsFromSequence(ctx, db.FindAllInvoiceEventsFromSequenceParams{Sequence: fromSequence, Limit: invoice_events_replay_batch_size})
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindAllInvoiceEventsFromSequence").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, fromSequence, err
+	}
+
+	tx.Commit(ctx)
+
+	events := make([]dto.InvoiceEvent, 0, len(dbEvents))
+	for i := 0; i < len(dbEvents); i++ {
+		fromSequence = dbEvents[i].Sequence
+
+		var invoice db.Invoice
+		if err := json.Unmarshal(dbEvents[i].Invoice, &invoice); err != nil {
+			p.log.Err(err).Int64("sequence", dbEvents[i].Sequence).Msg("An error occurred while decoding the invoice event.")
+			return nil, fromSequence, err
+		}
+
+		if !matchesInvoiceFilter(filter, &invoice) {
+			continue
+		}
+		events = append(events, dto.InvoiceEvent{Sequence: dbEvents[i].Sequence, Invoice: invoice})
+	}
+
+	return events, fromSequence, nil
+}
+
 func (p *PaymentProcessor) load() error {
-	go func() {
+	p.routines.Go(func() {
 		for {
 			select {
-			case tx := <-p.invoiceCn:
-				p.newInvoicesCns.Range(func(key string, cn chan db.Invoice) bool {
-					go func() {
+			case event := <-p.invoiceCn:
+				tx := event.Invoice
+
+				p.newInvoicesCns.Range(func(key string, sub invoiceSubscription) bool {
+					if !matchesInvoiceFilter(sub.filter, &tx) {
+						return true
+					}
+
+					p.routines.Go(func() {
 						select {
-						case cn <- tx:
+						case sub.cn <- event:
 							return
 						case <-time.After(util.SEND_TIMEOUT):
 							p.newInvoicesCns.Delete(key)
+							metrics.SubscriberDropsTotal.WithLabelValues(string(tx.Coin), metrics.INVOICE_CHANNEL).Inc()
 							return
 						case <-p.ctx.Done():
 							return
 						}
-					}()
+					})
 
 					return true
 				})
@@ -93,63 +261,204 @@ func (p *PaymentProcessor) load() error {
 				return
 			}
 		}
-	}()
+	})
 
 	if err := p.loadPersistedPendingInvoices(); err != nil {
 		return err
 	}
 
-	if err := p.xmr.load(p.ctx); err != nil {
-		return err
+	for _, coin := range p.coins {
+		if err := p.processors[coin].Start(p.ctx); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (p *PaymentProcessor) HandleNewInvoice(req *dto.NewInvoiceRequest) (*db.Invoice, error) {
-	switch req.Coin {
-	case db.CoinTypeXMR:
-		return p.xmr.handleInvoicePbReq(p.ctx, req)
-	// TODO: Add impelmentation for BTC
-	case db.CoinTypeBTC:
-		return nil, unimplementedError
-	// TODO: Add impelmentation for LTC
-	case db.CoinTypeLTC:
-		return nil, unimplementedError
-	// TODO: Add impelmentation for ETH
-	case db.CoinTypeETH:
-		return nil, unimplementedError
-	// TODO: Add impelmentation for TON
-	case db.CoinTypeTON:
-		return nil, unimplementedError
+// HandleNewInvoice creates the invoice on the processor of its coin. The invoice outlives the call,
+// so only the span of ctx is carried over to link the background verification of the invoice with it.
+func (p *PaymentProcessor) HandleNewInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
+	if req.Token != "" && req.Coin != db.CoinTypeETH {
+		return nil, UnsupportedTokenErr
+	}
+
+	cp, ok := p.processors[req.Coin]
+	if !ok {
+		for _, r := range coinProcessorRegistry {
+			if r.coin == req.Coin {
+				return nil, UnimplementedCoinErr
+			}
+		}
+		return nil, invalidCoinTypeErr
+	}
+
+	return cp.HandleNewInvoice(trace.ContextWithSpanContext(p.ctx, trace.SpanContextFromContext(ctx)), req)
+}
+
+// SupportedAssets lists the assets of the enabled coins along with the configured tokens.
+func (p *PaymentProcessor) SupportedAssets() []dto.Asset {
+	assets := make([]dto.Asset, 0, len(p.coins))
+	for _, coin := range p.coins {
+		assets = append(assets, p.processors[coin].Assets()...)
 	}
 
-	return nil, errors.New("invalid coin type")
+	return assets
 }
 
-func (p *PaymentProcessor) NewInvoicesChan() <-chan db.Invoice {
-	cn := make(chan db.Invoice)
-	p.newInvoicesCns.Store(uuid.NewString(), cn)
+// Health reports the daemon health of every enabled coin.
+func (p *PaymentProcessor) Health() map[db.CoinType]error {
+	res := make(map[db.CoinType]error, len(p.coins))
+	for _, coin := range p.coins {
+		res[coin] = p.processors[coin].Health()
+	}
+
+	return res
+}
+
+// SyncLag reports the number of the daemon blocks not synced yet by every enabled coin.
+func (p *PaymentProcessor) SyncLag() map[db.CoinType]uint64 {
+	res := make(map[db.CoinType]uint64, len(p.coins))
+	for _, coin := range p.coins {
+		res[coin] = p.processors[coin].SyncLag()
+	}
+
+	return res
+}
+
+// EncryptXmrPrivViewKey encrypts the XMR private view key before storing it, the key is kept in plaintext if no master key is configured.
+func (p *PaymentProcessor) EncryptXmrPrivViewKey(privViewKey string) (string, error) {
+	if p.xmrKeyring == nil {
+		return privViewKey, nil
+	}
+
+	return p.xmrKeyring.Encrypt(privViewKey)
+}
+
+// RevealXmrPrivViewKey returns the decrypted XMR private view key of the user, it's empty if the user has no XMR keys.
+func (p *PaymentProcessor) RevealXmrPrivViewKey(ctx context.Context, userId pgtype.UUID) (string, error) {
+	xp, ok := p.processors[db.CoinTypeXMR].(*xmrProcessor)
+	if !ok {
+		return "", UnimplementedCoinErr
+	}
+
+	return xp.revealPrivViewKey(ctx, userId)
+}
+
+// PendingInvoices returns the number of the invoices being watched for payments by coin.
+func (p *PaymentProcessor) PendingInvoices() map[db.CoinType]int {
+	res := make(map[db.CoinType]int, len(p.coins))
+	for _, coin := range p.coins {
+		res[coin] = p.processors[coin].PendingInvoicesCount()
+	}
+
+	return res
+}
+
+// Stop stops syncing with the daemons of every enabled coin.
+func (p *PaymentProcessor) Stop() {
+	for _, coin := range p.coins {
+		p.processors[coin].Stop()
+	}
+}
+
+// CloseSubscriptions lets the long-lived subscribers, i.e. the invoice streams, know the server is shutting down,
+// see SubscriptionsClosed. Otherwise they'd keep the gRPC server from stopping gracefully.
+func (p *PaymentProcessor) CloseSubscriptions() {
+	p.closeSubscriptions.Do(func() { close(p.subscriptionsClosed) })
+}
+
+// SubscriptionsClosed returns a channel closed by CloseSubscriptions.
+func (p *PaymentProcessor) SubscriptionsClosed() <-chan struct{} {
+	return p.subscriptionsClosed
+}
+
+// Shutdown stops the processing in order: the daemon sync is stopped, the in-flight invoice verification is waited for until ctx is done
+// and the last synced block heights are persisted. Then the rest of the goroutines are stopped, the pool is left open.
+func (p *PaymentProcessor) Shutdown(ctx context.Context) error {
+	p.CloseSubscriptions()
+	p.Stop()
+
+	errs := make([]error, 0)
+	for _, coin := range p.coins {
+		if err := p.processors[coin].Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", coin, err))
+		}
+	}
+
+	p.ctxCancel()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), stop_routines_timeout)
+	defer cancel()
+	for _, coin := range p.coins {
+		if err := p.processors[coin].Wait(stopCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", coin, err))
+		}
+	}
+	if err := p.routines.Wait(stopCtx); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewInvoicesChan returns a channel receiving the updates of the invoices matching the filter, a nil filter matches all of them.
+func (p *PaymentProcessor) NewInvoicesChan(filter *dto.InvoiceFilter) <-chan dto.InvoiceEvent {
+	cn := make(chan dto.InvoiceEvent)
+	p.newInvoicesCns.Store(uuid.NewString(), invoiceSubscription{cn: cn, filter: filter})
 	return cn
 }
 
-func NewPaymentProcessor(ctx context.Context, dbConnPool *pgxpool.Pool, c *dto.DaemonsConfig, log *zerolog.Logger) (*PaymentProcessor, error) {
-	invoiceCn := make(chan db.Invoice)
+// newPaymentProcessor creates the processors of the coins in the registry whose daemon url is set, nothing is started yet.
+func newPaymentProcessor(ctx context.Context, dbConnPool *pgxpool.Pool, registry []coinProcessorRegistration, c *dto.DaemonsConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (*PaymentProcessor, error) {
+	invoiceCn := make(chan dto.InvoiceEvent)
+	ctx, cancel := context.WithCancel(ctx)
 
-	xmr, err := newXmrProcessor(dbConnPool, invoiceCn, c, log)
-	if err != nil {
-		return nil, err
+	coins := make([]db.CoinType, 0, len(registry))
+	processors := make(map[db.CoinType]CoinProcessor, len(registry))
+	for _, r := range registry {
+		if r.daemon(c).Url == "" {
+			log.Info().Msgf("%v is disabled", r.coin)
+			continue
+		}
+
+		cp, err := r.factory(dbConnPool, invoiceCn, c, ic, log)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		coins = append(coins, r.coin)
+		processors[r.coin] = cp
 	}
 
 	pp := &PaymentProcessor{
 		dbConnPool:     dbConnPool,
 		invoiceCn:      invoiceCn,
-		newInvoicesCns: &util.SyncMapTypeSafe[string, chan db.Invoice]{},
-		xmr:            xmr,
+		newInvoicesCns: &util.SyncMapTypeSafe[string, invoiceSubscription]{},
+		coins:          coins,
+		processors:     processors,
 		ctx:            ctx,
+		ctxCancel:      cancel,
 		log:            log,
+
+		subscriptionsClosed: make(chan struct{}),
+
+		latePaymentGracePeriod: ic.LatePaymentGracePeriod,
+		xmrKeyring:             c.XmrKeyring,
 	}
+
+	return pp, nil
+}
+
+func NewPaymentProcessor(ctx context.Context, dbConnPool *pgxpool.Pool, c *dto.DaemonsConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (*PaymentProcessor, error) {
+	pp, err := newPaymentProcessor(ctx, dbConnPool, coinProcessorRegistry, c, ic, log)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := pp.load(); err != nil {
+		pp.ctxCancel()
 		return nil, err
 	}
 
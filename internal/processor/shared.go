@@ -0,0 +1,1032 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/dto"
+	"github.com/chekist32/goipay/internal/listener"
+	"github.com/chekist32/goipay/internal/tracing"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/chekist32/goipay/internal/webhook"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type pendingInvoice struct {
+	invoice           *atomic.Pointer[db.Invoice]
+	cancelTimeoutFunc context.CancelFunc
+	// Set once the invoice has expired, afterwards it's only watched for late payments
+	expired *atomic.Bool
+	// Set if the invoice was CONFIRMED before a chain reorganization orphaned its payment block
+	reorged bool
+	// Span of the call which created the invoice, invalid for the invoices loaded on startup
+	spanContext trace.SpanContext
+}
+
+// startInvoiceSpan starts a span of the background processing of the invoice, tagged with its id
+// and linked to the span of the call which created it.
+func startInvoiceSpan(ctx context.Context, name string, value pendingInvoice) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithAttributes(tracing.INVOICE_ID_KEY.String(util.PgUUIDToString(value.invoice.Load().ID))),
+		trace.WithLinks(trace.Link{SpanContext: value.spanContext}),
+	)
+}
+
+// invoicePayment is a single tx output matched to an invoice.
+type invoicePayment struct {
+	txId        string
+	outputIndex int32
+	amount      *big.Int
+	// Not valid while the tx is in the mempool
+	blockHeight pgtype.Int8
+}
+
+// generateAddressFunc derives a new address for the user when there is no released one to reuse.
+type generateAddressFunc func(ctx context.Context, q *db.Queries, userId pgtype.UUID) (string, error)
+
+// hdCryptoDataStore gives access to a per-coin crypto data table holding a master public key and the last derivation indices.
+type hdCryptoDataStore interface {
+	findCryptoDataId(cd *db.CryptoDatum) pgtype.UUID
+	findKeysAndLock(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error)
+	findIndicesAndLock(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error)
+	updateIndices(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) error
+}
+
+// deriveHdAddressFunc derives the address at major/minor from the master public key.
+type deriveHdAddressFunc func(masterPubKey string, major uint32, minor uint32) (string, error)
+
+// baseCryptoProcessor holds the invoice lifecycle logic shared by every coin processor.
+type baseCryptoProcessor struct {
+	log *zerolog.Logger
+
+	dbConnPool *pgxpool.Pool
+
+	invoiceCn chan<- dto.InvoiceEvent
+
+	pendingInvoices *util.SyncMapTypeSafe[string, pendingInvoice]
+
+	latePaymentGracePeriod time.Duration
+
+	// The in-flight invoice verification, waited for on shutdown
+	tasks *util.Routines
+	// The goroutines living until the processor ctx is done: the event loops and the invoice timers
+	watchers *util.Routines
+}
+
+// pendingInvoiceKey returns the key the invoice is tracked by: the memo for invoices
+// sharing one address, the crypto address otherwise.
+func pendingInvoiceKey(invoice *db.Invoice) string {
+	if invoice.Memo.Valid {
+		return invoice.Memo.String
+	}
+	return invoice.CryptoAddress
+}
+
+// newInvoiceParams fills in everything but the address of the new invoice.
+// The amount is converted to atomic units using the decimals of the invoice asset.
+func newInvoiceParams(req *dto.NewInvoiceRequest, userId pgtype.UUID, decimals int) (db.CreateInvoiceParams, error) {
+	requiredAmount := req.AmountAtomic
+	if requiredAmount == nil {
+		amount, err := util.FloatToAtomicUnits(req.Amount, decimals)
+		if err != nil {
+			return db.CreateInvoiceParams{}, fmt.Errorf("%w: %w", InvalidAmountErr, err)
+		}
+		requiredAmount = amount
+	}
+
+	toleranceAmount := new(big.Int)
+	if req.ToleranceAtomic != nil {
+		toleranceAmount = req.ToleranceAtomic
+	} else if req.TolerancePercent != nil {
+		amount, err := util.PercentOfAtomicUnits(requiredAmount, *req.TolerancePercent)
+		if err != nil {
+			return db.CreateInvoiceParams{}, fmt.Errorf("%w: %w", InvalidToleranceErr, err)
+		}
+		toleranceAmount = amount
+	}
+	if toleranceAmount.Cmp(requiredAmount) > 0 {
+		return db.CreateInvoiceParams{}, InvalidToleranceErr
+	}
+
+	timeout := time.Duration(req.Timeout) * time.Second
+	if timeout < listener.MIN_SYNC_TIMEOUT {
+		timeout = listener.MIN_SYNC_TIMEOUT
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if err := expiresAt.Scan(time.Now().UTC().Add(timeout)); err != nil {
+		return db.CreateInvoiceParams{}, err
+	}
+
+	return db.CreateInvoiceParams{
+		Coin:                  req.Coin,
+		RequiredAmount:        util.BigIntToPgNumeric(requiredAmount),
+		ConfirmationsRequired: int16(req.Confirmations),
+		ExpiresAt:             expiresAt,
+		UserID:                userId,
+		TokenContract:         pgtype.Text{String: req.Token, Valid: req.Token != ""},
+		Decimals:              int16(decimals),
+		ToleranceAmount:       util.BigIntToPgNumeric(toleranceAmount),
+	}, nil
+}
+
+// createInvoiceEvent appends the invoice transition to the event log within the tx of q, so the event is persisted
+// along with the transition or not at all. It has to be the last statement of the tx as the event log stays locked till the tx ends.
+func createInvoiceEvent(ctx context.Context, q *db.Queries, invoice *db.Invoice) (dto.InvoiceEvent, error) {
+	snapshot, err := json.Marshal(invoice)
+	if err != nil {
+		return dto.InvoiceEvent{}, err
+	}
+
+	if err := q.LockInvoiceEvents(ctx); err != nil {
+		return dto.InvoiceEvent{}, err
+	}
+
+	event, err := q.CreateInvoiceEvent(ctx, db.CreateInvoiceEventParams{InvoiceID: invoice.ID, Status: invoice.Status, Invoice: snapshot})
+	if err != nil {
+		return dto.InvoiceEvent{}, err
+	}
+
+	return dto.InvoiceEvent{Sequence: event.Sequence, Invoice: *invoice}, nil
+}
+
+// publishInvoice hands the persisted invoice event over to the PaymentProcessor. It's dropped once ctx is done as nobody receives it anymore.
+func (p *baseCryptoProcessor) publishInvoice(ctx context.Context, event dto.InvoiceEvent) {
+	select {
+	case p.invoiceCn <- event:
+	case <-ctx.Done():
+	}
+}
+
+// shutdown waits for the in-flight invoice verification until ctx is done, then persists the last synced block height
+// regardless, so the sync resumes from it after the restart.
+func (p *baseCryptoProcessor) shutdown(ctx context.Context, coin db.CoinType, lastSyncedBlockHeight uint64) error {
+	err := p.tasks.Wait(ctx)
+	if err != nil {
+		p.log.Warn().Str("coin", string(coin)).Msgf("Abandoning %v in-flight invoice verification tasks", p.tasks.Running())
+	}
+
+	persistCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), stop_routines_timeout)
+	defer cancel()
+	p.persistCryptoCacheHelper(persistCtx, coin, lastSyncedBlockHeight)
+
+	return err
+}
+
+func (p *baseCryptoProcessor) Wait(ctx context.Context) error {
+	if err := p.watchers.Wait(ctx); err != nil {
+		return err
+	}
+	return p.tasks.Wait(ctx)
+}
+
+func (p *baseCryptoProcessor) PendingInvoicesCount() int {
+	return p.pendingInvoices.Len()
+}
+
+func (p *baseCryptoProcessor) createInvoice(ctx context.Context, req *dto.NewInvoiceRequest, decimals int, generateAddress generateAddressFunc) (*db.Invoice, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.UserId); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	coin := req.Coin
+
+	params, err := newInvoiceParams(req, userId, decimals)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	addr, err := q.FindNonOccupiedCryptoAddressAndLockByUserIdAndCoin(ctx, db.FindNonOccupiedCryptoAddressAndLockByUserIdAndCoinParams{UserID: userId, Coin: coin})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+
+		newAddr, err := generateAddress(ctx, q, userId)
+		if err != nil {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+
+		addr, err = q.CreateCryptoAddress(ctx, db.CreateCryptoAddressParams{Address: newAddr, Coin: coin, IsOccupied: true, UserID: userId})
+		if err != nil {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+	}
+
+	params.CryptoAddress = addr.Address
+
+	invoice, err := q.CreateInvoice(ctx, params)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoice").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return &invoice, nil
+}
+
+// generateHdAddress bumps the derivation indices of the user and derives the next address.
+func (p *baseCryptoProcessor) generateHdAddress(ctx context.Context, q *db.Queries, userId pgtype.UUID, store hdCryptoDataStore, derive deriveHdAddressFunc) (string, error) {
+	cd, err := q.FindCryptoDataByUserId(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+
+	id := store.findCryptoDataId(&cd)
+
+	major, minor, err := store.findIndicesAndLock(ctx, q, id)
+	if err != nil {
+		return "", err
+	}
+
+	masterPubKey, err := store.findKeysAndLock(ctx, q, id)
+	if err != nil {
+		return "", err
+	}
+
+	minor++
+	if minor == 0 {
+		major++
+	}
+
+	addr, err := derive(masterPubKey, uint32(major), uint32(minor))
+	if err != nil {
+		return "", err
+	}
+
+	if err := store.updateIndices(ctx, q, id, major, minor); err != nil {
+		return "", err
+	}
+
+	return addr, nil
+}
+
+// isPaidEnough reports whether the received atomic units cover the required amount of the invoice less its tolerance.
+func (p *baseCryptoProcessor) isPaidEnough(invoice *db.Invoice, received *big.Int) bool {
+	requiredAmount, err := util.PgNumericToBigInt(invoice.RequiredAmount)
+	if err != nil {
+		p.log.Err(err).Str("invoiceId", util.PgUUIDToString(invoice.ID)).Msg("An error occurred while reading the required amount.")
+		return false
+	}
+
+	toleranceAmount, err := util.PgNumericToBigInt(invoice.ToleranceAmount)
+	if err != nil {
+		p.log.Err(err).Str("invoiceId", util.PgUUIDToString(invoice.ID)).Msg("An error occurred while reading the tolerance amount.")
+		return false
+	}
+
+	return received.Cmp(new(big.Int).Sub(requiredAmount, toleranceAmount)) >= 0
+}
+
+// paymentStatus returns the status of the invoice once its payments total the received atomic units:
+// PAID_AFTER_EXPIRY for an expired invoice whatever the amount is, PENDING_MEMPOOL if the payments cover it and PARTIALLY_PAID otherwise.
+func (p *baseCryptoProcessor) paymentStatus(invoice *db.Invoice, received *big.Int) db.InvoiceStatusType {
+	switch {
+	case isExpired(invoice):
+		return db.InvoiceStatusTypePAIDAFTEREXPIRY
+	case p.isPaidEnough(invoice, received):
+		return db.InvoiceStatusTypePENDINGMEMPOOL
+	}
+
+	return db.InvoiceStatusTypePARTIALLYPAID
+}
+
+// paymentOutcome compares the paid amount of a paid invoice to the required one.
+// It returns the outcome along with their difference (paid - required).
+func paymentOutcome(requiredAmount *big.Int, paidAmount *big.Int) (db.InvoiceOutcomeType, *big.Int) {
+	delta := new(big.Int).Sub(paidAmount, requiredAmount)
+
+	switch delta.Sign() {
+	case -1:
+		return db.InvoiceOutcomeTypeUNDERPAID, delta
+	case 1:
+		return db.InvoiceOutcomeTypeOVERPAID, delta
+	}
+
+	return db.InvoiceOutcomeTypeEXACT, delta
+}
+
+// acceptsPayments reports whether the payments to the invoice are still recorded: while it waits for (the rest of)
+// its payment or has expired. Expired invoices are only tracked during the late payment grace period.
+func acceptsPayments(invoice *db.Invoice) bool {
+	return invoice.Status == db.InvoiceStatusTypePENDING ||
+		invoice.Status == db.InvoiceStatusTypePARTIALLYPAID ||
+		isExpired(invoice)
+}
+
+// isPending reports whether the invoice waits for its payment or the confirmations of it.
+func isPending(invoice *db.Invoice) bool {
+	return invoice.Status == db.InvoiceStatusTypePENDING ||
+		invoice.Status == db.InvoiceStatusTypePARTIALLYPAID ||
+		invoice.Status == db.InvoiceStatusTypePENDINGMEMPOOL
+}
+
+// isPaid reports whether the invoice was paid in time: its payment waits for the confirmations or has got them.
+// Only such invoices become REORGED once their payments are orphaned.
+func isPaid(invoice *db.Invoice) bool {
+	return invoice.Status == db.InvoiceStatusTypePENDINGMEMPOOL || invoice.Status == db.InvoiceStatusTypeCONFIRMED
+}
+
+func isExpired(invoice *db.Invoice) bool {
+	return invoice.Status == db.InvoiceStatusTypeEXPIRED || invoice.Status == db.InvoiceStatusTypePAIDAFTEREXPIRY
+}
+
+// recordPayment stores the payment and moves the invoice to PARTIALLY_PAID or PENDING_MEMPOOL
+// depending on whether the total of its payments covers the required amount.
+// A payment to an expired invoice moves it to PAID_AFTER_EXPIRY whatever the amount is.
+// A payment that has been already recorded only gets its block height updated.
+// It returns true if the payment was new and the invoice has been updated, and an error if the payment couldn't be recorded.
+func (p *baseCryptoProcessor) recordPayment(ctx context.Context, value pendingInvoice, payment invoicePayment) (bool, error) {
+	ctx, span := startInvoiceSpan(ctx, "processor.recordPayment", value)
+	span.SetAttributes(tracing.TX_ID_KEY.String(payment.txId))
+	defer span.End()
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return false, err
+	}
+
+	// Payments to the same invoice may be recorded concurrently, the row lock serializes them
+	invoice, err := q.FindInvoiceByIdAndLock(ctx, value.invoice.Load().ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindInvoiceByIdAndLock").Msg(util.DefaultFailedSqlQueryMsg)
+		return false, err
+	}
+
+	recorded := false
+	if acceptsPayments(&invoice) {
+		if _, err := q.CreateInvoicePayment(ctx, db.CreateInvoicePaymentParams{
+			InvoiceID:   invoice.ID,
+			TxID:        payment.txId,
+			OutputIndex: payment.outputIndex,
+			Amount:      util.BigIntToPgNumeric(payment.amount),
+			BlockHeight: payment.blockHeight,
+		}); err == nil {
+			recorded = true
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			tx.Rollback(ctx)
+			p.log.Err(err).Str("queryName", "CreateInvoicePayment").Msg(util.DefaultFailedSqlQueryMsg)
+			return false, err
+		}
+	}
+
+	// The output has been already recorded or the invoice doesn't wait for payments anymore
+	if !recorded {
+		if payment.blockHeight.Valid {
+			if _, err := q.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId(ctx, db.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxIdParams{InvoiceID: invoice.ID, TxID: payment.txId, BlockHeight: payment.blockHeight}); err != nil {
+				tx.Rollback(ctx)
+				p.log.Err(err).Str("queryName", "UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId").Msg(util.DefaultFailedSqlQueryMsg)
+				return false, err
+			}
+		}
+
+		tx.Commit(ctx)
+		return false, nil
+	}
+
+	total, err := q.SumAmountInvoicePaymentsByInvoiceId(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "SumAmountInvoicePaymentsByInvoiceId").Msg(util.DefaultFailedSqlQueryMsg)
+		return false, err
+	}
+
+	totalAmount, err := util.PgNumericToBigInt(total)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("fieldName", "amount").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+		return false, err
+	}
+
+	txId := pgtype.Text{String: payment.txId, Valid: true}
+
+	requiredAmount, err := util.PgNumericToBigInt(invoice.RequiredAmount)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("fieldName", "requiredAmount").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+		return false, err
+	}
+
+	outcome, delta := paymentOutcome(requiredAmount, totalAmount)
+
+	switch p.paymentStatus(&invoice, totalAmount) {
+	case db.InvoiceStatusTypePAIDAFTEREXPIRY:
+		p.log.Info().Str("invoiceId", util.PgUUIDToString(invoice.ID)).Msgf("Tx %v paid the invoice after expiry", payment.txId)
+
+		invoice, err = q.PayAfterExpiryInvoiceById(ctx, db.PayAfterExpiryInvoiceByIdParams{
+			ID:           invoice.ID,
+			ActualAmount: total,
+			TxID:         txId,
+			AmountDelta:  util.BigIntToPgNumeric(delta),
+			Outcome:      db.NullInvoiceOutcomeType{InvoiceOutcomeType: outcome, Valid: true},
+		})
+		if err != nil {
+			tx.Rollback(ctx)
+			p.log.Err(err).Str("queryName", "PayAfterExpiryInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+			return false, err
+		}
+	case db.InvoiceStatusTypePENDINGMEMPOOL:
+		if outcome != db.InvoiceOutcomeTypeEXACT {
+			p.log.Info().Str("invoiceId", util.PgUUIDToString(invoice.ID)).Msgf("Invoice is %v by %v atomic units", outcome, new(big.Int).Abs(delta))
+		}
+
+		invoice, err = q.ConfirmInvoiceStatusMempoolById(ctx, db.ConfirmInvoiceStatusMempoolByIdParams{
+			ID:           invoice.ID,
+			ActualAmount: total,
+			TxID:         txId,
+			AmountDelta:  util.BigIntToPgNumeric(delta),
+			Outcome:      db.NullInvoiceOutcomeType{InvoiceOutcomeType: outcome, Valid: true},
+		})
+		if err != nil {
+			tx.Rollback(ctx)
+			p.log.Err(err).Str("queryName", "ConfirmInvoiceStatusMempoolById").Msg(util.DefaultFailedSqlQueryMsg)
+			return false, err
+		}
+	default:
+		invoice, err = q.PartiallyPayInvoiceById(ctx, db.PartiallyPayInvoiceByIdParams{ID: invoice.ID, ActualAmount: total, TxID: txId})
+		if err != nil {
+			tx.Rollback(ctx)
+			p.log.Err(err).Str("queryName", "PartiallyPayInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+			return false, err
+		}
+	}
+
+	event, err := createInvoiceEvent(ctx, q, &invoice)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return false, err
+	}
+
+	tx.Commit(ctx)
+
+	value.invoice.Store(&invoice)
+
+	p.publishInvoice(ctx, event)
+
+	return true, nil
+}
+
+// findPaymentTxIds returns the distinct txs paying the invoice.
+func (p *baseCryptoProcessor) findPaymentTxIds(ctx context.Context, invoice *db.Invoice) ([]string, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	payments, err := q.FindAllInvoicePaymentsByInvoiceId(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindAllInvoicePaymentsByInvoiceId").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	txIds := make([]string, 0, len(payments))
+	seen := make(map[string]bool, len(payments))
+	for i := 0; i < len(payments); i++ {
+		if seen[payments[i].TxID] {
+			continue
+		}
+		seen[payments[i].TxID] = true
+		txIds = append(txIds, payments[i].TxID)
+	}
+
+	return txIds, nil
+}
+
+// dropMissingPayments deletes the payments of the invoice made by the txs gone from the chain, e.g. rejected or orphaned ones,
+// and recomputes the paid amount from the rest of them. It returns the updated invoice and whether the remaining payments
+// still cover the required amount, the caller expires or reorgs the invoice otherwise.
+func (p *baseCryptoProcessor) dropMissingPayments(ctx context.Context, invoice *db.Invoice, txIds []string) (*db.Invoice, bool, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, false, err
+	}
+
+	lockedInvoice, err := q.FindInvoiceByIdAndLock(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindInvoiceByIdAndLock").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, false, err
+	}
+
+	if _, err := q.DeleteInvoicePaymentsByInvoiceIdAndTxIds(ctx, db.DeleteInvoicePaymentsByInvoiceIdAndTxIdsParams{InvoiceID: invoice.ID, TxIds: txIds}); err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "DeleteInvoicePaymentsByInvoiceIdAndTxIds").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, false, err
+	}
+
+	payments, err := q.FindAllInvoicePaymentsByInvoiceId(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindAllInvoicePaymentsByInvoiceId").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, false, err
+	}
+
+	requiredAmount, err := util.PgNumericToBigInt(lockedInvoice.RequiredAmount)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("fieldName", "requiredAmount").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+		return nil, false, err
+	}
+
+	totalAmount := new(big.Int)
+	for i := 0; i < len(payments); i++ {
+		amount, err := util.PgNumericToBigInt(payments[i].Amount)
+		if err != nil {
+			tx.Rollback(ctx)
+			p.log.Err(err).Str("fieldName", "amount").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+			return nil, false, err
+		}
+		totalAmount.Add(totalAmount, amount)
+	}
+
+	params := db.UpdatePaidAmountInvoiceByIdParams{ID: invoice.ID}
+	if len(payments) > 0 {
+		params.ActualAmount = util.BigIntToPgNumeric(totalAmount)
+		params.TxID = pgtype.Text{String: payments[len(payments)-1].TxID, Valid: true}
+	}
+
+	paid := len(payments) > 0 && p.isPaidEnough(&lockedInvoice, totalAmount)
+	if paid {
+		outcome, delta := paymentOutcome(requiredAmount, totalAmount)
+		params.AmountDelta = util.BigIntToPgNumeric(delta)
+		params.Outcome = db.NullInvoiceOutcomeType{InvoiceOutcomeType: outcome, Valid: true}
+	}
+
+	updatedInvoice, err := q.UpdatePaidAmountInvoiceById(ctx, params)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "UpdatePaidAmountInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, false, err
+	}
+
+	event, err := createInvoiceEvent(ctx, q, &updatedInvoice)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, false, err
+	}
+
+	tx.Commit(ctx)
+
+	if value, ok := p.pendingInvoices.Load(pendingInvoiceKey(invoice)); ok && value.invoice.Load().ID == invoice.ID {
+		value.invoice.Store(&updatedInvoice)
+	}
+
+	if paid {
+		p.log.Info().Str("invoiceId", util.PgUUIDToString(invoice.ID)).Msgf("The remaining payments still cover the invoice after dropping txs %v", txIds)
+	}
+
+	p.publishInvoice(ctx, event)
+
+	return &updatedInvoice, paid, nil
+}
+
+// trackConfirmations persists the confirmations of the least confirmed payment tx and publishes
+// the invoice whenever the number changes. It returns true once the required confirmations are reached.
+func (p *baseCryptoProcessor) trackConfirmations(ctx context.Context, value pendingInvoice, confirmations uint64) bool {
+	invoice := value.invoice.Load()
+	if confirmations >= uint64(invoice.ConfirmationsRequired) {
+		return true
+	}
+	if confirmations == uint64(invoice.Confirmations) {
+		return false
+	}
+
+	ctx, span := startInvoiceSpan(ctx, "processor.trackConfirmations", value)
+	defer span.End()
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return false
+	}
+
+	updatedInvoice, err := q.UpdateConfirmationsInvoiceById(ctx, db.UpdateConfirmationsInvoiceByIdParams{ID: invoice.ID, Confirmations: int16(confirmations)})
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "UpdateConfirmationsInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+		return false
+	}
+
+	event, err := createInvoiceEvent(ctx, q, &updatedInvoice)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return false
+	}
+
+	tx.Commit(ctx)
+
+	value.invoice.Store(&updatedInvoice)
+
+	p.publishInvoice(ctx, event)
+
+	return false
+}
+
+func (p *baseCryptoProcessor) confirmInvoice(ctx context.Context, value pendingInvoice) {
+	invoice := value.invoice.Load()
+
+	if _, loaded := p.pendingInvoices.LoadAndDelete(pendingInvoiceKey(invoice)); !loaded {
+		return
+	}
+	value.cancelTimeoutFunc()
+
+	ctx, span := startInvoiceSpan(ctx, "processor.confirmInvoice", value)
+	defer span.End()
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	confirmedInvoice, err := q.ConfirmInvoiceById(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "ConfirmInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	if err := webhook.Enqueue(ctx, q, webhook.INVOICE_CONFIRMED_EVENT, &confirmedInvoice); err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Msg(failed_enqueue_webhook_event_msg)
+		return
+	}
+
+	event, err := createInvoiceEvent(ctx, q, &confirmedInvoice)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	tx.Commit(ctx)
+
+	p.tasks.Go(func() { p.releaseAddressHelper(ctx, invoice) })
+
+	p.publishInvoice(ctx, event)
+}
+
+// unconfirmInvoice moves the CONFIRMED invoice back to PENDING_MEMPOOL after its payment block was orphaned
+// and tracks it until the payment gets the required confirmations on the new chain.
+// The invoice was paid in time, so it isn't expired by timeout.
+func (p *baseCryptoProcessor) unconfirmInvoice(ctx context.Context, invoice *db.Invoice) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	unconfirmedInvoice, err := q.UnconfirmInvoiceById(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "UnconfirmInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	// The address was released on confirmation, it mustn't be handed out again while the payment is unconfirmed
+	if !invoice.Memo.Valid {
+		if _, err := q.UpdateIsOccupiedByCryptoAddress(ctx, db.UpdateIsOccupiedByCryptoAddressParams{IsOccupied: true, Address: invoice.CryptoAddress}); err != nil {
+			tx.Rollback(ctx)
+			p.log.Err(err).Str("queryName", "UpdateIsOccupiedByCryptoAddress").Msg(util.DefaultFailedSqlQueryMsg)
+			return
+		}
+	}
+
+	event, err := createInvoiceEvent(ctx, q, &unconfirmedInvoice)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	tx.Commit(ctx)
+
+	invoicePtr := &atomic.Pointer[db.Invoice]{}
+	invoicePtr.Store(&unconfirmedInvoice)
+	value := pendingInvoice{invoice: invoicePtr, cancelTimeoutFunc: func() {}, expired: &atomic.Bool{}, reorged: true}
+	if _, loaded := p.pendingInvoices.LoadOrStore(pendingInvoiceKey(&unconfirmedInvoice), value); loaded {
+		p.log.Warn().Str("invoiceId", util.PgUUIDToString(invoice.ID)).Msgf("Address %v is used by another pending invoice, the invoice won't be tracked until restart", invoice.CryptoAddress)
+	}
+
+	p.publishInvoice(ctx, event)
+}
+
+// reorgInvoice marks the invoice REORGED as its payment tx is gone after a chain reorganization.
+func (p *baseCryptoProcessor) reorgInvoice(ctx context.Context, invoice *db.Invoice) {
+	tracked := false
+	if value, ok := p.pendingInvoices.Load(pendingInvoiceKey(invoice)); ok && value.invoice.Load().ID == invoice.ID {
+		p.pendingInvoices.Delete(pendingInvoiceKey(invoice))
+		value.cancelTimeoutFunc()
+		tracked = true
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	reorgedInvoice, err := q.ReorgInvoiceById(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "ReorgInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	event, err := createInvoiceEvent(ctx, q, &reorgedInvoice)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	tx.Commit(ctx)
+
+	// An untracked invoice had its address released already
+	if tracked {
+		p.tasks.Go(func() { p.releaseAddressHelper(ctx, invoice) })
+	}
+
+	p.publishInvoice(ctx, event)
+}
+
+func (p *baseCryptoProcessor) persistCryptoCacheHelper(ctx context.Context, coin db.CoinType, lastSyncedBlockHeight uint64) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	var height pgtype.Int8
+	if err := height.Scan(int64(lastSyncedBlockHeight)); err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("fieldName", "height").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+		return
+	}
+
+	if _, err := q.UpdateCryptoCacheByCoin(ctx, db.UpdateCryptoCacheByCoinParams{Coin: coin, LastSyncedBlockHeight: height}); err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "UpdateCryptoCacheByCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	tx.Commit(ctx)
+}
+
+func (p *baseCryptoProcessor) releaseAddressHelper(ctx context.Context, invoice *db.Invoice) {
+	// A memo invoice uses the shared merchant address which is never occupied
+	if invoice.Memo.Valid {
+		return
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	if _, err := q.UpdateIsOccupiedByCryptoAddress(ctx, db.UpdateIsOccupiedByCryptoAddressParams{IsOccupied: false, Address: invoice.CryptoAddress}); err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "UpdateIsOccupiedByCryptoAddress").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	tx.Commit(ctx)
+}
+
+// expireInvoice expires the tracked invoice and watches it for late payments during the grace period.
+// It returns InvoiceNotPendingErr if the invoice isn't tracked or has expired already.
+func (p *baseCryptoProcessor) expireInvoice(ctx context.Context, invoice *db.Invoice) (*db.Invoice, error) {
+	value, ok := p.pendingInvoices.Load(pendingInvoiceKey(invoice))
+	if !ok || value.invoice.Load().ID != invoice.ID || !value.expired.CompareAndSwap(false, true) {
+		return nil, InvoiceNotPendingErr
+	}
+
+	ctx, span := startInvoiceSpan(ctx, "processor.expireInvoice", value)
+	defer span.End()
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.pendingInvoices.Delete(pendingInvoiceKey(invoice))
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	expiredInvoice, err := q.ExpireInvoiceById(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.pendingInvoices.Delete(pendingInvoiceKey(invoice))
+		p.log.Err(err).Str("queryName", "ExpireInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	if err := webhook.Enqueue(ctx, q, webhook.INVOICE_EXPIRED_EVENT, &expiredInvoice); err != nil {
+		tx.Rollback(ctx)
+		p.pendingInvoices.Delete(pendingInvoiceKey(invoice))
+		p.log.Err(err).Msg(failed_enqueue_webhook_event_msg)
+		return nil, err
+	}
+
+	event, err := createInvoiceEvent(ctx, q, &expiredInvoice)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.pendingInvoices.Delete(pendingInvoiceKey(invoice))
+		p.log.Err(err).Str("queryName", "CreateInvoiceEvent").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	value.invoice.Store(&expiredInvoice)
+
+	p.watchers.Go(func() { p.watchLatePaymentsHelper(ctx, value, p.latePaymentGracePeriod) })
+
+	p.publishInvoice(ctx, event)
+
+	return &expiredInvoice, nil
+}
+
+// ExpireInvoice expires the pending invoice ahead of its timeout, ctx has to outlive the grace period.
+func (p *baseCryptoProcessor) ExpireInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error) {
+	return p.expireInvoice(ctx, &invoice)
+}
+
+// recheckInvoice starts tracking the pending invoice again if it isn't tracked, e.g. after it got lost on a failed query,
+// and verifies its known payments with verify right away instead of waiting for the next block.
+// The new payments are found while syncing as usual.
+func (p *baseCryptoProcessor) recheckInvoice(ctx context.Context, invoice db.Invoice, verify func(ctx context.Context, value pendingInvoice)) (*db.Invoice, error) {
+	key := pendingInvoiceKey(&invoice)
+
+	value, ok := p.pendingInvoices.Load(key)
+	if !ok {
+		if !isPending(&invoice) {
+			return nil, InvoiceNotPendingErr
+		}
+
+		p.handleInvoice(ctx, invoice)
+		value, ok = p.pendingInvoices.Load(key)
+	}
+	if !ok || value.invoice.Load().ID != invoice.ID {
+		return nil, InvoiceAddressInUseErr
+	}
+
+	verify(ctx, value)
+
+	return value.invoice.Load(), nil
+}
+
+// watchLatePaymentsHelper keeps the expired invoice tracked for the grace period,
+// then stops tracking it and releases its address unless late funds have arrived.
+func (p *baseCryptoProcessor) watchLatePaymentsHelper(ctx context.Context, value pendingInvoice, gracePeriod time.Duration) {
+	if gracePeriod > 0 {
+		select {
+		case <-time.After(gracePeriod):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	invoice := value.invoice.Load()
+	if _, loaded := p.pendingInvoices.LoadAndDelete(pendingInvoiceKey(invoice)); !loaded {
+		return
+	}
+
+	p.releaseExpiredInvoiceAddressHelper(ctx, invoice)
+	value.cancelTimeoutFunc()
+}
+
+// releaseExpiredInvoiceAddressHelper releases the address of the invoice if it's still EXPIRED.
+// An address which received late funds is never reused.
+func (p *baseCryptoProcessor) releaseExpiredInvoiceAddressHelper(ctx context.Context, invoice *db.Invoice) {
+	if invoice.Memo.Valid {
+		return
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	// The lock makes sure no late payment is being recorded at the moment
+	lockedInvoice, err := q.FindInvoiceByIdAndLock(ctx, invoice.ID)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindInvoiceByIdAndLock").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+	if lockedInvoice.Status != db.InvoiceStatusTypeEXPIRED {
+		tx.Rollback(ctx)
+		p.log.Info().Msgf("Address %v received late funds and won't be reused", invoice.CryptoAddress)
+		return
+	}
+
+	if _, err := q.UpdateIsOccupiedByCryptoAddress(ctx, db.UpdateIsOccupiedByCryptoAddressParams{IsOccupied: false, Address: invoice.CryptoAddress}); err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "UpdateIsOccupiedByCryptoAddress").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	tx.Commit(ctx)
+}
+
+func (p *baseCryptoProcessor) handleInvoiceHelper(confirmedInvoiceCtx context.Context, invoice *db.Invoice) {
+	select {
+	case <-time.After(invoice.ExpiresAt.Time.Sub(time.Now().UTC())):
+		p.expireInvoice(confirmedInvoiceCtx, invoice)
+		return
+	case <-confirmedInvoiceCtx.Done():
+		return
+	}
+}
+func (p *baseCryptoProcessor) handleInvoice(ctx context.Context, invoice db.Invoice) {
+	if _, ok := p.pendingInvoices.Load(pendingInvoiceKey(&invoice)); ok {
+		return
+	}
+
+	confirmedInvoiceCtx, cancel := context.WithCancel(ctx)
+
+	invoicePtr := &atomic.Pointer[db.Invoice]{}
+	invoicePtr.Store(&invoice)
+	p.pendingInvoices.Store(pendingInvoiceKey(&invoice), pendingInvoice{
+		invoice:           invoicePtr,
+		cancelTimeoutFunc: cancel,
+		expired:           &atomic.Bool{},
+		spanContext:       trace.SpanContextFromContext(ctx),
+	})
+
+	p.watchers.Go(func() { p.handleInvoiceHelper(confirmedInvoiceCtx, &invoice) })
+}
+
+// resumeExpiredInvoice tracks the invoice for the rest of its late payment grace period.
+func (p *baseCryptoProcessor) resumeExpiredInvoice(ctx context.Context, invoice db.Invoice) {
+	if _, ok := p.pendingInvoices.Load(pendingInvoiceKey(&invoice)); ok {
+		return
+	}
+
+	invoicePtr := &atomic.Pointer[db.Invoice]{}
+	invoicePtr.Store(&invoice)
+	expired := &atomic.Bool{}
+	expired.Store(true)
+
+	value := pendingInvoice{invoice: invoicePtr, cancelTimeoutFunc: func() {}, expired: expired}
+	p.pendingInvoices.Store(pendingInvoiceKey(&invoice), value)
+
+	gracePeriod := invoice.ExpiredAt.Time.Add(p.latePaymentGracePeriod).Sub(time.Now().UTC())
+	p.watchers.Go(func() { p.watchLatePaymentsHelper(ctx, value, gracePeriod) })
+}
+
+func (p *baseCryptoProcessor) ResumeInvoice(ctx context.Context, invoice db.Invoice) {
+	if isExpired(&invoice) {
+		p.resumeExpiredInvoice(ctx, invoice)
+		return
+	}
+
+	p.handleInvoice(ctx, invoice)
+}
+
+func newBaseCryptoProcessor(dbConnPool *pgxpool.Pool, invoiceCn chan<- dto.InvoiceEvent, ic *dto.InvoiceConfig, log *zerolog.Logger) baseCryptoProcessor {
+	return baseCryptoProcessor{
+		log:                    log,
+		dbConnPool:             dbConnPool,
+		invoiceCn:              invoiceCn,
+		pendingInvoices:        new(util.SyncMapTypeSafe[string, pendingInvoice]),
+		latePaymentGracePeriod: ic.LatePaymentGracePeriod,
+		tasks:                  new(util.Routines),
+		watchers:               new(util.Routines),
+	}
+}
@@ -0,0 +1,416 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chekist32/goipay/internal/daemon/ton"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/dto"
+	"github.com/chekist32/goipay/internal/listener"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// The page size the transactions of a merchant address are fetched with
+	ton_transactions_limit uint32 = 50
+	// The transactions are paged through back to the creation of the oldest pending invoice less the margin for the clock skew
+	ton_tx_time_margin time.Duration = 5 * time.Minute
+)
+
+var (
+	tonAddressNotSetErr error = errors.New("TON address is not set")
+)
+
+// tonProcessor handles TON invoices. Every invoice of a user is paid to the same
+// merchant address and is told apart by the unique comment (memo) of the payment.
+type tonProcessor struct {
+	baseCryptoProcessor
+
+	daemon   ton.IDaemonRpcClient
+	daemonEx *listener.TonDaemonRpcClientExecutor
+
+	// Merchant addresses being polled at the moment
+	pollingAddresses *util.SyncMapTypeSafe[string, struct{}]
+	// The last transaction seen on every merchant address, loaded from the database on the first poll
+	cursors *util.SyncMapTypeSafe[string, ton.TransactionId]
+}
+
+// verifyTonTx records the payment of the tx if it pays an invoice. It returns an error if the payment couldn't be recorded,
+// the tx has to be verified again then.
+func (p *tonProcessor) verifyTonTx(ctx context.Context, address string, tonTx ton.Transaction) error {
+	// External messages have no source, a bounced payment produces an outgoing message
+	if tonTx.InMsg == nil || tonTx.InMsg.Source == "" || len(tonTx.OutMsgs) > 0 {
+		return nil
+	}
+
+	value, ok := p.pendingInvoices.Load(strings.TrimSpace(tonTx.InMsg.Message))
+	if !ok {
+		return nil
+	}
+
+	invoice := value.invoice.Load()
+	// The invoice has been already paid or the memo was sent to another merchant
+	if !acceptsPayments(invoice) || invoice.CryptoAddress != address || tonTx.InMsg.Value == 0 {
+		return nil
+	}
+
+	// The API doesn't report the masterchain block of a tx, so the block height is left unset
+	payment := invoicePayment{
+		txId:        tonTx.TransactionId.Hash,
+		outputIndex: 0,
+		amount:      new(big.Int).SetUint64(tonTx.InMsg.Value),
+	}
+	recorded, err := p.recordPayment(ctx, value, payment)
+	if err != nil {
+		return err
+	}
+	if !recorded || value.invoice.Load().Status != db.InvoiceStatusTypePENDINGMEMPOOL {
+		return nil
+	}
+
+	// A tx returned by the API is already committed to the masterchain and TON has no reorgs,
+	// so there is nothing left to wait for.
+	p.confirmInvoice(ctx, value)
+
+	return nil
+}
+
+// findNewTonTxs pages back through the transactions of the address until the last seen one with lastLt
+// or the ones older than since. The new transactions are returned the oldest first.
+func (p *tonProcessor) findNewTonTxs(ctx context.Context, address string, lastLt uint64, since time.Time) ([]ton.Transaction, error) {
+	res := make([]ton.Transaction, 0)
+
+	var from ton.TransactionId
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		txs, err := p.daemon.GetTransactions(address, ton_transactions_limit, from.Lt, from.Hash)
+		if err != nil {
+			return nil, err
+		}
+		lastPage := uint32(len(txs)) < ton_transactions_limit
+
+		// A page starts with the tx it's requested from
+		if from.Lt != 0 && len(txs) > 0 && txs[0].TransactionId == from {
+			txs = txs[1:]
+		}
+
+		for i := 0; i < len(txs); i++ {
+			if txs[i].TransactionId.Lt <= lastLt || txs[i].Utime < since.Unix() {
+				lastPage = true
+				break
+			}
+			res = append(res, txs[i])
+		}
+
+		if lastPage || len(txs) == 0 {
+			break
+		}
+		from = txs[len(txs)-1].TransactionId
+	}
+
+	slices.Reverse(res)
+	return res, nil
+}
+
+func (p *tonProcessor) findCursor(ctx context.Context, address string) (ton.TransactionId, error) {
+	if cursor, ok := p.cursors.Load(address); ok {
+		return cursor, nil
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return ton.TransactionId{}, err
+	}
+
+	cursor, err := q.FindTonAddressCursorByAddress(ctx, address)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindTonAddressCursorByAddress").Msg(util.DefaultFailedSqlQueryMsg)
+		return ton.TransactionId{}, err
+	}
+
+	tx.Commit(ctx)
+
+	return ton.TransactionId{Lt: uint64(cursor.LastLt), Hash: cursor.LastHash}, nil
+}
+
+// persistCursor stores the last verified transaction of the address. Failing to persist it only makes the next poll
+// after a restart page through the transactions seen already, their payments are recorded once.
+func (p *tonProcessor) persistCursor(ctx context.Context, address string, cursor ton.TransactionId) {
+	p.cursors.Store(address, cursor)
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	if err := q.UpsertTonAddressCursor(ctx, db.UpsertTonAddressCursorParams{Address: address, LastLt: int64(cursor.Lt), LastHash: cursor.Hash}); err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "UpsertTonAddressCursor").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	tx.Commit(ctx)
+}
+
+// oldestInvoiceCreatedAt returns the creation time of the oldest invoice tracked on the address,
+// no memo could have been paid before it.
+func (p *tonProcessor) oldestInvoiceCreatedAt(address string) time.Time {
+	oldest := time.Now().UTC()
+	p.pendingInvoices.Range(func(key string, value pendingInvoice) bool {
+		invoice := value.invoice.Load()
+		if invoice.CryptoAddress == address && invoice.CreatedAt.Time.Before(oldest) {
+			oldest = invoice.CreatedAt.Time
+		}
+		return true
+	})
+
+	return oldest.Add(-ton_tx_time_margin)
+}
+
+// verifyTonTxs verifies the transactions of the address received since the last poll. The cursor stops before
+// the first tx whose payment couldn't be recorded, so the next poll verifies it again.
+func (p *tonProcessor) verifyTonTxs(ctx context.Context, address string) {
+	if _, polling := p.pollingAddresses.LoadOrStore(address, struct{}{}); polling {
+		return
+	}
+	defer p.pollingAddresses.Delete(address)
+
+	cursor, err := p.findCursor(ctx, address)
+	if err != nil {
+		return
+	}
+
+	txs, err := p.findNewTonTxs(ctx, address, cursor.Lt, p.oldestInvoiceCreatedAt(address))
+	if err != nil {
+		p.log.Err(err).Str("method", "getTransactions").Msg(util.DefaultFailedFetchingTONDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeTON), "getTransactions").Inc()
+		return
+	}
+	if len(txs) == 0 {
+		return
+	}
+
+	verified := 0
+	for ; verified < len(txs); verified++ {
+		if err := p.verifyTonTx(ctx, address, txs[verified]); err != nil {
+			break
+		}
+	}
+	if verified == 0 {
+		return
+	}
+
+	p.persistCursor(ctx, address, txs[verified-1].TransactionId)
+}
+
+func (p *tonProcessor) verifyTonTxsOnNewBlock(ctx context.Context) {
+	addresses := make(map[string]struct{})
+	p.pendingInvoices.Range(func(key string, value pendingInvoice) bool {
+		addresses[value.invoice.Load().CryptoAddress] = struct{}{}
+		return true
+	})
+
+	for address := range addresses {
+		p.tasks.Go(func() { p.verifyTonTxs(ctx, address) })
+	}
+}
+
+func (p *tonProcessor) persistCryptoCacheHelper(ctx context.Context) {
+	p.baseCryptoProcessor.persistCryptoCacheHelper(ctx, db.CoinTypeTON, p.daemonEx.LastSyncedBlockHeight())
+}
+
+func (p *tonProcessor) Start(ctx context.Context) error {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return err
+	}
+
+	cache, err := q.FindCryptoCacheByCoin(ctx, db.CoinTypeTON)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindCryptoCacheByCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		return err
+	}
+
+	info, err := p.daemon.GetMasterchainInfo()
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("method", "getMasterchainInfo").Msg(util.DefaultFailedFetchingTONDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeTON), "getMasterchainInfo").Inc()
+		return err
+	}
+
+	height := info.Last.Seqno
+	if cache.LastSyncedBlockHeight.Valid {
+		height = uint64(cache.LastSyncedBlockHeight.Int64)
+	}
+
+	p.watchers.Go(func() {
+		p.watchers.Go(func() {
+			blockCn := p.daemonEx.NewBlockChan()
+
+			for {
+				select {
+				case <-blockCn:
+					p.tasks.Go(func() { p.verifyTonTxsOnNewBlock(ctx) })
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+
+		p.persistCryptoCacheHelper(ctx)
+		for {
+			select {
+			case <-time.After(persist_cache_timeout):
+				p.tasks.Go(func() { p.persistCryptoCacheHelper(ctx) })
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	tx.Commit(ctx)
+
+	p.daemonEx.Start(height)
+	return nil
+}
+
+func (p *tonProcessor) createInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return nil, err
+	}
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.UserId); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	cd, err := q.FindCryptoDataByUserId(ctx, userId)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	if !cd.TonID.Valid {
+		tx.Rollback(ctx)
+		return nil, tonAddressNotSetErr
+	}
+
+	address, err := q.FindAddressAndLockTONCryptoDataById(ctx, cd.TonID)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	memo, err := util.GenerateTonMemo()
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	params, err := newInvoiceParams(req, userId, util.TON_DECIMALS)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	params.CryptoAddress = address
+	params.Memo = pgtype.Text{String: memo, Valid: true}
+
+	invoice, err := q.CreateInvoice(ctx, params)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "CreateInvoice").Msg(util.DefaultFailedSqlQueryMsg)
+		return nil, err
+	}
+
+	tx.Commit(ctx)
+
+	return &invoice, nil
+}
+
+func (p *tonProcessor) HandleNewInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
+	invoice, err := p.createInvoice(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.handleInvoice(ctx, *invoice)
+
+	return invoice, nil
+}
+
+func (p *tonProcessor) Stop() {
+	p.daemonEx.Stop()
+}
+
+func (p *tonProcessor) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx, db.CoinTypeTON, p.daemonEx.LastSyncedBlockHeight())
+}
+
+func (p *tonProcessor) Health() error {
+	_, err := p.daemon.GetMasterchainInfo()
+	return err
+}
+
+func (p *tonProcessor) SyncLag() uint64 {
+	return p.daemonEx.SyncLag()
+}
+
+func (p *tonProcessor) LastSyncedBlockHeight() uint64 {
+	return p.daemonEx.LastSyncedBlockHeight()
+}
+
+func (p *tonProcessor) RecheckInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error) {
+	return p.recheckInvoice(ctx, invoice, func(ctx context.Context, value pendingInvoice) {
+		p.verifyTonTxs(ctx, value.invoice.Load().CryptoAddress)
+	})
+}
+
+func (p *tonProcessor) Assets() []dto.Asset {
+	return []dto.Asset{{Coin: db.CoinTypeTON, Symbol: string(db.CoinTypeTON), Decimals: uint32(util.TON_DECIMALS)}}
+}
+
+func newTonProcessor(dbConnPool *pgxpool.Pool, invoiceCn chan<- dto.InvoiceEvent, c *dto.DaemonsConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (CoinProcessor, error) {
+	u, err := url.Parse(c.Ton.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	d := ton.NewDaemonRpcClient(ton.NewRpcConnection(u, c.Ton.Pass))
+
+	info, err := d.GetMasterchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Connected to the TON API (masterchain seqno: %v)", info.Last.Seqno)
+
+	return &tonProcessor{
+			baseCryptoProcessor: newBaseCryptoProcessor(dbConnPool, invoiceCn, ic, log),
+			daemon:              d,
+			daemonEx:            listener.NewTonDaemonRpcClientExecutor(d, log),
+			pollingAddresses:    new(util.SyncMapTypeSafe[string, struct{}]),
+			cursors:             new(util.SyncMapTypeSafe[string, ton.TransactionId]),
+		},
+		nil
+}
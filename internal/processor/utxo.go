@@ -0,0 +1,382 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/chekist32/goipay/internal/daemon/utxo"
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/dto"
+	"github.com/chekist32/goipay/internal/listener"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+var (
+	utxoTxIndexDisabledErr error = errors.New("UTXO daemon must be run with -txindex enabled")
+)
+
+// utxoCryptoDataStore is the hdCryptoDataStore of a UTXO coin, the coins differ only in the queries of their crypto data table.
+type utxoCryptoDataStore struct {
+	cryptoDataId   func(cd *db.CryptoDatum) pgtype.UUID
+	keysAndLock    func(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error)
+	indicesAndLock func(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error)
+	setIndices     func(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) error
+}
+
+func (s *utxoCryptoDataStore) findCryptoDataId(cd *db.CryptoDatum) pgtype.UUID {
+	return s.cryptoDataId(cd)
+}
+
+func (s *utxoCryptoDataStore) findKeysAndLock(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error) {
+	return s.keysAndLock(ctx, q, id)
+}
+
+func (s *utxoCryptoDataStore) findIndicesAndLock(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error) {
+	return s.indicesAndLock(ctx, q, id)
+}
+
+func (s *utxoCryptoDataStore) updateIndices(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) error {
+	return s.setIndices(ctx, q, id, major, minor)
+}
+
+// utxoCoin is everything specific to a UTXO coin.
+type utxoCoin struct {
+	chain  *util.UtxoChainParams
+	daemon func(c *dto.DaemonsConfig) *dto.DaemonConfig
+	store  utxoCryptoDataStore
+}
+
+var utxoCoins = map[db.CoinType]*utxoCoin{
+	db.CoinTypeBTC: {
+		chain:  &util.BtcChainParams,
+		daemon: func(c *dto.DaemonsConfig) *dto.DaemonConfig { return &c.Btc },
+		store: utxoCryptoDataStore{
+			cryptoDataId: func(cd *db.CryptoDatum) pgtype.UUID { return cd.BtcID },
+			keysAndLock: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error) {
+				return q.FindKeysAndLockBTCCryptoDataById(ctx, id)
+			},
+			indicesAndLock: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error) {
+				indices, err := q.FindIndicesAndLockBTCCryptoDataById(ctx, id)
+				return indices.LastMajorIndex, indices.LastMinorIndex, err
+			},
+			setIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) error {
+				_, err := q.UpdateIndicesBTCCryptoDataById(ctx, db.UpdateIndicesBTCCryptoDataByIdParams{ID: id, LastMajorIndex: major, LastMinorIndex: minor})
+				return err
+			},
+		},
+	},
+	db.CoinTypeLTC: {
+		chain:  &util.LtcChainParams,
+		daemon: func(c *dto.DaemonsConfig) *dto.DaemonConfig { return &c.Ltc },
+		store: utxoCryptoDataStore{
+			cryptoDataId: func(cd *db.CryptoDatum) pgtype.UUID { return cd.LtcID },
+			keysAndLock: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error) {
+				return q.FindKeysAndLockLTCCryptoDataById(ctx, id)
+			},
+			indicesAndLock: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error) {
+				indices, err := q.FindIndicesAndLockLTCCryptoDataById(ctx, id)
+				return indices.LastMajorIndex, indices.LastMinorIndex, err
+			},
+			setIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) error {
+				_, err := q.UpdateIndicesLTCCryptoDataById(ctx, db.UpdateIndicesLTCCryptoDataByIdParams{ID: id, LastMajorIndex: major, LastMinorIndex: minor})
+				return err
+			},
+		},
+	},
+}
+
+// utxoProcessor handles invoices of bitcoind-like chains.
+// The coin specifics are limited to the chain params and the crypto data store.
+type utxoProcessor struct {
+	baseCryptoProcessor
+
+	coin  db.CoinType
+	chain *util.UtxoChainParams
+	store hdCryptoDataStore
+
+	daemon   utxo.IDaemonRpcClient
+	daemonEx *listener.UtxoDaemonRpcClientExecutor
+	network  *chaincfg.Params
+}
+
+func (p *utxoProcessor) verifyUtxoTxOutput(ctx context.Context, utxoTx *utxo.Tx, out *utxo.Vout, blockHeight pgtype.Int8, value pendingInvoice) {
+	payment := invoicePayment{
+		txId:        utxoTx.TxId,
+		outputIndex: int32(out.N),
+		amount:      new(big.Int).SetUint64(uint64(out.Value)),
+		blockHeight: blockHeight,
+	}
+	if recorded, err := p.recordPayment(ctx, value, payment); err != nil || !recorded {
+		return
+	}
+
+	p.confirmInvoiceHelper(ctx, value)
+}
+
+// verifyUtxoTx matches the outputs of the tx to pending invoices. The block height is not valid for mempool txs.
+func (p *utxoProcessor) verifyUtxoTx(ctx context.Context, utxoTx utxo.Tx, blockHeight pgtype.Int8) {
+	for i := 0; i < len(utxoTx.Vout); i++ {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			out := &utxoTx.Vout[i]
+
+			value, ok := p.pendingInvoices.Load(out.Address())
+			if !ok {
+				continue
+			}
+
+			p.verifyUtxoTxOutput(ctx, &utxoTx, out, blockHeight, value)
+		}
+	}
+}
+
+func (p *utxoProcessor) confirmInvoiceHelper(ctx context.Context, value pendingInvoice) {
+	invoice := value.invoice.Load()
+	if invoice.Status != db.InvoiceStatusTypePENDINGMEMPOOL {
+		return
+	}
+
+	txIds, err := p.findPaymentTxIds(ctx, invoice)
+	if err != nil {
+		return
+	}
+
+	confirmations := uint64(math.MaxUint64)
+	missedTxIds := make([]string, 0)
+	for i := 0; i < len(txIds); i++ {
+		utxoTx, err := p.daemon.GetRawTransaction(txIds[i])
+		if err != nil {
+			if utxo.IsRpcErrorCode(err, utxo.RPC_INVALID_ADDRESS_OR_KEY) {
+				p.log.Info().Msgf("Tx %v was rejected by blockchain", txIds[i])
+				missedTxIds = append(missedTxIds, txIds[i])
+				continue
+			}
+
+			p.log.Err(err).Str("coin", string(p.coin)).Str("method", "getrawtransaction").Msg(util.DefaultFailedFetchingUtxoDaemonMsg)
+			metrics.DaemonRpcErrorsTotal.WithLabelValues(string(p.coin), "getrawtransaction").Inc()
+			return
+		}
+
+		confirmations = min(confirmations, utxoTx.Confirmations)
+	}
+
+	if len(missedTxIds) > 0 {
+		updatedInvoice, paid, err := p.dropMissingPayments(ctx, invoice, missedTxIds)
+		if err != nil {
+			return
+		}
+		if !paid {
+			p.expireInvoice(ctx, updatedInvoice)
+			return
+		}
+	}
+
+	if !p.trackConfirmations(ctx, value, confirmations) {
+		return
+	}
+
+	p.confirmInvoice(ctx, value)
+}
+
+func (p *utxoProcessor) verifyUtxoTxOnNewBlock(ctx context.Context) {
+	p.pendingInvoices.Range(func(key string, value pendingInvoice) bool {
+		p.tasks.Go(func() { p.confirmInvoiceHelper(ctx, value) })
+		return true
+	})
+}
+
+func (p *utxoProcessor) persistCryptoCacheHelper(ctx context.Context) {
+	p.baseCryptoProcessor.persistCryptoCacheHelper(ctx, p.coin, p.daemonEx.LastSyncedBlockHeight())
+}
+
+func (p *utxoProcessor) Start(ctx context.Context) error {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return err
+	}
+
+	cache, err := q.FindCryptoCacheByCoin(ctx, p.coin)
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "FindCryptoCacheByCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		return err
+	}
+
+	height, err := p.daemon.GetBlockCount()
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("coin", string(p.coin)).Str("method", "getblockcount").Msg(util.DefaultFailedFetchingUtxoDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(p.coin), "getblockcount").Inc()
+		return err
+	}
+
+	if cache.LastSyncedBlockHeight.Valid {
+		height = uint64(cache.LastSyncedBlockHeight.Int64)
+	}
+
+	p.watchers.Go(func() {
+		p.watchers.Go(func() {
+			txPoolCn := p.daemonEx.NewTxPoolChan()
+
+			for {
+				select {
+				case res := <-txPoolCn:
+					p.tasks.Go(func() { p.verifyUtxoTx(ctx, res, pgtype.Int8{}) })
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+
+		p.watchers.Go(func() {
+			blockCn := p.daemonEx.NewBlockChan()
+
+			for {
+				select {
+				case res := <-blockCn:
+					p.tasks.Go(func() {
+						for i := 0; i < len(res.Tx); i++ {
+							p.tasks.Go(func() { p.verifyUtxoTx(ctx, res.Tx[i], pgtype.Int8{Int64: int64(res.Height), Valid: true}) })
+						}
+					})
+
+					p.tasks.Go(func() { p.verifyUtxoTxOnNewBlock(ctx) })
+
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+
+		p.persistCryptoCacheHelper(ctx)
+		for {
+			select {
+			case <-time.After(persist_cache_timeout):
+				p.tasks.Go(func() { p.persistCryptoCacheHelper(ctx) })
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	tx.Commit(ctx)
+
+	p.daemonEx.Start(height)
+	return nil
+}
+
+func (p *utxoProcessor) deriveAddress(masterPubKey string, major uint32, minor uint32) (string, error) {
+	key, err := p.chain.NewMasterPubKey(masterPubKey)
+	if err != nil {
+		return "", err
+	}
+
+	addr, err := p.chain.GenerateAddress(key, major, minor, p.network)
+	if err != nil {
+		return "", err
+	}
+
+	return addr.EncodeAddress(), nil
+}
+
+func (p *utxoProcessor) generateAddress(ctx context.Context, q *db.Queries, userId pgtype.UUID) (string, error) {
+	return p.generateHdAddress(ctx, q, userId, p.store, p.deriveAddress)
+}
+
+func (p *utxoProcessor) HandleNewInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
+	invoice, err := p.createInvoice(ctx, req, util.UTXO_DECIMALS, p.generateAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	p.handleInvoice(ctx, *invoice)
+
+	return invoice, nil
+}
+
+func (p *utxoProcessor) Stop() {
+	p.daemonEx.Stop()
+}
+
+func (p *utxoProcessor) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx, p.coin, p.daemonEx.LastSyncedBlockHeight())
+}
+
+func (p *utxoProcessor) Health() error {
+	_, err := p.daemon.GetBlockchainInfo()
+	return err
+}
+
+func (p *utxoProcessor) SyncLag() uint64 {
+	return p.daemonEx.SyncLag()
+}
+
+func (p *utxoProcessor) LastSyncedBlockHeight() uint64 {
+	return p.daemonEx.LastSyncedBlockHeight()
+}
+
+func (p *utxoProcessor) RecheckInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error) {
+	return p.recheckInvoice(ctx, invoice, p.confirmInvoiceHelper)
+}
+
+func (p *utxoProcessor) Assets() []dto.Asset {
+	return []dto.Asset{{Coin: p.coin, Symbol: string(p.coin), Decimals: uint32(util.UTXO_DECIMALS)}}
+}
+
+// newUtxoProcessorFactory returns the coinProcessorFactory of the coin listed in utxoCoins.
+func newUtxoProcessorFactory(coin db.CoinType) coinProcessorFactory {
+	return func(dbConnPool *pgxpool.Pool, invoiceCn chan<- dto.InvoiceEvent, c *dto.DaemonsConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (CoinProcessor, error) {
+		uc := utxoCoins[coin]
+		return newUtxoProcessor(dbConnPool, invoiceCn, coin, uc.chain, &uc.store, uc.daemon(c), ic, log)
+	}
+}
+
+func newUtxoProcessor(dbConnPool *pgxpool.Pool, invoiceCn chan<- dto.InvoiceEvent, coin db.CoinType, chain *util.UtxoChainParams, store hdCryptoDataStore, c *dto.DaemonConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (CoinProcessor, error) {
+	u, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	d := utxo.NewDaemonRpcClient(utxo.NewRpcConnection(u, c.User, c.Pass))
+
+	info, err := d.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	net, err := chain.NetworkParams(info.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	indexInfo, err := d.GetIndexInfo()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := indexInfo["txindex"]; !ok {
+		return nil, utxoTxIndexDisabledErr
+	}
+
+	return &utxoProcessor{
+			baseCryptoProcessor: newBaseCryptoProcessor(dbConnPool, invoiceCn, ic, log),
+			coin:                coin,
+			chain:               chain,
+			store:               store,
+			daemon:              d,
+			daemonEx:            listener.NewUtxoDaemonRpcClientExecutor(string(coin), d, log),
+			network:             net,
+		},
+		nil
+}
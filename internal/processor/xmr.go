@@ -2,33 +2,32 @@ package processor
 
 import (
 	"context"
-	"errors"
+	"math"
+	"math/big"
 	"net/url"
-	"sync/atomic"
 	"time"
 
 	"github.com/chekist32/go-monero/daemon"
 	"github.com/chekist32/go-monero/utils"
 	"github.com/chekist32/goipay/internal/db"
 	"github.com/chekist32/goipay/internal/dto"
+	"github.com/chekist32/goipay/internal/envelope"
 	"github.com/chekist32/goipay/internal/listener"
+	"github.com/chekist32/goipay/internal/metrics"
+	"github.com/chekist32/goipay/internal/tracing"
 	"github.com/chekist32/goipay/internal/util"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type pendingInvoice struct {
-	invoice           *atomic.Pointer[db.Invoice]
-	cancelTimeoutFunc context.CancelFunc
-}
-
 type incomingMoneroTx interface {
 	txInfo() daemon.MoneroTxInfo
 	confirmations() uint64
 	doubleSpendSeen() bool
 	txId() string
+	blockHeight() pgtype.Int8
 }
 
 type incomingMoneroTxTxPool daemon.MoneroTx
@@ -45,6 +44,9 @@ func (i incomingMoneroTxTxPool) doubleSpendSeen() bool {
 func (i incomingMoneroTxTxPool) txId() string {
 	return i.IdHash
 }
+func (i incomingMoneroTxTxPool) blockHeight() pgtype.Int8 {
+	return pgtype.Int8{}
+}
 
 type incomingMoneroTxGetTx daemon.MoneroTx1
 
@@ -60,24 +62,56 @@ func (i incomingMoneroTxGetTx) doubleSpendSeen() bool {
 func (i incomingMoneroTxGetTx) txId() string {
 	return i.TxHash
 }
+func (i incomingMoneroTxGetTx) blockHeight() pgtype.Int8 {
+	return pgtype.Int8{Int64: int64(i.BlockHeight), Valid: !i.InPool}
+}
 
 type xmrProcessor struct {
-	log *zerolog.Logger
-
-	dbConnPool *pgxpool.Pool
+	baseCryptoProcessor
 
 	daemon   daemon.IDaemonRpcClient
 	daemonEx *listener.DaemonRpcClientExecutor
 	network  utils.NetworkType
 
-	invoiceCn chan<- db.Invoice
+	keyring *envelope.Keyring
+}
+
+// decryptPrivViewKey returns the private view key in plaintext. The keys stored before the encryption was enabled aren't encrypted.
+func (p *xmrProcessor) decryptPrivViewKey(stored string) (string, error) {
+	if !envelope.IsEncrypted(stored) {
+		return stored, nil
+	}
+	if p.keyring == nil {
+		return "", MissingMasterKeyErr
+	}
+
+	return p.keyring.Decrypt(stored)
+}
+
+func (p *xmrProcessor) revealPrivViewKey(ctx context.Context, userId pgtype.UUID) (string, error) {
+	q := db.New(p.dbConnPool)
+
+	keys, err := q.FindCryptoKeysByUserId(ctx, userId)
+	if err != nil {
+		return "", err
+	}
 
-	pendingInvoices *util.SyncMapTypeSafe[string, pendingInvoice]
+	return p.decryptPrivViewKey(keys.PrivViewKey)
+}
+
+func (p *xmrProcessor) getTransactions(ctx context.Context, txIds []string) (*daemon.GetTransactionsResponse, error) {
+	return tracing.Call(ctx, string(db.CoinTypeXMR), "get_transactions", func() (*daemon.GetTransactionsResponse, error) {
+		return p.daemon.GetTransactions(txIds, true, false, false)
+	})
 }
 
 func (p *xmrProcessor) verifyMoneroTxOnTxMempool(ctx context.Context, xmrTx incomingMoneroTx) {
 	p.pendingInvoices.Range(func(key string, value pendingInvoice) bool {
-		go func() {
+		p.tasks.Go(func() {
+			ctx, span := startInvoiceSpan(ctx, "xmrProcessor.verifyMoneroTx", value)
+			span.SetAttributes(tracing.TX_ID_KEY.String(xmrTx.txId()))
+			defer span.End()
+
 			txInfo := xmrTx.txInfo()
 
 			q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
@@ -102,7 +136,14 @@ func (p *xmrProcessor) verifyMoneroTxOnTxMempool(ctx context.Context, xmrTx inco
 				return
 			}
 
-			privView, err := utils.NewPrivateKey(xmrKeys.PrivViewKey)
+			privViewKey, err := p.decryptPrivViewKey(xmrKeys.PrivViewKey)
+			if err != nil {
+				tx.Rollback(ctx)
+				p.log.Err(err).Msg("An error occurred while decrypting the XMR private view key.")
+				return
+			}
+
+			privView, err := utils.NewPrivateKey(privViewKey)
 			if err != nil {
 				tx.Rollback(ctx)
 				p.log.Err(err).Msg("An error occurred while creating the XMR private view key.")
@@ -124,6 +165,8 @@ func (p *xmrProcessor) verifyMoneroTxOnTxMempool(ctx context.Context, xmrTx inco
 				return
 			}
 
+			payments := make([]invoicePayment, 0, 1)
+
 			for i := 0; i < len(txInfo.Vout); i++ {
 				select {
 				case <-ctx.Done():
@@ -145,44 +188,31 @@ func (p *xmrProcessor) verifyMoneroTxOnTxMempool(ctx context.Context, xmrTx inco
 						p.log.Err(err).Msg("An error occurred while decrypting the XMR tx output.")
 						return
 					}
-					if !res ||
-						xmrTx.doubleSpendSeen() ||
-						value.invoice.Load().RequiredAmount > utils.XMRToFloat64(am) {
+					if !res || xmrTx.doubleSpendSeen() {
 						continue
 					}
 
-					var txId pgtype.Text
-					if err := txId.Scan(xmrTx.txId()); err != nil {
-						tx.Rollback(ctx)
-						p.log.Err(err).Str("fieldName", "txId").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
-						return
-					}
-
-					var amount pgtype.Float8
-					if err := amount.Scan(utils.XMRToFloat64(am)); err != nil {
-						tx.Rollback(ctx)
-						p.log.Err(err).Str("fieldName", "amount").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
-						return
-					}
-
-					invoice, err := q.ConfirmInvoiceStatusMempoolById(ctx, db.ConfirmInvoiceStatusMempoolByIdParams{ID: value.invoice.Load().ID, ActualAmount: amount, TxID: txId})
-					if err != nil {
-						tx.Rollback(ctx)
-						p.log.Err(err).Str("queryName", "ConfirmInvoiceStatusMempoolById").Msg(util.DefaultFailedSqlQueryMsg)
-						return
-					}
-
-					value.invoice.Store(&invoice)
+					payments = append(payments, invoicePayment{
+						txId:        xmrTx.txId(),
+						outputIndex: int32(i),
+						amount:      new(big.Int).SetUint64(am),
+						blockHeight: xmrTx.blockHeight(),
+					})
 				}
 			}
 			tx.Commit(ctx)
 
-			invoice = value.invoice.Load()
-			if invoice.Status != db.InvoiceStatusTypePENDING {
-				p.invoiceCn <- *invoice
+			paid := false
+			for i := 0; i < len(payments); i++ {
+				if recorded, _ := p.recordPayment(ctx, value, payments[i]); recorded {
+					paid = true
+				}
+			}
+
+			if paid && value.invoice.Load().Status == db.InvoiceStatusTypePENDINGMEMPOOL {
 				p.confirmInvoiceHelper(ctx, value)
 			}
-		}()
+		})
 
 		return true
 	})
@@ -190,57 +220,61 @@ func (p *xmrProcessor) verifyMoneroTxOnTxMempool(ctx context.Context, xmrTx inco
 
 func (p *xmrProcessor) confirmInvoiceHelper(ctx context.Context, value pendingInvoice) {
 	invoice := value.invoice.Load()
-	if !invoice.TxID.Valid {
+	if invoice.Status != db.InvoiceStatusTypePENDINGMEMPOOL {
 		return
 	}
 
-	xmrTx, err := p.daemon.GetTransactions([]string{invoice.TxID.String}, true, false, false)
+	ctx, span := startInvoiceSpan(ctx, "xmrProcessor.confirmInvoice", value)
+	defer span.End()
+
+	txIds, err := p.findPaymentTxIds(ctx, invoice)
 	if err != nil {
-		p.log.Err(err).Str("method", "get_transactions").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
-		return
-	}
-	if len(xmrTx.MissedTx) > 0 {
-		p.log.Info().Msgf("Tx %v was rejected by blockchain", invoice.TxID.String)
-		p.expireInvoice(ctx, invoice)
 		return
 	}
 
-	if uint64(invoice.ConfirmationsRequired) > xmrTx.Txs[0].Confirmations {
+	xmrTxs, err := p.getTransactions(ctx, txIds)
+	if err != nil {
+		p.log.Err(err).Str("method", "get_transactions").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_transactions").Inc()
 		return
 	}
-	if _, loaded := p.pendingInvoices.LoadAndDelete(invoice.CryptoAddress); !loaded {
-		return
+	if len(xmrTxs.MissedTx) > 0 {
+		p.log.Info().Msgf("Txs %v were rejected by blockchain", xmrTxs.MissedTx)
+		updatedInvoice, paid, err := p.dropMissingPayments(ctx, invoice, xmrTxs.MissedTx)
+		if err != nil {
+			return
+		}
+		if !paid {
+			if value.reorged {
+				p.reorgInvoice(ctx, updatedInvoice)
+				return
+			}
+			p.expireInvoice(ctx, updatedInvoice)
+			return
+		}
 	}
-	value.cancelTimeoutFunc()
 
-	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
-	if err != nil {
-		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
-		return
+	confirmations := uint64(math.MaxUint64)
+	for i := 0; i < len(xmrTxs.Txs); i++ {
+		confirmations = min(confirmations, xmrTxs.Txs[i].Confirmations)
 	}
 
-	confirmedInvoice, err := q.ConfirmInvoiceById(ctx, invoice.ID)
-	if err != nil {
-		tx.Rollback(ctx)
-		p.log.Err(err).Str("queryName", "ConfirmInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
+	if !p.trackConfirmations(ctx, value, confirmations) {
 		return
 	}
 
-	tx.Commit(ctx)
-
-	go p.releaseAddressHelper(ctx, invoice)
-
-	p.invoiceCn <- confirmedInvoice
+	p.confirmInvoice(ctx, value)
 }
 
 func (p *xmrProcessor) verifyMoneroTxOnNewBlock(ctx context.Context) {
 	p.pendingInvoices.Range(func(key string, value pendingInvoice) bool {
-		go p.confirmInvoiceHelper(ctx, value)
+		p.tasks.Go(func() { p.confirmInvoiceHelper(ctx, value) })
 		return true
 	})
 }
 
-func (p *xmrProcessor) persistCryptoCacheHelper(ctx context.Context) {
+// handleChainReorg re-verifies the invoices having payments in the orphaned blocks.
+func (p *xmrProcessor) handleChainReorg(ctx context.Context, reorg listener.ChainReorg) {
 	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
 	if err != nil {
 		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
@@ -248,22 +282,109 @@ func (p *xmrProcessor) persistCryptoCacheHelper(ctx context.Context) {
 	}
 
 	var height pgtype.Int8
-	if err := height.Scan(int64(p.daemonEx.LastSyncedBlockHeight())); err != nil {
+	if err := height.Scan(int64(reorg.ForkHeight)); err != nil {
 		tx.Rollback(ctx)
 		p.log.Err(err).Str("fieldName", "height").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
 		return
 	}
 
-	if _, err := q.UpdateCryptoCacheByCoin(ctx, db.UpdateCryptoCacheByCoinParams{Coin: db.CoinTypeXMR, LastSyncedBlockHeight: height}); err != nil {
+	// The payments are unconfirmed until their txs are seen on the new chain
+	payments, err := q.ResetBlockHeightInvoicePaymentsByCoinFromBlockHeight(ctx, db.ResetBlockHeightInvoicePaymentsByCoinFromBlockHeightParams{Coin: db.CoinTypeXMR, BlockHeight: height})
+	if err != nil {
+		tx.Rollback(ctx)
+		p.log.Err(err).Str("queryName", "ResetBlockHeightInvoicePaymentsByCoinFromBlockHeight").Msg(util.DefaultFailedSqlQueryMsg)
+		return
+	}
+
+	ids := make([]pgtype.UUID, 0, len(payments))
+	seen := make(map[[16]byte]bool, len(payments))
+	for i := 0; i < len(payments); i++ {
+		if seen[payments[i].InvoiceID.Bytes] {
+			continue
+		}
+		seen[payments[i].InvoiceID.Bytes] = true
+		ids = append(ids, payments[i].InvoiceID)
+	}
+
+	invoices, err := q.FindAllInvoicesByIds(ctx, ids)
+	if err != nil {
 		tx.Rollback(ctx)
-		p.log.Err(err).Str("queryName", "UpdateCryptoCacheByCoin").Msg(util.DefaultFailedSqlQueryMsg)
+		p.log.Err(err).Str("queryName", "FindAllInvoicesByIds").Msg(util.DefaultFailedSqlQueryMsg)
 		return
 	}
 
 	tx.Commit(ctx)
+
+	for i := 0; i < len(invoices); i++ {
+		p.tasks.Go(func() { p.reverifyInvoiceHelper(ctx, invoices[i]) })
+	}
 }
 
-func (p *xmrProcessor) load(ctx context.Context) error {
+// reverifyInvoiceHelper checks whether the payment txs of the invoice survived the reorg.
+// A CONFIRMED invoice goes back to PENDING_MEMPOOL if they did, and a paid one becomes REORGED otherwise.
+func (p *xmrProcessor) reverifyInvoiceHelper(ctx context.Context, invoice db.Invoice) {
+	ctx, span := tracing.Tracer().Start(ctx, "xmrProcessor.reverifyInvoice", trace.WithAttributes(tracing.INVOICE_ID_KEY.String(util.PgUUIDToString(invoice.ID))))
+	defer span.End()
+
+	txIds, err := p.findPaymentTxIds(ctx, &invoice)
+	if err != nil {
+		return
+	}
+
+	xmrTxs, err := p.getTransactions(ctx, txIds)
+	if err != nil {
+		p.log.Err(err).Str("method", "get_transactions").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_transactions").Inc()
+		return
+	}
+
+	if len(xmrTxs.MissedTx) > 0 {
+		p.log.Info().Str("invoiceId", util.PgUUIDToString(invoice.ID)).Msgf("Txs %v were orphaned by the reorg", xmrTxs.MissedTx)
+		updatedInvoice, paid, err := p.dropMissingPayments(ctx, &invoice, xmrTxs.MissedTx)
+		if err != nil {
+			return
+		}
+		if !paid {
+			if isPaid(&invoice) {
+				p.reorgInvoice(ctx, updatedInvoice)
+			}
+			return
+		}
+		invoice = *updatedInvoice
+	}
+
+	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+	if err != nil {
+		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return
+	}
+
+	// The txs might have been already mined on the new chain
+	for i := 0; i < len(xmrTxs.Txs); i++ {
+		blockHeight := incomingMoneroTxGetTx(xmrTxs.Txs[i]).blockHeight()
+		if !blockHeight.Valid {
+			continue
+		}
+
+		if _, err := q.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId(ctx, db.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxIdParams{InvoiceID: invoice.ID, TxID: xmrTxs.Txs[i].TxHash, BlockHeight: blockHeight}); err != nil {
+			tx.Rollback(ctx)
+			p.log.Err(err).Str("queryName", "UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId").Msg(util.DefaultFailedSqlQueryMsg)
+			return
+		}
+	}
+
+	tx.Commit(ctx)
+
+	if invoice.Status == db.InvoiceStatusTypeCONFIRMED {
+		p.unconfirmInvoice(ctx, &invoice)
+	}
+}
+
+func (p *xmrProcessor) persistCryptoCacheHelper(ctx context.Context) {
+	p.baseCryptoProcessor.persistCryptoCacheHelper(ctx, db.CoinTypeXMR, p.daemonEx.LastSyncedBlockHeight())
+}
+
+func (p *xmrProcessor) Start(ctx context.Context) error {
 	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
 	if err != nil {
 		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
@@ -281,6 +402,7 @@ func (p *xmrProcessor) load(ctx context.Context) error {
 	if err != nil {
 		tx.Rollback(ctx)
 		p.log.Err(err).Str("method", "get_last_block_header").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+		metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_last_block_header").Inc()
 		return err
 	}
 
@@ -289,57 +411,71 @@ func (p *xmrProcessor) load(ctx context.Context) error {
 		height = cache.LastSyncedBlockHeight.Int64
 	}
 
-	go func() {
-		go func() {
+	p.watchers.Go(func() {
+		p.watchers.Go(func() {
 			txPoolCn := p.daemonEx.NewTxPoolChan()
 
 			for {
 				select {
 				case res := <-txPoolCn:
-					go p.verifyMoneroTxOnTxMempool(ctx, incomingMoneroTxTxPool(res))
+					p.tasks.Go(func() { p.verifyMoneroTxOnTxMempool(ctx, incomingMoneroTxTxPool(res)) })
 				case <-ctx.Done():
 					return
 				}
 			}
-		}()
+		})
 
-		go func() {
+		p.watchers.Go(func() {
 			blockCn := p.daemonEx.NewBlockChan()
 
 			for {
 				select {
 				case res := <-blockCn:
-					go func() {
-						txsRes, err := p.daemon.GetTransactions(res.BlockDetails.TxHashes, true, false, false)
+					p.tasks.Go(func() {
+						txsRes, err := p.getTransactions(ctx, res.BlockDetails.TxHashes)
 						if err != nil {
 							p.log.Err(err).Str("method", "get_transactions").Msg(util.DefaultFailedFetchingXMRDaemonMsg)
+							metrics.DaemonRpcErrorsTotal.WithLabelValues(string(db.CoinTypeXMR), "get_transactions").Inc()
 							return
 						}
 
 						for i := 0; i < len(txsRes.Txs); i++ {
-							go p.verifyMoneroTxOnTxMempool(ctx, incomingMoneroTxGetTx(txsRes.Txs[i]))
+							p.tasks.Go(func() { p.verifyMoneroTxOnTxMempool(ctx, incomingMoneroTxGetTx(txsRes.Txs[i])) })
 						}
 
-					}()
+					})
 
-					go p.verifyMoneroTxOnNewBlock(ctx)
+					p.tasks.Go(func() { p.verifyMoneroTxOnNewBlock(ctx) })
 
 				case <-ctx.Done():
 					return
 				}
 			}
-		}()
+		})
+
+		p.watchers.Go(func() {
+			reorgCn := p.daemonEx.NewReorgChan()
+
+			for {
+				select {
+				case reorg := <-reorgCn:
+					p.tasks.Go(func() { p.handleChainReorg(ctx, reorg) })
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
 
 		p.persistCryptoCacheHelper(ctx)
 		for {
 			select {
 			case <-time.After(persist_cache_timeout):
-				go p.persistCryptoCacheHelper(ctx)
+				p.tasks.Go(func() { p.persistCryptoCacheHelper(ctx) })
 			case <-ctx.Done():
 				return
 			}
 		}
-	}()
+	})
 
 	tx.Commit(ctx)
 
@@ -347,116 +483,56 @@ func (p *xmrProcessor) load(ctx context.Context) error {
 	return nil
 }
 
-func (p *xmrProcessor) createInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
-	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
+func (p *xmrProcessor) generateSubaddress(ctx context.Context, q *db.Queries, userId pgtype.UUID) (string, error) {
+	cd, err := q.FindCryptoDataByUserId(ctx, userId)
 	if err != nil {
-		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
-		return nil, err
+		return "", err
 	}
 
-	var userId pgtype.UUID
-	if err := userId.Scan(req.UserId); err != nil {
-		tx.Rollback(ctx)
-		return nil, err
+	indices, err := q.FindIndicesAndLockXMRCryptoDataById(ctx, cd.XmrID)
+	if err != nil {
+		return "", err
 	}
 
-	coin := req.Coin
-
-	timeout := time.Duration(req.Timeout) * time.Second
-	if timeout < listener.MIN_SYNC_TIMEOUT {
-		timeout = listener.MIN_SYNC_TIMEOUT
+	keys, err := q.FindKeysAndLockXMRCryptoDataById(ctx, cd.XmrID)
+	if err != nil {
+		return "", err
 	}
 
-	var expiresAt pgtype.Timestamptz
-	if err := expiresAt.Scan(time.Now().UTC().Add(timeout)); err != nil {
-		tx.Rollback(ctx)
-		return nil, err
+	privViewKey, err := p.decryptPrivViewKey(keys.PrivViewKey)
+	if err != nil {
+		return "", err
 	}
 
-	addr, err := q.FindNonOccupiedCryptoAddressAndLockByUserIdAndCoin(ctx, db.FindNonOccupiedCryptoAddressAndLockByUserIdAndCoinParams{UserID: userId, Coin: coin})
+	viewKey, err := utils.NewPrivateKey(privViewKey)
 	if err != nil {
-		if !errors.Is(err, pgx.ErrNoRows) {
-			tx.Rollback(ctx)
-			return nil, err
-		}
-
-		cd, err := q.FindCryptoDataByUserId(ctx, userId)
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
-
-		indices, err := q.FindIndicesAndLockXMRCryptoDataById(ctx, cd.XmrID)
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
-
-		keys, err := q.FindKeysAndLockXMRCryptoDataById(ctx, cd.XmrID)
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
-
-		viewKey, err := utils.NewPrivateKey(keys.PrivViewKey)
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
-
-		spendKey, err := utils.NewPublicKey(keys.PubSpendKey)
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
-
-		indices.LastMinorIndex++
-		if indices.LastMinorIndex == 0 {
-			indices.LastMajorIndex++
-		}
-
-		subAddr, err := utils.GenerateSubaddress(viewKey, spendKey, uint32(indices.LastMajorIndex), uint32(indices.LastMinorIndex), p.network)
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
+		return "", err
+	}
 
-		addr, err = q.CreateCryptoAddress(ctx, db.CreateCryptoAddressParams{Address: subAddr.Address(), Coin: db.CoinTypeXMR, IsOccupied: true, UserID: userId})
-		if err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
+	spendKey, err := utils.NewPublicKey(keys.PubSpendKey)
+	if err != nil {
+		return "", err
+	}
 
-		if _, err := q.UpdateIndicesXMRCryptoDataById(ctx, db.UpdateIndicesXMRCryptoDataByIdParams{ID: cd.XmrID, LastMajorIndex: indices.LastMajorIndex, LastMinorIndex: indices.LastMinorIndex}); err != nil {
-			tx.Rollback(ctx)
-			return nil, err
-		}
+	indices.LastMinorIndex++
+	if indices.LastMinorIndex == 0 {
+		indices.LastMajorIndex++
 	}
 
-	invoice, err := q.CreateInvoice(
-		ctx,
-		db.CreateInvoiceParams{
-			CryptoAddress:         addr.Address,
-			Coin:                  coin,
-			RequiredAmount:        req.Amount,
-			ConfirmationsRequired: int16(req.Confirmations),
-			ExpiresAt:             expiresAt,
-			UserID:                userId,
-		},
-	)
+	subAddr, err := utils.GenerateSubaddress(viewKey, spendKey, uint32(indices.LastMajorIndex), uint32(indices.LastMinorIndex), p.network)
 	if err != nil {
-		tx.Rollback(ctx)
-		p.log.Err(err).Str("queryName", "CreateInvoice").Msg(util.DefaultFailedSqlQueryMsg)
-		return nil, err
+		return "", err
 	}
 
-	tx.Commit(ctx)
+	if _, err := q.UpdateIndicesXMRCryptoDataById(ctx, db.UpdateIndicesXMRCryptoDataByIdParams{ID: cd.XmrID, LastMajorIndex: indices.LastMajorIndex, LastMinorIndex: indices.LastMinorIndex}); err != nil {
+		return "", err
+	}
 
-	return &invoice, nil
+	return subAddr.Address(), nil
 }
 
-func (p *xmrProcessor) handleInvoicePbReq(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
-	invoice, err := p.createInvoice(ctx, req)
+func (p *xmrProcessor) HandleNewInvoice(ctx context.Context, req *dto.NewInvoiceRequest) (*db.Invoice, error) {
+	invoice, err := p.createInvoice(ctx, req, util.XMR_DECIMALS, p.generateSubaddress)
 	if err != nil {
 		return nil, err
 	}
@@ -466,72 +542,36 @@ func (p *xmrProcessor) handleInvoicePbReq(ctx context.Context, req *dto.NewInvoi
 	return invoice, nil
 }
 
-// TODO: Make it shared
-func (p *xmrProcessor) releaseAddressHelper(ctx context.Context, invoice *db.Invoice) {
-	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
-	if err != nil {
-		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
-		return
-	}
-
-	if _, err := q.UpdateIsOccupiedByCryptoAddress(ctx, db.UpdateIsOccupiedByCryptoAddressParams{IsOccupied: false, Address: invoice.CryptoAddress}); err != nil {
-		tx.Rollback(ctx)
-		p.log.Err(err).Str("queryName", "UpdateIsOccupiedByCryptoAddress").Msg(util.DefaultFailedSqlQueryMsg)
-		return
-	}
-
-	tx.Commit(ctx)
+func (p *xmrProcessor) Stop() {
+	p.daemonEx.Stop()
 }
 
-func (p *xmrProcessor) expireInvoice(ctx context.Context, invoice *db.Invoice) {
-	if _, loaded := p.pendingInvoices.LoadAndDelete(invoice.CryptoAddress); !loaded {
-		return
-	}
-
-	q, tx, err := util.InitDbQueriesWithTx(ctx, p.dbConnPool)
-	if err != nil {
-		p.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
-		return
-	}
-
-	expiredInvoice, err := q.ExpireInvoiceById(ctx, invoice.ID)
-	if err != nil {
-		tx.Rollback(ctx)
-		p.log.Err(err).Str("queryName", "ExpireInvoiceById").Msg(util.DefaultFailedSqlQueryMsg)
-		return
-	}
-
-	tx.Commit(ctx)
-
-	go p.releaseAddressHelper(ctx, invoice)
+func (p *xmrProcessor) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx, db.CoinTypeXMR, p.daemonEx.LastSyncedBlockHeight())
+}
 
-	p.invoiceCn <- expiredInvoice
+func (p *xmrProcessor) Health() error {
+	_, err := p.daemon.GetInfo()
+	return err
 }
 
-func (p *xmrProcessor) handleInvoiceHelper(confirmedInvoiceCtx context.Context, invoice *db.Invoice) {
-	select {
-	case <-time.After(invoice.ExpiresAt.Time.Sub(time.Now().UTC())):
-		p.expireInvoice(confirmedInvoiceCtx, invoice)
-		return
-	case <-confirmedInvoiceCtx.Done():
-		return
-	}
+func (p *xmrProcessor) SyncLag() uint64 {
+	return p.daemonEx.SyncLag()
 }
-func (p *xmrProcessor) handleInvoice(ctx context.Context, invoice db.Invoice) {
-	if _, ok := p.pendingInvoices.Load(invoice.CryptoAddress); ok {
-		return
-	}
 
-	confirmedInvoiceCtx, cancel := context.WithCancel(ctx)
+func (p *xmrProcessor) LastSyncedBlockHeight() uint64 {
+	return p.daemonEx.LastSyncedBlockHeight()
+}
 
-	invoicePtr := &atomic.Pointer[db.Invoice]{}
-	invoicePtr.Store(&invoice)
-	p.pendingInvoices.Store(invoice.CryptoAddress, pendingInvoice{invoice: invoicePtr, cancelTimeoutFunc: cancel})
+func (p *xmrProcessor) RecheckInvoice(ctx context.Context, invoice db.Invoice) (*db.Invoice, error) {
+	return p.recheckInvoice(ctx, invoice, p.confirmInvoiceHelper)
+}
 
-	go p.handleInvoiceHelper(confirmedInvoiceCtx, &invoice)
+func (p *xmrProcessor) Assets() []dto.Asset {
+	return []dto.Asset{{Coin: db.CoinTypeXMR, Symbol: string(db.CoinTypeXMR), Decimals: uint32(util.XMR_DECIMALS)}}
 }
 
-func newXmrProcessor(dbConnPool *pgxpool.Pool, invoiceCn chan<- db.Invoice, c *dto.DaemonsConfig, log *zerolog.Logger) (*xmrProcessor, error) {
+func newXmrProcessor(dbConnPool *pgxpool.Pool, invoiceCn chan<- dto.InvoiceEvent, c *dto.DaemonsConfig, ic *dto.InvoiceConfig, log *zerolog.Logger) (CoinProcessor, error) {
 	u, err := url.Parse(c.Xmr.Url)
 	if err != nil {
 		return nil, err
@@ -552,13 +592,56 @@ func newXmrProcessor(dbConnPool *pgxpool.Pool, invoiceCn chan<- db.Invoice, c *d
 	}
 
 	return &xmrProcessor{
-			log:             log,
-			dbConnPool:      dbConnPool,
-			daemon:          d,
-			daemonEx:        listener.NewDaemonRpcClientExecutor(d, log),
-			network:         net,
-			invoiceCn:       invoiceCn,
-			pendingInvoices: new(util.SyncMapTypeSafe[string, pendingInvoice]),
+			baseCryptoProcessor: newBaseCryptoProcessor(dbConnPool, invoiceCn, ic, log),
+			daemon:              d,
+			daemonEx:            listener.NewDaemonRpcClientExecutor(d, log),
+			network:             net,
+			keyring:             c.XmrKeyring,
 		},
 		nil
 }
+
+// RotateXmrPrivViewKeys rewraps the stored XMR private view keys with the current master key of the keyring
+// and encrypts the ones still stored in plaintext. It returns the number of updated keys.
+func RotateXmrPrivViewKeys(ctx context.Context, dbConnPool *pgxpool.Pool, keyring *envelope.Keyring) (int, error) {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, dbConnPool)
+	if err != nil {
+		return 0, err
+	}
+
+	keys, err := q.FindAllPrivViewKeysAndLockXMRCryptoData(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		return 0, err
+	}
+
+	updated := 0
+	for i := 0; i < len(keys); i++ {
+		var privViewKey string
+		changed := true
+		if envelope.IsEncrypted(keys[i].PrivViewKey) {
+			privViewKey, changed, err = keyring.Rewrap(keys[i].PrivViewKey)
+		} else {
+			privViewKey, err = keyring.Encrypt(keys[i].PrivViewKey)
+		}
+		if err != nil {
+			tx.Rollback(ctx)
+			return 0, err
+		}
+		if !changed {
+			continue
+		}
+
+		if _, err := q.UpdatePrivViewKeyXMRCryptoDataById(ctx, db.UpdatePrivViewKeyXMRCryptoDataByIdParams{ID: keys[i].ID, PrivViewKey: privViewKey}); err != nil {
+			tx.Rollback(ctx)
+			return 0, err
+		}
+		updated++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
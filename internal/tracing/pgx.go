@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TX_SPAN_NAME string = "db.transaction"
+
+	sqlc_query_name_prefix string = "-- name: "
+)
+
+var dbSystemAttr attribute.KeyValue = attribute.String("db.system", "postgresql")
+
+// queryName returns the sqlc query name, e.g. "CreateInvoice", or the first keyword of the other statements.
+func queryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if strings.HasPrefix(sql, sqlc_query_name_prefix) {
+		if fields := strings.Fields(strings.TrimPrefix(sql, sqlc_query_name_prefix)); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+
+	return "query"
+}
+
+// QueryTracer starts a span for every query named after it, set it as the pgx.ConnConfig.Tracer.
+type QueryTracer struct{}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name := queryName(data.SQL)
+	ctx, _ = Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(dbSystemAttr, QUERY_NAME_KEY.String(name)),
+	)
+
+	return ctx
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	End(trace.SpanFromContext(ctx), data.Err)
+}
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{}
+}
+
+// tx runs the queries as children of the transaction span, which ends once the tx is committed or rolled back.
+type tx struct {
+	pgx.Tx
+
+	span    trace.Span
+	endOnce sync.Once
+}
+
+func (t *tx) withSpan(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(ctx, t.span)
+}
+
+func (t *tx) end(err error) {
+	t.endOnce.Do(func() { End(t.span, err) })
+}
+
+func (t *tx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return t.Tx.Exec(t.withSpan(ctx), sql, arguments...)
+}
+
+func (t *tx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.Tx.Query(t.withSpan(ctx), sql, args...)
+}
+
+func (t *tx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.Tx.QueryRow(t.withSpan(ctx), sql, args...)
+}
+
+func (t *tx) Commit(ctx context.Context) error {
+	err := t.Tx.Commit(t.withSpan(ctx))
+	t.end(err)
+	return err
+}
+
+func (t *tx) Rollback(ctx context.Context) error {
+	err := t.Tx.Rollback(t.withSpan(ctx))
+	t.span.SetAttributes(attribute.Bool("db.rollback", true))
+	t.end(nil)
+	return err
+}
+
+// Beginner is implemented by pgxpool.Pool and pgx.Conn.
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// BeginTx begins a transaction traced by a span which ends once it's committed or rolled back.
+func BeginTx(ctx context.Context, b Beginner) (pgx.Tx, error) {
+	ctx, span := Tracer().Start(ctx, TX_SPAN_NAME, trace.WithAttributes(dbSystemAttr))
+
+	t, err := b.Begin(ctx)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+
+	return &tx{Tx: t, span: span}, nil
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TRACER_NAME  string = "github.com/chekist32/goipay"
+	SERVICE_NAME string = "goipay"
+
+	INVOICE_ID_KEY attribute.Key = "goipay.invoice.id"
+	TX_ID_KEY      attribute.Key = "goipay.tx.id"
+	COIN_KEY       attribute.Key = "goipay.coin"
+	QUERY_NAME_KEY attribute.Key = "db.operation"
+	RPC_METHOD_KEY attribute.Key = "rpc.method"
+)
+
+// Tracer returns the goipay tracer of the global provider, spans are dropped until a provider is set with NewTracerProvider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TRACER_NAME)
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Call runs the daemon RPC call in a client span named "<coin>.<method>".
+func Call[T any](ctx context.Context, coin string, method string, call func() (T, error)) (T, error) {
+	_, span := Tracer().Start(ctx, coin+"."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(COIN_KEY.String(coin), RPC_METHOD_KEY.String(method)),
+	)
+
+	res, err := call()
+	End(span, err)
+
+	return res, err
+}
+
+// NewTracerProvider creates a provider batching the spans to the exporter and sets it as the global one
+// along with the W3C trace context propagator. Tests can pass an in-memory exporter, see tracetest.NewInMemoryExporter.
+func NewTracerProvider(exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", SERVICE_NAME))),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp
+}
+
+// NewOtlpTracerProvider creates the global provider exporting the spans over OTLP/gRPC to the endpoint (host:port).
+func NewOtlpTracerProvider(ctx context.Context, endpoint string, insecure bool) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTracerProvider(exporter), nil
+}
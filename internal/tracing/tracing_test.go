@@ -0,0 +1,138 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type fakeTx struct {
+	pgx.Tx
+}
+
+// Exec mimics pgx, which calls the QueryTracer of the connection.
+func (t *fakeTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	tracer := NewQueryTracer()
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: sql})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	return pgconn.CommandTag{}, nil
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	return nil
+}
+
+type fakeBeginner struct{}
+
+func (b *fakeBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+func setupTracer(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := NewTracerProvider(exporter)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	return tp, exporter
+}
+
+func findSpan(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := 0; i < len(spans); i++ {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+func TestQueryName(t *testing.T) {
+	tests := map[string]string{
+		"-- name: CreateInvoice :one\nINSERT INTO invoices": "CreateInvoice",
+		"\n  -- name: FindAllPendingInvoices :many\nSELECT": "FindAllPendingInvoices",
+		"begin":  "BEGIN",
+		"commit": "COMMIT",
+		"":       "query",
+	}
+
+	for sql, expected := range tests {
+		assert.Equal(t, expected, queryName(sql))
+	}
+}
+
+func TestCall(t *testing.T) {
+	tp, exporter := setupTracer(t)
+	ctx := context.Background()
+	expectedErr := errors.New("daemon is down")
+
+	res, err := Call(ctx, "XMR", "get_block", func() (int, error) { return 1, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res)
+
+	_, err = Call(ctx, "XMR", "get_transactions", func() (int, error) { return 0, expectedErr })
+	assert.ErrorIs(t, err, expectedErr)
+
+	assert.NoError(t, tp.ForceFlush(ctx))
+	spans := exporter.GetSpans()
+
+	span := findSpan(spans, "XMR.get_block")
+	if assert.NotNil(t, span) {
+		assert.Equal(t, codes.Unset, span.Status.Code)
+		assert.Contains(t, span.Attributes, RPC_METHOD_KEY.String("get_block"))
+	}
+
+	span = findSpan(spans, "XMR.get_transactions")
+	if assert.NotNil(t, span) {
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Equal(t, expectedErr.Error(), span.Status.Description)
+	}
+}
+
+func TestBeginTx(t *testing.T) {
+	tp, exporter := setupTracer(t)
+	ctx := context.Background()
+
+	tx, err := BeginTx(ctx, &fakeBeginner{})
+	assert.NoError(t, err)
+
+	// The query is a child of the tx span even though ctx doesn't carry it
+	_, err = tx.Exec(ctx, "-- name: UpdateInvoice :one\nUPDATE invoices")
+	assert.NoError(t, err)
+
+	assert.NoError(t, tp.ForceFlush(ctx))
+	assert.Nil(t, findSpan(exporter.GetSpans(), TX_SPAN_NAME))
+
+	assert.NoError(t, tx.Commit(ctx))
+	// A Rollback after the Commit doesn't end the span twice
+	assert.NoError(t, tx.Rollback(ctx))
+
+	assert.NoError(t, tp.ForceFlush(ctx))
+	spans := exporter.GetSpans()
+
+	txSpan := findSpan(spans, TX_SPAN_NAME)
+	querySpan := findSpan(spans, "UpdateInvoice")
+	if assert.NotNil(t, txSpan) && assert.NotNil(t, querySpan) {
+		assert.Equal(t, txSpan.SpanContext.SpanID(), querySpan.Parent.SpanID())
+		assert.Equal(t, txSpan.SpanContext.TraceID(), querySpan.SpanContext.TraceID())
+		assert.Contains(t, querySpan.Attributes, QUERY_NAME_KEY.String("UpdateInvoice"))
+	}
+
+	count := 0
+	for i := 0; i < len(spans); i++ {
+		if spans[i].Name == TX_SPAN_NAME {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+}
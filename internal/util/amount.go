@@ -0,0 +1,98 @@
+package util
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	XMR_DECIMALS  int = 12
+	UTXO_DECIMALS int = 8
+)
+
+var (
+	invalidAmountErr error = errors.New("invalid amount")
+)
+
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
+
+// FloatToAtomicUnits converts the amount to the smallest units of a coin (e.g. wei) using
+// its shortest decimal representation, so 0.1 becomes exactly 10^17 wei.
+func FloatToAtomicUnits(amount float64, decimals int) (*big.Int, error) {
+	if amount < 0 {
+		return nil, invalidAmountErr
+	}
+
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(amount, 'f', -1, 64), ".")
+	if len(frac) > decimals {
+		return nil, invalidAmountErr
+	}
+
+	units, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return nil, invalidAmountErr
+	}
+
+	return units, nil
+}
+
+// AtomicUnitsToFloat converts the smallest units of a coin (e.g. wei) to the nearest float64.
+func AtomicUnitsToFloat(units *big.Int, decimals int) float64 {
+	res, _ := new(big.Float).Quo(new(big.Float).SetInt(units), new(big.Float).SetInt(pow10(decimals))).Float64()
+	return res
+}
+
+// ParseAtomicUnits parses a non-negative decimal integer amount of atomic units.
+func ParseAtomicUnits(amount string) (*big.Int, error) {
+	units, ok := new(big.Int).SetString(amount, 10)
+	if !ok || units.Sign() < 0 || strings.HasPrefix(amount, "+") {
+		return nil, invalidAmountErr
+	}
+
+	return units, nil
+}
+
+func BigIntToPgNumeric(units *big.Int) pgtype.Numeric {
+	return pgtype.Numeric{Int: new(big.Int).Set(units), Exp: 0, Valid: true}
+}
+
+// PgNumericToBigInt converts an integral NUMERIC to big.Int, NULL becomes 0.
+func PgNumericToBigInt(n pgtype.Numeric) (*big.Int, error) {
+	if !n.Valid {
+		return new(big.Int), nil
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return nil, invalidAmountErr
+	}
+
+	if n.Exp >= 0 {
+		return new(big.Int).Mul(n.Int, pow10(int(n.Exp))), nil
+	}
+
+	res, rem := new(big.Int).QuoRem(n.Int, pow10(int(-n.Exp)), new(big.Int))
+	if rem.Sign() != 0 {
+		return nil, invalidAmountErr
+	}
+
+	return res, nil
+}
+
+// PercentOfAtomicUnits returns the percent of the units rounded down to a whole atomic unit.
+func PercentOfAtomicUnits(units *big.Int, percent float64) (*big.Int, error) {
+	if math.IsNaN(percent) || percent < 0 || percent > 100 {
+		return nil, invalidAmountErr
+	}
+
+	res := new(big.Rat).SetFloat64(percent)
+	res.Mul(res, new(big.Rat).SetInt(units))
+	res.Quo(res, big.NewRat(100, 1))
+
+	return new(big.Int).Quo(res.Num(), res.Denom()), nil
+}
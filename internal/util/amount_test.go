@@ -0,0 +1,112 @@
+package util
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloatToAtomicUnits(t *testing.T) {
+	t.Parallel()
+
+	units, err := FloatToAtomicUnits(0.1, ETH_DECIMALS)
+	assert.NoError(t, err)
+	assert.Equal(t, "100000000000000000", units.String())
+
+	units, err = FloatToAtomicUnits(1.000000000000000001, ETH_DECIMALS)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000000000000000000", units.String())
+
+	units, err = FloatToAtomicUnits(12.5, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, "12500000", units.String())
+
+	units, err = FloatToAtomicUnits(0.000000000001, XMR_DECIMALS)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", units.String())
+
+	_, err = FloatToAtomicUnits(0.0000001, 6)
+	assert.ErrorIs(t, err, invalidAmountErr)
+
+	_, err = FloatToAtomicUnits(-1, ETH_DECIMALS)
+	assert.ErrorIs(t, err, invalidAmountErr)
+
+	wei, _ := new(big.Int).SetString("1500000000000000000", 10)
+	assert.Equal(t, 1.5, AtomicUnitsToFloat(wei, ETH_DECIMALS))
+}
+
+func TestParseAtomicUnits(t *testing.T) {
+	t.Parallel()
+
+	units, err := ParseAtomicUnits("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	assert.NoError(t, err)
+	assert.Equal(t, "115792089237316195423570985008687907853269984665640564039457584007913129639935", units.String())
+
+	for _, amount := range []string{"", "-1", "+1", "1.5", "1e18", "0x10"} {
+		_, err := ParseAtomicUnits(amount)
+		assert.ErrorIs(t, err, invalidAmountErr, amount)
+	}
+}
+
+func TestPgNumericToBigInt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should Round Trip", func(t *testing.T) {
+		units, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+		res, err := PgNumericToBigInt(BigIntToPgNumeric(units))
+		assert.NoError(t, err)
+		assert.Equal(t, units, res)
+	})
+
+	t.Run("Should Apply Exponent", func(t *testing.T) {
+		res, err := PgNumericToBigInt(pgtype.Numeric{Int: big.NewInt(15), Exp: 3, Valid: true})
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(15000), res)
+
+		res, err = PgNumericToBigInt(pgtype.Numeric{Int: big.NewInt(15000), Exp: -3, Valid: true})
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(15), res)
+	})
+
+	t.Run("Should Return Zero For NULL", func(t *testing.T) {
+		res, err := PgNumericToBigInt(pgtype.Numeric{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, res.Sign())
+	})
+
+	t.Run("Should Return Error (fractional)", func(t *testing.T) {
+		_, err := PgNumericToBigInt(pgtype.Numeric{Int: big.NewInt(15), Exp: -1, Valid: true})
+		assert.ErrorIs(t, err, invalidAmountErr)
+	})
+}
+
+func TestPercentOfAtomicUnits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		units    int64
+		percent  float64
+		expected int64
+	}{
+		{units: 1_000_000_000_000, percent: 1, expected: 10_000_000_000},
+		{units: 1_000_000_000_000, percent: 0.5, expected: 5_000_000_000},
+		{units: 999, percent: 10, expected: 99},
+		{units: 1_000, percent: 0, expected: 0},
+		{units: 1_000, percent: 100, expected: 1_000},
+	}
+
+	for _, tt := range tests {
+		res, err := PercentOfAtomicUnits(big.NewInt(tt.units), tt.percent)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(tt.expected), res, "%v%% of %v", tt.percent, tt.units)
+	}
+
+	for _, percent := range []float64{-1, 100.5, math.NaN(), math.Inf(1)} {
+		_, err := PercentOfAtomicUnits(big.NewInt(1_000), percent)
+		assert.ErrorIs(t, err, invalidAmountErr, percent)
+	}
+}
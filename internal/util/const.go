@@ -7,10 +7,15 @@ const (
 	DefaultFailedSqlQueryMsg                     string = "An error occurred while executing a SQL query."
 	DefaultFailedScanningToPostgresqlDataTypeMsg string = "An error occurred while scanning the value into a PostgreSQL data type."
 	DefaultFailedFetchingXMRDaemonMsg            string = "An error occurred while fetching."
+	DefaultFailedFetchingUtxoDaemonMsg           string = "An error occurred while fetching the UTXO daemon."
+	DefaultFailedFetchingETHDaemonMsg            string = "An error occurred while fetching the ETH daemon."
+	DefaultFailedFetchingTONDaemonMsg            string = "An error occurred while fetching the TON API."
 )
 
 var (
 	invalidProtoBufCoinTypeErr error = errors.New("invalid protoBuf coin type")
 	invalidDbCoinTypeErr       error = errors.New("invalid db coin type")
 	invalidDbStatusTypeErr     error = errors.New("invalid db status type")
+
+	invalidProtoBufStatusTypeErr error = errors.New("invalid protoBuf status type")
 )
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	ETH_DECIMALS int = 18
+)
+
+var (
+	invalidEthMasterPubKeyErr error = errors.New("invalid ETH master public key")
+	privateEthMasterPubKeyErr error = errors.New("ETH master public key must not be private")
+	invalidEthAddressErr      error = errors.New("invalid ETH address")
+	invalidErc20TransferErr   error = errors.New("invalid ERC-20 Transfer event")
+)
+
+// NewEthMasterPubKey parses the account level (m/44'/60'/0') extended public key.
+func NewEthMasterPubKey(key string) (*hdkeychain.ExtendedKey, error) {
+	k, err := hdkeychain.NewKeyFromString(key)
+	if err != nil {
+		return nil, errors.Join(invalidEthMasterPubKeyErr, err)
+	}
+	if k.IsPrivate() {
+		return nil, privateEthMasterPubKeyErr
+	}
+
+	return k, nil
+}
+
+// GenerateEthAddress derives the EIP-55 checksummed address at m/44'/60'/0'/major/minor.
+func GenerateEthAddress(masterPubKey *hdkeychain.ExtendedKey, major uint32, minor uint32) (string, error) {
+	branch, err := masterPubKey.Derive(major)
+	if err != nil {
+		return "", err
+	}
+	child, err := branch.Derive(minor)
+	if err != nil {
+		return "", err
+	}
+
+	pubKey, err := child.ECPubKey()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write(pubKey.SerializeUncompressed()[1:])
+
+	return ToEthChecksumAddress("0x" + hex.EncodeToString(h.Sum(nil)[12:]))
+}
+
+// ToEthChecksumAddress validates the hex address and returns its EIP-55 representation.
+func ToEthChecksumAddress(addr string) (string, error) {
+	if len(addr) != 42 || !strings.HasPrefix(addr, "0x") {
+		return "", invalidEthAddressErr
+	}
+
+	lower := strings.ToLower(addr[2:])
+	if _, err := hex.DecodeString(lower); err != nil {
+		return "", invalidEthAddressErr
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := h.Sum(nil)
+
+	res := []byte(lower)
+	for i := 0; i < len(res); i++ {
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if res[i] >= 'a' && nibble&0x0f >= 8 {
+			res[i] -= 'a' - 'A'
+		}
+	}
+
+	return "0x" + string(res), nil
+}
+
+// DecodeErc20Transfer decodes the recipient and the value of a Transfer(address,address,uint256) event.
+func DecodeErc20Transfer(topics []string, data string) (string, *big.Int, error) {
+	// topics[0] is the event signature, topics[1] and topics[2] are the indexed from/to addresses
+	if len(topics) != 3 || len(topics[2]) != 66 || len(data) != 66 {
+		return "", nil, invalidErc20TransferErr
+	}
+
+	to, err := ToEthChecksumAddress("0x" + topics[2][26:])
+	if err != nil {
+		return "", nil, err
+	}
+
+	value, ok := new(big.Int).SetString(data[2:], 16)
+	if !ok {
+		return "", nil, invalidErc20TransferErr
+	}
+
+	return to, value, nil
+}
@@ -0,0 +1,131 @@
+package util
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// BIP-39 seed of "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testSeed string = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+)
+
+func getTestEthAccountKey(t *testing.T) string {
+	seed, err := hex.DecodeString(testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []uint32{44, 60, 0} {
+		key, err = key.Derive(hdkeychain.HardenedKeyStart + i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pubKey, err := key.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pubKey.String()
+}
+
+func TestGenerateEthAddress(t *testing.T) {
+	t.Parallel()
+
+	key, err := NewEthMasterPubKey(getTestEthAccountKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := GenerateEthAddress(key, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", addr)
+
+	addr, err = GenerateEthAddress(key, 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0", addr)
+}
+
+func TestNewEthMasterPubKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should Return Error (private key)", func(t *testing.T) {
+		_, err := NewEthMasterPubKey(bip84Zprv)
+		assert.ErrorIs(t, err, privateEthMasterPubKeyErr)
+	})
+
+	t.Run("Should Return Error (invalid key)", func(t *testing.T) {
+		_, err := NewEthMasterPubKey("xpub")
+		assert.ErrorIs(t, err, invalidEthMasterPubKeyErr)
+	})
+}
+
+func TestToEthChecksumAddress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should Return EIP-55 Address", func(t *testing.T) {
+		// EIP-55 test vectors
+		for _, expected := range []string{
+			"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+			"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+			"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		} {
+			addr, err := ToEthChecksumAddress(strings.ToLower(expected))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, addr)
+		}
+	})
+
+	t.Run("Should Return Error (invalid address)", func(t *testing.T) {
+		_, err := ToEthChecksumAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe")
+		assert.ErrorIs(t, err, invalidEthAddressErr)
+
+		_, err = ToEthChecksumAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeZ")
+		assert.ErrorIs(t, err, invalidEthAddressErr)
+	})
+}
+
+func TestDecodeErc20Transfer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should Return Recipient And Value", func(t *testing.T) {
+		to, value, err := DecodeErc20Transfer(
+			[]string{
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				"0x0000000000000000000000009858effd232b4033e47d90003d41ec34ecaeda94",
+				"0x0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			},
+			"0x00000000000000000000000000000000000000000000000000000000000f4240",
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", to)
+		assert.Equal(t, big.NewInt(1_000_000), value)
+	})
+
+	t.Run("Should Return Error (ERC-721 Transfer)", func(t *testing.T) {
+		// ERC-721 Transfer has the same signature, but the tokenId is indexed
+		_, _, err := DecodeErc20Transfer(
+			[]string{
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				"0x0000000000000000000000009858effd232b4033e47d90003d41ec34ecaeda94",
+				"0x0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+				"0x0000000000000000000000000000000000000000000000000000000000000001",
+			},
+			"0x",
+		)
+		assert.ErrorIs(t, err, invalidErc20TransferErr)
+	})
+}
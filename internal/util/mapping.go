@@ -2,10 +2,12 @@ package util
 
 import (
 	"math"
+	"math/big"
 
 	"github.com/chekist32/goipay/internal/db"
 	"github.com/chekist32/goipay/internal/dto"
 	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	pb_v2 "github.com/chekist32/goipay/internal/pb/v2"
 	"github.com/jackc/pgx/v5/pgtype"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -66,10 +68,16 @@ func DbInvoiceStatusToPbInvoiceStatus(status db.InvoiceStatusType) (pb_v1.Invoic
 	switch status {
 	case db.InvoiceStatusTypePENDING:
 		return pb_v1.InvoiceStatusType_PENDING, nil
+	case db.InvoiceStatusTypePARTIALLYPAID:
+		return pb_v1.InvoiceStatusType_PARTIALLY_PAID, nil
 	case db.InvoiceStatusTypePENDINGMEMPOOL:
 		return pb_v1.InvoiceStatusType_PENDING_MEMPOOL, nil
 	case db.InvoiceStatusTypeCONFIRMED:
 		return pb_v1.InvoiceStatusType_CONFIRMED, nil
+	case db.InvoiceStatusTypePAIDAFTEREXPIRY:
+		return pb_v1.InvoiceStatusType_PAID_AFTER_EXPIRY, nil
+	case db.InvoiceStatusTypeREORGED:
+		return pb_v1.InvoiceStatusType_REORGED, nil
 	case db.InvoiceStatusTypeEXPIRED:
 		return pb_v1.InvoiceStatusType_EXPIRED, nil
 	}
@@ -77,34 +85,269 @@ func DbInvoiceStatusToPbInvoiceStatus(status db.InvoiceStatusType) (pb_v1.Invoic
 	return math.MaxInt32, invalidDbStatusTypeErr
 }
 
-func DbInvoiceToPbInvoice(invoice *db.Invoice) *pb_v1.Invoice {
+func PbInvoiceStatusToDbInvoiceStatus(status pb_v1.InvoiceStatusType) (db.InvoiceStatusType, error) {
+	switch status {
+	case pb_v1.InvoiceStatusType_PENDING:
+		return db.InvoiceStatusTypePENDING, nil
+	case pb_v1.InvoiceStatusType_PARTIALLY_PAID:
+		return db.InvoiceStatusTypePARTIALLYPAID, nil
+	case pb_v1.InvoiceStatusType_PENDING_MEMPOOL:
+		return db.InvoiceStatusTypePENDINGMEMPOOL, nil
+	case pb_v1.InvoiceStatusType_CONFIRMED:
+		return db.InvoiceStatusTypeCONFIRMED, nil
+	case pb_v1.InvoiceStatusType_PAID_AFTER_EXPIRY:
+		return db.InvoiceStatusTypePAIDAFTEREXPIRY, nil
+	case pb_v1.InvoiceStatusType_REORGED:
+		return db.InvoiceStatusTypeREORGED, nil
+	case pb_v1.InvoiceStatusType_EXPIRED:
+		return db.InvoiceStatusTypeEXPIRED, nil
+	}
+
+	return "", invalidProtoBufStatusTypeErr
+}
+
+func DbInvoiceOutcomeToPbInvoiceOutcome(outcome db.NullInvoiceOutcomeType) pb_v1.InvoiceOutcomeType {
+	if !outcome.Valid {
+		return pb_v1.InvoiceOutcomeType_NONE
+	}
+
+	switch outcome.InvoiceOutcomeType {
+	case db.InvoiceOutcomeTypeEXACT:
+		return pb_v1.InvoiceOutcomeType_EXACT
+	case db.InvoiceOutcomeTypeUNDERPAID:
+		return pb_v1.InvoiceOutcomeType_UNDERPAID
+	case db.InvoiceOutcomeTypeOVERPAID:
+		return pb_v1.InvoiceOutcomeType_OVERPAID
+	}
+
+	return pb_v1.InvoiceOutcomeType_NONE
+}
+
+func DbInvoicePaymentToPbInvoicePayment(payment *db.InvoicePayment) *pb_v1.InvoicePayment {
+	amount, _ := PgNumericToBigInt(payment.Amount)
+
+	var blockHeight *uint64
+	if payment.BlockHeight.Valid {
+		height := uint64(payment.BlockHeight.Int64)
+		blockHeight = &height
+	}
+
+	return &pb_v1.InvoicePayment{
+		TxId:         payment.TxID,
+		OutputIndex:  uint32(payment.OutputIndex),
+		AmountAtomic: amount.String(),
+		BlockHeight:  blockHeight,
+		CreatedAt:    timestamppb.New(payment.CreatedAt.Time),
+	}
+}
+
+func DbInvoiceToPbInvoice(invoice *db.Invoice, payments []db.InvoicePayment) *pb_v1.Invoice {
 	coin, _ := DbCoinToPbCoin(invoice.Coin)
 	status, _ := DbInvoiceStatusToPbInvoiceStatus(invoice.Status)
+	requiredAmount, _ := PgNumericToBigInt(invoice.RequiredAmount)
+	actualAmount, _ := PgNumericToBigInt(invoice.ActualAmount)
+	toleranceAmount, _ := PgNumericToBigInt(invoice.ToleranceAmount)
+
+	var amountDelta *string
+	if invoice.AmountDelta.Valid {
+		delta, _ := PgNumericToBigInt(invoice.AmountDelta)
+		deltaStr := delta.String()
+		amountDelta = &deltaStr
+	}
+
+	pbPayments := make([]*pb_v1.InvoicePayment, 0, len(payments))
+	for i := 0; i < len(payments); i++ {
+		pbPayments = append(pbPayments, DbInvoicePaymentToPbInvoicePayment(&payments[i]))
+	}
 
 	return &pb_v1.Invoice{
 		Id:                    PgUUIDToString(invoice.ID),
 		CryptoAddress:         invoice.CryptoAddress,
 		Coin:                  coin,
-		RequiredAmount:        invoice.RequiredAmount,
-		ActualAmount:          invoice.ActualAmount.Float64,
+		RequiredAmount:        AtomicUnitsToFloat(requiredAmount, int(invoice.Decimals)),
+		ActualAmount:          AtomicUnitsToFloat(actualAmount, int(invoice.Decimals)),
 		ConfirmationsRequired: uint32(invoice.ConfirmationsRequired),
+		Confirmations:         uint32(invoice.Confirmations),
 		CreatedAt:             timestamppb.New(invoice.CreatedAt.Time),
 		ConfirmedAt:           timestamppb.New(invoice.ConfirmedAt.Time),
 		Status:                status,
 		ExpiresAt:             timestamppb.New(invoice.ExpiresAt.Time),
 		TxId:                  invoice.TxID.String,
 		UserId:                PgUUIDToString(invoice.UserID),
+		Token:                 PgTextToStringPtr(invoice.TokenContract),
+		Memo:                  PgTextToStringPtr(invoice.Memo),
+		RequiredAmountAtomic:  requiredAmount.String(),
+		ActualAmountAtomic:    actualAmount.String(),
+		Decimals:              uint32(invoice.Decimals),
+		Payments:              pbPayments,
+		ToleranceAtomic:       toleranceAmount.String(),
+		Outcome:               DbInvoiceOutcomeToPbInvoiceOutcome(invoice.Outcome),
+		AmountDeltaAtomic:     amountDelta,
+	}
+}
+
+// PbInvoiceToPbV2Invoice maps the v1 invoice to the v2 one carrying the exact atomic amounts only.
+// The v2 status and outcome enums share the numbers of the v1 ones.
+func PbInvoiceToPbV2Invoice(invoice *pb_v1.Invoice) *pb_v2.Invoice {
+	payments := make([]*pb_v2.InvoicePayment, 0, len(invoice.Payments))
+	for _, p := range invoice.Payments {
+		payments = append(payments, &pb_v2.InvoicePayment{
+			TxId:        p.TxId,
+			OutputIndex: p.OutputIndex,
+			Amount:      p.AmountAtomic,
+			BlockHeight: p.BlockHeight,
+			CreatedAt:   p.CreatedAt,
+		})
+	}
+
+	return &pb_v2.Invoice{
+		Id:                    invoice.Id,
+		UserId:                invoice.UserId,
+		CryptoAddress:         invoice.CryptoAddress,
+		Coin:                  invoice.Coin,
+		Token:                 invoice.Token,
+		Memo:                  invoice.Memo,
+		Decimals:              invoice.Decimals,
+		RequiredAmount:        invoice.RequiredAmountAtomic,
+		ActualAmount:          invoice.ActualAmountAtomic,
+		Tolerance:             invoice.ToleranceAtomic,
+		AmountDelta:           invoice.AmountDeltaAtomic,
+		Status:                pb_v2.InvoiceStatusType(invoice.Status),
+		Outcome:               pb_v2.InvoiceOutcomeType(invoice.Outcome),
+		ConfirmationsRequired: invoice.ConfirmationsRequired,
+		Confirmations:         invoice.Confirmations,
+		TxId:                  invoice.TxId,
+		Payments:              payments,
+		CreatedAt:             invoice.CreatedAt,
+		ConfirmedAt:           invoice.ConfirmedAt,
+		ExpiresAt:             invoice.ExpiresAt,
+	}
+}
+
+func DbWebhookToPbWebhook(webhook *db.Webhook) *pb_v1.Webhook {
+	return &pb_v1.Webhook{
+		Id:        PgUUIDToString(webhook.ID),
+		Url:       webhook.Url,
+		CreatedAt: timestamppb.New(webhook.CreatedAt.Time),
+	}
+}
+
+func PgTextToStringPtr(text pgtype.Text) *string {
+	if !text.Valid {
+		return nil
+	}
+
+	return &text.String
+}
+
+// DbCryptoCacheToPbSyncState maps the persisted sync state of the coin, the sync lag is left unset.
+func DbCryptoCacheToPbSyncState(cache *db.CryptoCache) (*pb_v1.SyncState, error) {
+	coin, err := DbCoinToPbCoin(cache.Coin)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &pb_v1.SyncState{Coin: coin, LastSyncedBlockHeight: uint64(cache.LastSyncedBlockHeight.Int64)}
+	if cache.SyncedTimestamp.Valid {
+		state.SyncedAt = timestamppb.New(cache.SyncedTimestamp.Time)
+	}
+
+	return state, nil
+}
+
+func DtoAssetToPbAsset(asset *dto.Asset) *pb_v1.Asset {
+	coin, _ := DbCoinToPbCoin(asset.Coin)
+
+	var contract *string
+	if asset.Contract != "" {
+		contract = &asset.Contract
+	}
+
+	return &pb_v1.Asset{
+		Coin:     coin,
+		Symbol:   asset.Symbol,
+		Contract: contract,
+		Decimals: asset.Decimals,
 	}
 }
 
-func PbNewInvoiceToProcessorNewInvoice(req *pb_v1.CreateInvoiceRequest) *dto.NewInvoiceRequest {
+func PbNewInvoiceToProcessorNewInvoice(req *pb_v1.CreateInvoiceRequest) (*dto.NewInvoiceRequest, error) {
 	coin, _ := PbCoinToDbCoin(req.Coin)
 
+	var amountAtomic *big.Int
+	if req.AmountAtomic != nil {
+		amount, err := ParseAtomicUnits(req.GetAmountAtomic())
+		if err != nil {
+			return nil, err
+		}
+		amountAtomic = amount
+	}
+
+	var toleranceAtomic *big.Int
+	var tolerancePercent *float64
+	switch tolerance := req.GetTolerance().GetTolerance().(type) {
+	case *pb_v1.AmountTolerance_AbsoluteAtomic:
+		amount, err := ParseAtomicUnits(tolerance.AbsoluteAtomic)
+		if err != nil {
+			return nil, err
+		}
+		toleranceAtomic = amount
+	case *pb_v1.AmountTolerance_Percent:
+		tolerancePercent = &tolerance.Percent
+	}
+
 	return &dto.NewInvoiceRequest{
-		UserId:        req.UserId,
-		Coin:          coin,
-		Amount:        req.Amount,
-		Timeout:       req.Timeout,
-		Confirmations: req.Confirmations,
+		UserId:           req.UserId,
+		Coin:             coin,
+		Amount:           req.Amount,
+		AmountAtomic:     amountAtomic,
+		Timeout:          req.Timeout,
+		Confirmations:    req.Confirmations,
+		Token:            req.GetToken(),
+		ToleranceAtomic:  toleranceAtomic,
+		TolerancePercent: tolerancePercent,
+	}, nil
+}
+
+func PbInvoiceStatusStreamRequestToInvoiceFilter(req *pb_v1.InvoiceStatusStreamRequest) (*dto.InvoiceFilter, error) {
+	filter := &dto.InvoiceFilter{
+		UserIds:    make([]pgtype.UUID, 0, len(req.UserIds)),
+		InvoiceIds: make([]pgtype.UUID, 0, len(req.PaymentIds)),
+		Coins:      make([]db.CoinType, 0, len(req.Coins)),
+		Statuses:   make([]db.InvoiceStatusType, 0, len(req.Statuses)),
+	}
+
+	for i := 0; i < len(req.UserIds); i++ {
+		id, err := StringToPgUUID(req.UserIds[i])
+		if err != nil {
+			return nil, err
+		}
+		filter.UserIds = append(filter.UserIds, *id)
 	}
+
+	for i := 0; i < len(req.PaymentIds); i++ {
+		id, err := StringToPgUUID(req.PaymentIds[i])
+		if err != nil {
+			return nil, err
+		}
+		filter.InvoiceIds = append(filter.InvoiceIds, *id)
+	}
+
+	for i := 0; i < len(req.Coins); i++ {
+		coin, err := PbCoinToDbCoin(req.Coins[i])
+		if err != nil {
+			return nil, err
+		}
+		filter.Coins = append(filter.Coins, coin)
+	}
+
+	for i := 0; i < len(req.Statuses); i++ {
+		status, err := PbInvoiceStatusToDbInvoiceStatus(req.Statuses[i])
+		if err != nil {
+			return nil, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	return filter, nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/big"
 	"math/rand"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/chekist32/goipay/internal/db"
 	"github.com/chekist32/goipay/internal/dto"
 	pb_v1 "github.com/chekist32/goipay/internal/pb/v1"
+	pb_v2 "github.com/chekist32/goipay/internal/pb/v2"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +22,8 @@ import (
 var (
 	pbCoins           []pb_v1.CoinType          = []pb_v1.CoinType{pb_v1.CoinType_XMR, pb_v1.CoinType_BTC, pb_v1.CoinType_LTC, pb_v1.CoinType_ETH, pb_v1.CoinType_TON}
 	dbCoins           []db.CoinType             = []db.CoinType{db.CoinTypeXMR, db.CoinTypeBTC, db.CoinTypeLTC, db.CoinTypeETH, db.CoinTypeTON}
-	dbInvoiceStatuses []db.InvoiceStatusType    = []db.InvoiceStatusType{db.InvoiceStatusTypePENDING, db.InvoiceStatusTypePENDINGMEMPOOL, db.InvoiceStatusTypeEXPIRED, db.InvoiceStatusTypeCONFIRMED}
-	pbInvoiceStatuses []pb_v1.InvoiceStatusType = []pb_v1.InvoiceStatusType{pb_v1.InvoiceStatusType_PENDING, pb_v1.InvoiceStatusType_PENDING_MEMPOOL, pb_v1.InvoiceStatusType_EXPIRED, pb_v1.InvoiceStatusType_CONFIRMED}
+	dbInvoiceStatuses []db.InvoiceStatusType    = []db.InvoiceStatusType{db.InvoiceStatusTypePENDING, db.InvoiceStatusTypePARTIALLYPAID, db.InvoiceStatusTypePENDINGMEMPOOL, db.InvoiceStatusTypeEXPIRED, db.InvoiceStatusTypeCONFIRMED, db.InvoiceStatusTypePAIDAFTEREXPIRY, db.InvoiceStatusTypeREORGED}
+	pbInvoiceStatuses []pb_v1.InvoiceStatusType = []pb_v1.InvoiceStatusType{pb_v1.InvoiceStatusType_PENDING, pb_v1.InvoiceStatusType_PARTIALLY_PAID, pb_v1.InvoiceStatusType_PENDING_MEMPOOL, pb_v1.InvoiceStatusType_EXPIRED, pb_v1.InvoiceStatusType_CONFIRMED, pb_v1.InvoiceStatusType_PAID_AFTER_EXPIRY, pb_v1.InvoiceStatusType_REORGED}
 )
 
 func TestStringToPgUUID(t *testing.T) {
@@ -131,22 +133,84 @@ func TestDbInvoiceStatusToPbInvoiceStatus(t *testing.T) {
 	})
 }
 
+func TestPbInvoiceStatusToDbInvoiceStatus(t *testing.T) {
+	t.Run("Should Return Valid DbInvoiceStatus For PbInvoiceStatus", func(t *testing.T) {
+		for i := 0; i < len(pbInvoiceStatuses); i++ {
+			t.Run(fmt.Sprintf("Should Return Valid DbInvoiceStatus For PbInvoiceStatus(%v)", pbInvoiceStatuses[i]), func(t *testing.T) {
+				expectedDbInvoiceStatus := dbInvoiceStatuses[i]
+
+				dbInvoiceStatus, err := PbInvoiceStatusToDbInvoiceStatus(pbInvoiceStatuses[i])
+				assert.NoError(t, err)
+				assert.Equal(t, expectedDbInvoiceStatus, dbInvoiceStatus)
+			})
+		}
+	})
+
+	t.Run("Should Return Error", func(t *testing.T) {
+		_, err := PbInvoiceStatusToDbInvoiceStatus(math.MaxInt32)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, invalidProtoBufStatusTypeErr)
+	})
+}
+
+func TestPbInvoiceStatusStreamRequestToInvoiceFilter(t *testing.T) {
+	t.Run("Should Return Valid InvoiceFilter", func(t *testing.T) {
+		userId := uuid.New()
+		paymentId := uuid.New()
+
+		req := &pb_v1.InvoiceStatusStreamRequest{
+			UserIds:    []string{userId.String()},
+			PaymentIds: []string{paymentId.String()},
+			Coins:      []pb_v1.CoinType{pb_v1.CoinType_XMR, pb_v1.CoinType_TON},
+			Statuses:   []pb_v1.InvoiceStatusType{pb_v1.InvoiceStatusType_CONFIRMED},
+		}
+		expectedFilter := &dto.InvoiceFilter{
+			UserIds:    []pgtype.UUID{{Bytes: userId, Valid: true}},
+			InvoiceIds: []pgtype.UUID{{Bytes: paymentId, Valid: true}},
+			Coins:      []db.CoinType{db.CoinTypeXMR, db.CoinTypeTON},
+			Statuses:   []db.InvoiceStatusType{db.InvoiceStatusTypeCONFIRMED},
+		}
+
+		filter, err := PbInvoiceStatusStreamRequestToInvoiceFilter(req)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedFilter, filter)
+	})
+
+	t.Run("Should Return Empty InvoiceFilter", func(t *testing.T) {
+		filter, err := PbInvoiceStatusStreamRequestToInvoiceFilter(&pb_v1.InvoiceStatusStreamRequest{})
+		assert.NoError(t, err)
+		assert.Empty(t, filter.UserIds)
+		assert.Empty(t, filter.InvoiceIds)
+		assert.Empty(t, filter.Coins)
+		assert.Empty(t, filter.Statuses)
+	})
+
+	t.Run("Should Return Error", func(t *testing.T) {
+		_, err := PbInvoiceStatusStreamRequestToInvoiceFilter(&pb_v1.InvoiceStatusStreamRequest{UserIds: []string{"invalid"}})
+		assert.Error(t, err)
+
+		_, err = PbInvoiceStatusStreamRequestToInvoiceFilter(&pb_v1.InvoiceStatusStreamRequest{Coins: []pb_v1.CoinType{math.MaxInt32}})
+		assert.ErrorIs(t, err, invalidProtoBufCoinTypeErr)
+
+		_, err = PbInvoiceStatusStreamRequestToInvoiceFilter(&pb_v1.InvoiceStatusStreamRequest{Statuses: []pb_v1.InvoiceStatusType{math.MaxInt32}})
+		assert.ErrorIs(t, err, invalidProtoBufStatusTypeErr)
+	})
+}
+
 func TestDbInvoiceToPbInvoice(t *testing.T) {
 	idStr := uuid.NewString()
-	actualAmountFloat64 := rand.Float64()
+	requiredAmountAtomic := big.NewInt(1_500_000_000)
+	actualAmountAtomic := big.NewInt(2_000_000_001)
 	createdAtTime := time.Now().UTC()
 	expiresAtTime := createdAtTime.Add(time.Duration(rand.Intn(math.MaxInt)+1) * time.Minute)
 	txIdStr := uuid.NewString()
 	userIdStr := uuid.NewString()
+	memoStr := uuid.NewString()
 
 	var id pgtype.UUID
 	if err := id.Scan(idStr); err != nil {
 		log.Fatal(err)
 	}
-	var actualAmount pgtype.Float8
-	if err := actualAmount.Scan(actualAmountFloat64); err != nil {
-		log.Fatal(err)
-	}
 	var createdAt pgtype.Timestamptz
 	if err := createdAt.Scan(createdAtTime); err != nil {
 		log.Fatal(err)
@@ -167,34 +231,178 @@ func TestDbInvoiceToPbInvoice(t *testing.T) {
 	dbInv := db.Invoice{
 		ID:                    id,
 		CryptoAddress:         uuid.NewString(),
-		Coin:                  db.CoinTypeBTC,
-		RequiredAmount:        rand.Float64(),
-		ActualAmount:          actualAmount,
+		Coin:                  db.CoinTypeTON,
+		RequiredAmount:        BigIntToPgNumeric(requiredAmountAtomic),
+		ActualAmount:          BigIntToPgNumeric(actualAmountAtomic),
 		ConfirmationsRequired: int16(rand.Intn(math.MaxInt16)),
+		Confirmations:         int16(rand.Intn(math.MaxInt16)),
 		CreatedAt:             createdAt,
 		ConfirmedAt:           pgtype.Timestamptz{},
-		Status:                db.InvoiceStatusTypePENDING,
+		Status:                db.InvoiceStatusTypePENDINGMEMPOOL,
 		ExpiresAt:             expiresAt,
 		TxID:                  txId,
 		UserID:                userId,
+		Memo:                  pgtype.Text{String: memoStr, Valid: true},
+		Decimals:              int16(TON_DECIMALS),
+		ToleranceAmount:       BigIntToPgNumeric(big.NewInt(100_000_000)),
+		AmountDelta:           BigIntToPgNumeric(big.NewInt(500_000_001)),
+		Outcome:               db.NullInvoiceOutcomeType{InvoiceOutcomeType: db.InvoiceOutcomeTypeOVERPAID, Valid: true},
+	}
+	amountDeltaStr := "500000001"
+
+	blockHeight := uint64(rand.Int63())
+	payments := []db.InvoicePayment{
+		{InvoiceID: id, TxID: "tx1", OutputIndex: 0, Amount: BigIntToPgNumeric(big.NewInt(1_000_000_000)), BlockHeight: pgtype.Int8{Int64: int64(blockHeight), Valid: true}, CreatedAt: createdAt},
+		{InvoiceID: id, TxID: txIdStr, OutputIndex: 1, Amount: BigIntToPgNumeric(big.NewInt(1_000_000_001)), CreatedAt: createdAt},
 	}
 
 	expectedPbInvoice := pb_v1.Invoice{
 		Id:                    idStr,
 		CryptoAddress:         dbInv.CryptoAddress,
-		Coin:                  pb_v1.CoinType_BTC,
-		RequiredAmount:        dbInv.RequiredAmount,
-		ActualAmount:          actualAmountFloat64,
+		Coin:                  pb_v1.CoinType_TON,
+		RequiredAmount:        1.5,
+		ActualAmount:          2.000000001,
 		ConfirmationsRequired: uint32(dbInv.ConfirmationsRequired),
+		Confirmations:         uint32(dbInv.Confirmations),
 		CreatedAt:             timestamppb.New(createdAtTime),
 		ConfirmedAt:           timestamppb.New(dbInv.ConfirmedAt.Time),
-		Status:                pb_v1.InvoiceStatusType_PENDING,
+		Status:                pb_v1.InvoiceStatusType_PENDING_MEMPOOL,
 		ExpiresAt:             timestamppb.New(expiresAtTime),
 		TxId:                  txIdStr,
 		UserId:                userIdStr,
+		Memo:                  &memoStr,
+		RequiredAmountAtomic:  "1500000000",
+		ActualAmountAtomic:    "2000000001",
+		Decimals:              uint32(TON_DECIMALS),
+		Payments: []*pb_v1.InvoicePayment{
+			{TxId: "tx1", OutputIndex: 0, AmountAtomic: "1000000000", BlockHeight: &blockHeight, CreatedAt: timestamppb.New(createdAtTime)},
+			{TxId: txIdStr, OutputIndex: 1, AmountAtomic: "1000000001", CreatedAt: timestamppb.New(createdAtTime)},
+		},
+		ToleranceAtomic:   "100000000",
+		Outcome:           pb_v1.InvoiceOutcomeType_OVERPAID,
+		AmountDeltaAtomic: &amountDeltaStr,
 	}
 
-	assert.Equal(t, expectedPbInvoice, *DbInvoiceToPbInvoice(&dbInv))
+	assert.Equal(t, &expectedPbInvoice, DbInvoiceToPbInvoice(&dbInv, payments))
+}
+
+func TestPbInvoiceToPbV2Invoice(t *testing.T) {
+	createdAt := timestamppb.New(time.Now().UTC())
+	expiresAt := timestamppb.New(createdAt.AsTime().Add(time.Hour))
+	token := "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	amountDelta := "-1"
+	blockHeight := uint64(rand.Int63())
+
+	pbInv := pb_v1.Invoice{
+		Id:                    uuid.NewString(),
+		UserId:                uuid.NewString(),
+		CryptoAddress:         uuid.NewString(),
+		Coin:                  pb_v1.CoinType_ETH,
+		Token:                 &token,
+		Decimals:              6,
+		RequiredAmount:        1.000001,
+		ActualAmount:          1,
+		RequiredAmountAtomic:  "1000001",
+		ActualAmountAtomic:    "1000000",
+		ToleranceAtomic:       "10",
+		AmountDeltaAtomic:     &amountDelta,
+		Status:                pb_v1.InvoiceStatusType_PAID_AFTER_EXPIRY,
+		Outcome:               pb_v1.InvoiceOutcomeType_UNDERPAID,
+		ConfirmationsRequired: 12,
+		Confirmations:         3,
+		TxId:                  "tx1",
+		Payments: []*pb_v1.InvoicePayment{
+			{TxId: "tx1", OutputIndex: 7, AmountAtomic: "1000000", BlockHeight: &blockHeight, CreatedAt: createdAt},
+		},
+		CreatedAt:   createdAt,
+		ConfirmedAt: createdAt,
+		ExpiresAt:   expiresAt,
+	}
+
+	expectedPbInvoice := pb_v2.Invoice{
+		Id:                    pbInv.Id,
+		UserId:                pbInv.UserId,
+		CryptoAddress:         pbInv.CryptoAddress,
+		Coin:                  pb_v1.CoinType_ETH,
+		Token:                 &token,
+		Decimals:              6,
+		RequiredAmount:        "1000001",
+		ActualAmount:          "1000000",
+		Tolerance:             "10",
+		AmountDelta:           &amountDelta,
+		Status:                pb_v2.InvoiceStatusType_PAID_AFTER_EXPIRY,
+		Outcome:               pb_v2.InvoiceOutcomeType_UNDERPAID,
+		ConfirmationsRequired: 12,
+		Confirmations:         3,
+		TxId:                  "tx1",
+		Payments: []*pb_v2.InvoicePayment{
+			{TxId: "tx1", OutputIndex: 7, Amount: "1000000", BlockHeight: &blockHeight, CreatedAt: createdAt},
+		},
+		CreatedAt:   createdAt,
+		ConfirmedAt: createdAt,
+		ExpiresAt:   expiresAt,
+	}
+
+	assert.Equal(t, &expectedPbInvoice, PbInvoiceToPbV2Invoice(&pbInv))
+
+	t.Run("Should Map Every Status And Outcome", func(t *testing.T) {
+		for _, s := range pbInvoiceStatuses {
+			assert.Equal(t, s.String(), PbInvoiceToPbV2Invoice(&pb_v1.Invoice{Status: s}).Status.String())
+		}
+		for o := range pb_v1.InvoiceOutcomeType_name {
+			assert.Equal(t, pb_v1.InvoiceOutcomeType(o).String(), PbInvoiceToPbV2Invoice(&pb_v1.Invoice{Outcome: pb_v1.InvoiceOutcomeType(o)}).Outcome.String())
+		}
+	})
+}
+
+func TestDbWebhookToPbWebhook(t *testing.T) {
+	idStr := uuid.NewString()
+	id, err := StringToPgUUID(idStr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	createdAtTime := time.Now().UTC()
+	dbWebhook := db.Webhook{
+		ID:        *id,
+		UserID:    *id,
+		Url:       "https://example.com/webhook",
+		Secret:    uuid.NewString(),
+		CreatedAt: pgtype.Timestamptz{Time: createdAtTime, Valid: true},
+	}
+
+	expectedPbWebhook := pb_v1.Webhook{Id: idStr, Url: dbWebhook.Url, CreatedAt: timestamppb.New(createdAtTime)}
+
+	assert.Equal(t, &expectedPbWebhook, DbWebhookToPbWebhook(&dbWebhook))
+}
+
+func TestDbCryptoCacheToPbSyncState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should Return Sync State", func(t *testing.T) {
+		syncedAt := time.Now().UTC()
+		cache := db.CryptoCache{
+			Coin:                  db.CoinTypeBTC,
+			LastSyncedBlockHeight: pgtype.Int8{Int64: 840000, Valid: true},
+			SyncedTimestamp:       pgtype.Timestamptz{Time: syncedAt, Valid: true},
+		}
+		expectedState := pb_v1.SyncState{Coin: pb_v1.CoinType_BTC, LastSyncedBlockHeight: 840000, SyncedAt: timestamppb.New(syncedAt)}
+
+		state, err := DbCryptoCacheToPbSyncState(&cache)
+		assert.NoError(t, err)
+		assert.Equal(t, &expectedState, state)
+	})
+
+	t.Run("Should Return Never Synced State", func(t *testing.T) {
+		state, err := DbCryptoCacheToPbSyncState(&db.CryptoCache{Coin: db.CoinTypeTON})
+		assert.NoError(t, err)
+		assert.Equal(t, &pb_v1.SyncState{Coin: pb_v1.CoinType_TON}, state)
+	})
+
+	t.Run("Should Return Error (Invalid Coin)", func(t *testing.T) {
+		_, err := DbCryptoCacheToPbSyncState(&db.CryptoCache{Coin: db.CoinType("DOGE")})
+		assert.Error(t, err)
+	})
 }
 
 func TestPbNewInvoiceToProcessorNewInvoice(t *testing.T) {
@@ -202,22 +410,83 @@ func TestPbNewInvoiceToProcessorNewInvoice(t *testing.T) {
 	amount := rand.Float64()
 	timeout := rand.Uint64()
 	confirmations := rand.Uint32()
+	token := "USDT"
+	amountAtomic := "12500000"
 
 	newInv := pb_v1.CreateInvoiceRequest{
 		UserId:        userId,
-		Coin:          pb_v1.CoinType_BTC,
+		Coin:          pb_v1.CoinType_ETH,
 		Amount:        amount,
 		Timeout:       timeout,
 		Confirmations: confirmations,
+		Token:         &token,
+		AmountAtomic:  &amountAtomic,
 	}
 
 	expectedProcessorNewInvoice := dto.NewInvoiceRequest{
 		UserId:        userId,
-		Coin:          db.CoinTypeBTC,
+		Coin:          db.CoinTypeETH,
 		Amount:        amount,
+		AmountAtomic:  big.NewInt(12500000),
 		Timeout:       timeout,
 		Confirmations: confirmations,
+		Token:         token,
 	}
 
-	assert.Equal(t, expectedProcessorNewInvoice, *PbNewInvoiceToProcessorNewInvoice(&newInv))
+	t.Run("Should Return Valid NewInvoiceRequest", func(t *testing.T) {
+		processorNewInvoice, err := PbNewInvoiceToProcessorNewInvoice(&newInv)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedProcessorNewInvoice, *processorNewInvoice)
+	})
+
+	t.Run("Should Return Valid NewInvoiceRequest (with tolerance)", func(t *testing.T) {
+		percent := 2.5
+		percentInv := pb_v1.CreateInvoiceRequest{UserId: userId, Coin: pb_v1.CoinType_XMR, Tolerance: &pb_v1.AmountTolerance{Tolerance: &pb_v1.AmountTolerance_Percent{Percent: percent}}}
+
+		processorNewInvoice, err := PbNewInvoiceToProcessorNewInvoice(&percentInv)
+		assert.NoError(t, err)
+		assert.Equal(t, &percent, processorNewInvoice.TolerancePercent)
+		assert.Nil(t, processorNewInvoice.ToleranceAtomic)
+
+		absoluteInv := pb_v1.CreateInvoiceRequest{UserId: userId, Coin: pb_v1.CoinType_XMR, Tolerance: &pb_v1.AmountTolerance{Tolerance: &pb_v1.AmountTolerance_AbsoluteAtomic{AbsoluteAtomic: "1000"}}}
+
+		processorNewInvoice, err = PbNewInvoiceToProcessorNewInvoice(&absoluteInv)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(1000), processorNewInvoice.ToleranceAtomic)
+		assert.Nil(t, processorNewInvoice.TolerancePercent)
+	})
+
+	t.Run("Should Return Error (invalid tolerance)", func(t *testing.T) {
+		invalidInv := pb_v1.CreateInvoiceRequest{UserId: userId, Coin: pb_v1.CoinType_XMR, Tolerance: &pb_v1.AmountTolerance{Tolerance: &pb_v1.AmountTolerance_AbsoluteAtomic{AbsoluteAtomic: "0.5"}}}
+
+		_, err := PbNewInvoiceToProcessorNewInvoice(&invalidInv)
+		assert.ErrorIs(t, err, invalidAmountErr)
+	})
+
+	t.Run("Should Return Error (invalid amountAtomic)", func(t *testing.T) {
+		invalidAmount := "-1"
+		invalidInv := pb_v1.CreateInvoiceRequest{UserId: userId, Coin: pb_v1.CoinType_ETH, AmountAtomic: &invalidAmount}
+
+		_, err := PbNewInvoiceToProcessorNewInvoice(&invalidInv)
+		assert.ErrorIs(t, err, invalidAmountErr)
+	})
+}
+
+func TestDtoAssetToPbAsset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should Return Native Asset", func(t *testing.T) {
+		asset := dto.Asset{Coin: db.CoinTypeETH, Symbol: "ETH", Decimals: 18}
+		expectedAsset := pb_v1.Asset{Coin: pb_v1.CoinType_ETH, Symbol: "ETH", Decimals: 18}
+
+		assert.Equal(t, &expectedAsset, DtoAssetToPbAsset(&asset))
+	})
+
+	t.Run("Should Return Token Asset", func(t *testing.T) {
+		contract := "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+		asset := dto.Asset{Coin: db.CoinTypeETH, Symbol: "USDT", Contract: contract, Decimals: 6}
+		expectedAsset := pb_v1.Asset{Coin: pb_v1.CoinType_ETH, Symbol: "USDT", Contract: &contract, Decimals: 6}
+
+		assert.Equal(t, &expectedAsset, DtoAssetToPbAsset(&asset))
+	})
 }
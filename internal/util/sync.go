@@ -1,6 +1,7 @@
 package util
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -36,3 +37,70 @@ func (m *SyncMapTypeSafe[K, V]) Range(f func(key K, value V) bool) {
 	m.m.Range(func(key, value any) bool { return f(key.(K), value.(V)) })
 }
 func (m *SyncMapTypeSafe[K, V]) Store(key K, value V) { m.m.Store(key, value) }
+
+// Len counts the entries, it's O(n).
+func (m *SyncMapTypeSafe[K, V]) Len() int {
+	n := 0
+	m.m.Range(func(_, _ any) bool { n++; return true })
+	return n
+}
+
+// Routines tracks goroutines so they can be waited for. Unlike sync.WaitGroup,
+// new goroutines may be started while another one is waiting.
+type Routines struct {
+	mu      sync.Mutex
+	running int
+	// Closed once no goroutine is running, nil if nobody waits
+	idle chan struct{}
+}
+
+// Go runs f in a new goroutine.
+func (r *Routines) Go(f func()) {
+	r.mu.Lock()
+	r.running++
+	r.mu.Unlock()
+
+	go func() {
+		defer r.done()
+		f()
+	}()
+}
+
+func (r *Routines) done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.running--
+	if r.running == 0 && r.idle != nil {
+		close(r.idle)
+		r.idle = nil
+	}
+}
+
+// Running returns the number of the running goroutines.
+func (r *Routines) Running() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
+
+// Wait waits until no goroutine is running or ctx is done, in which case ctx.Err() is returned.
+func (r *Routines) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	if r.running == 0 {
+		r.mu.Unlock()
+		return nil
+	}
+	if r.idle == nil {
+		r.idle = make(chan struct{})
+	}
+	idle := r.idle
+	r.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Wait Without Goroutines", func(t *testing.T) {
+		var r Routines
+		assert.NoError(t, r.Wait(context.Background()))
+	})
+
+	t.Run("Wait For Nested Goroutines", func(t *testing.T) {
+		var r Routines
+		release := make(chan struct{})
+		finished := make(chan struct{}, 2)
+
+		r.Go(func() {
+			<-release
+			// Started while Wait is already waiting
+			r.Go(func() {
+				time.Sleep(10 * time.Millisecond)
+				finished <- struct{}{}
+			})
+			finished <- struct{}{}
+		})
+		assert.Equal(t, 1, r.Running())
+
+		go close(release)
+		assert.NoError(t, r.Wait(context.Background()))
+		assert.Equal(t, 0, r.Running())
+		assert.Equal(t, 2, len(finished))
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		var r Routines
+		release := make(chan struct{})
+		r.Go(func() { <-release })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, r.Wait(ctx), context.DeadlineExceeded)
+		assert.Equal(t, 1, r.Running())
+
+		close(release)
+		assert.NoError(t, r.Wait(context.Background()))
+	})
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	TON_DECIMALS int = 9
+
+	tonFriendlyAddressLength int  = 36
+	tonBounceableFlag        byte = 0x11
+	tonNonBounceableFlag     byte = 0x51
+	tonTestnetFlag           byte = 0x80
+
+	tonMemoLength int = 8
+)
+
+var (
+	invalidTonAddressErr         error = errors.New("invalid TON address")
+	invalidTonAddressChecksumErr error = errors.New("invalid TON address checksum")
+)
+
+// TonAddress is a TON account address: a workchain id and the 256-bit account id.
+type TonAddress struct {
+	Workchain int8
+	Hash      [32]byte
+	Testnet   bool
+}
+
+// Raw returns the address in the raw "workchain:hex" form.
+func (a *TonAddress) Raw() string {
+	return fmt.Sprintf("%d:%s", a.Workchain, hex.EncodeToString(a.Hash[:]))
+}
+
+// crc16 is CRC-16/XMODEM used by the user-friendly TON address form.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func parseRawTonAddress(addr string) (*TonAddress, error) {
+	parts := strings.Split(addr, ":")
+	if len(parts) != 2 {
+		return nil, invalidTonAddressErr
+	}
+
+	wc, err := strconv.ParseInt(parts[0], 10, 8)
+	if err != nil {
+		return nil, invalidTonAddressErr
+	}
+
+	hash, err := hex.DecodeString(parts[1])
+	if err != nil || len(hash) != 32 {
+		return nil, invalidTonAddressErr
+	}
+
+	res := &TonAddress{Workchain: int8(wc)}
+	copy(res.Hash[:], hash)
+
+	return res, nil
+}
+
+func parseFriendlyTonAddress(addr string) (*TonAddress, error) {
+	data, err := base64.URLEncoding.DecodeString(strings.NewReplacer("+", "-", "/", "_").Replace(addr))
+	if err != nil || len(data) != tonFriendlyAddressLength {
+		return nil, invalidTonAddressErr
+	}
+
+	if crc16(data[:34]) != binary.BigEndian.Uint16(data[34:]) {
+		return nil, invalidTonAddressChecksumErr
+	}
+
+	flag := data[0] &^ tonTestnetFlag
+	if flag != tonBounceableFlag && flag != tonNonBounceableFlag {
+		return nil, invalidTonAddressErr
+	}
+
+	res := &TonAddress{Workchain: int8(data[1]), Testnet: data[0]&tonTestnetFlag != 0}
+	copy(res.Hash[:], data[2:34])
+
+	return res, nil
+}
+
+// ParseTonAddress accepts both the raw and the user-friendly (base64 or base64url) address forms.
+func ParseTonAddress(addr string) (*TonAddress, error) {
+	if strings.Contains(addr, ":") {
+		return parseRawTonAddress(addr)
+	}
+
+	return parseFriendlyTonAddress(addr)
+}
+
+// GenerateTonMemo returns a random comment used to match a TON payment with its invoice.
+func GenerateTonMemo() (string, error) {
+	b := make([]byte, tonMemoLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTonAddress(t *testing.T) {
+	expectedRaw := "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+
+	t.Run("Should Parse Bounceable Address", func(t *testing.T) {
+		addr, err := ParseTonAddress("EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2N")
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRaw, addr.Raw())
+		assert.False(t, addr.Testnet)
+	})
+
+	t.Run("Should Parse Non-Bounceable Address", func(t *testing.T) {
+		addr, err := ParseTonAddress("UQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqEBI")
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRaw, addr.Raw())
+	})
+
+	t.Run("Should Parse Raw Address", func(t *testing.T) {
+		addr, err := ParseTonAddress(expectedRaw)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRaw, addr.Raw())
+	})
+
+	t.Run("Should Return Error (invalid checksum)", func(t *testing.T) {
+		_, err := ParseTonAddress("EQCD39VS5jcptHL8vMjEXrzGaRcCVYto7HUn4bpAOg8xqB2O")
+		assert.ErrorIs(t, err, invalidTonAddressChecksumErr)
+	})
+
+	t.Run("Should Return Error (invalid address)", func(t *testing.T) {
+		_, err := ParseTonAddress("0:83dfd5")
+		assert.ErrorIs(t, err, invalidTonAddressErr)
+
+		_, err = ParseTonAddress("EQCD39VS5jcptHL8")
+		assert.ErrorIs(t, err, invalidTonAddressErr)
+	})
+}
+
+func TestGenerateTonMemo(t *testing.T) {
+	memo1, err := GenerateTonMemo()
+	assert.NoError(t, err)
+	memo2, err := GenerateTonMemo()
+	assert.NoError(t, err)
+
+	assert.Len(t, memo1, 2*tonMemoLength)
+	assert.NotEqual(t, memo1, memo2)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 
 	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// InitDbQueriesWithTx begins a transaction traced by a span, the queries run on it become its children.
 func InitDbQueriesWithTx(ctx context.Context, dbConnPool *pgxpool.Pool) (*db.Queries, pgx.Tx, error) {
-	tx, err := dbConnPool.Begin(ctx)
+	tx, err := tracing.BeginTx(ctx, dbConnPool)
 	if err != nil {
 		return nil, nil, err
 	}
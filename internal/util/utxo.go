@@ -0,0 +1,179 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+type utxoAddressType int
+
+const (
+	utxoAddressTypeP2PKH utxoAddressType = iota
+	utxoAddressTypeP2SHP2WPKH
+	utxoAddressTypeP2WPKH
+)
+
+type utxoExtendedPubKeyVersion struct {
+	version  [4]byte
+	addrType utxoAddressType
+	mainnet  bool
+}
+
+// UtxoChainParams describes a bitcoind-like chain: the extended public key versions
+// it accepts and the address params of every network its daemon may report.
+type UtxoChainParams struct {
+	extendedPubKeyVersions []utxoExtendedPubKeyVersion
+	networks               map[string]*chaincfg.Params
+	mainnet                *chaincfg.Params
+}
+
+var (
+	// SLIP-0132 extended public key version bytes
+	xpubVersion utxoExtendedPubKeyVersion = utxoExtendedPubKeyVersion{version: [4]byte{0x04, 0x88, 0xb2, 0x1e}, addrType: utxoAddressTypeP2PKH, mainnet: true}
+	ypubVersion utxoExtendedPubKeyVersion = utxoExtendedPubKeyVersion{version: [4]byte{0x04, 0x9d, 0x7c, 0xb2}, addrType: utxoAddressTypeP2SHP2WPKH, mainnet: true}
+	zpubVersion utxoExtendedPubKeyVersion = utxoExtendedPubKeyVersion{version: [4]byte{0x04, 0xb2, 0x47, 0x46}, addrType: utxoAddressTypeP2WPKH, mainnet: true}
+	tpubVersion utxoExtendedPubKeyVersion = utxoExtendedPubKeyVersion{version: [4]byte{0x04, 0x35, 0x87, 0xcf}, addrType: utxoAddressTypeP2PKH, mainnet: false}
+	upubVersion utxoExtendedPubKeyVersion = utxoExtendedPubKeyVersion{version: [4]byte{0x04, 0x4a, 0x52, 0x62}, addrType: utxoAddressTypeP2SHP2WPKH, mainnet: false}
+	vpubVersion utxoExtendedPubKeyVersion = utxoExtendedPubKeyVersion{version: [4]byte{0x04, 0x5f, 0x1c, 0xf6}, addrType: utxoAddressTypeP2WPKH, mainnet: false}
+
+	LtcMainNetParams chaincfg.Params = chaincfg.Params{
+		Name:             "litecoin-mainnet",
+		Bech32HRPSegwit:  "ltc",
+		PubKeyHashAddrID: 0x30,
+		ScriptHashAddrID: 0x32,
+		HDPublicKeyID:    [4]byte{0x01, 0x9d, 0xa4, 0x62},
+	}
+	LtcTestNetParams chaincfg.Params = chaincfg.Params{
+		Name:             "litecoin-testnet",
+		Bech32HRPSegwit:  "tltc",
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0x3a,
+		HDPublicKeyID:    [4]byte{0x04, 0x36, 0xf6, 0xe1},
+	}
+	LtcRegressionNetParams chaincfg.Params = chaincfg.Params{
+		Name:             "litecoin-regtest",
+		Bech32HRPSegwit:  "rltc",
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0x3a,
+		HDPublicKeyID:    [4]byte{0x04, 0x36, 0xf6, 0xe1},
+	}
+
+	BtcChainParams UtxoChainParams = UtxoChainParams{
+		extendedPubKeyVersions: []utxoExtendedPubKeyVersion{xpubVersion, ypubVersion, zpubVersion, tpubVersion, upubVersion, vpubVersion},
+		networks: map[string]*chaincfg.Params{
+			"main":    &chaincfg.MainNetParams,
+			"test":    &chaincfg.TestNet3Params,
+			"signet":  &chaincfg.SigNetParams,
+			"regtest": &chaincfg.RegressionNetParams,
+		},
+		mainnet: &chaincfg.MainNetParams,
+	}
+	LtcChainParams UtxoChainParams = UtxoChainParams{
+		extendedPubKeyVersions: []utxoExtendedPubKeyVersion{
+			{version: [4]byte{0x01, 0x9d, 0xa4, 0x62}, addrType: utxoAddressTypeP2PKH, mainnet: true},      // Ltub
+			{version: [4]byte{0x01, 0xb2, 0x6e, 0xf6}, addrType: utxoAddressTypeP2SHP2WPKH, mainnet: true}, // Mtub
+			{version: [4]byte{0x04, 0x36, 0xf6, 0xe1}, addrType: utxoAddressTypeP2PKH, mainnet: false},     // ttub
+			// Most LTC wallets export keys with the BTC version bytes
+			xpubVersion, ypubVersion, zpubVersion, tpubVersion, upubVersion, vpubVersion,
+		},
+		networks: map[string]*chaincfg.Params{
+			"main":    &LtcMainNetParams,
+			"test":    &LtcTestNetParams,
+			"regtest": &LtcRegressionNetParams,
+		},
+		mainnet: &LtcMainNetParams,
+	}
+
+	invalidUtxoMasterPubKeyErr     error = errors.New("invalid master public key")
+	privateUtxoMasterPubKeyErr     error = errors.New("master public key must not be private")
+	utxoMasterPubKeyNetworkErr     error = errors.New("master public key doesn't belong to the daemon network")
+	unsupportedUtxoChainErr        error = errors.New("unsupported chain")
+	unsupportedUtxoMasterPubKeyErr error = errors.New("unsupported master public key version")
+)
+
+func (c *UtxoChainParams) findExtendedPubKeyVersion(key *hdkeychain.ExtendedKey) (*utxoExtendedPubKeyVersion, error) {
+	for i := 0; i < len(c.extendedPubKeyVersions); i++ {
+		if bytes.Equal(c.extendedPubKeyVersions[i].version[:], key.Version()) {
+			return &c.extendedPubKeyVersions[i], nil
+		}
+	}
+
+	return nil, unsupportedUtxoMasterPubKeyErr
+}
+
+// NewMasterPubKey parses an account level extended public key (e.g. xpub/ypub/zpub or Ltub/Mtub).
+func (c *UtxoChainParams) NewMasterPubKey(key string) (*hdkeychain.ExtendedKey, error) {
+	k, err := hdkeychain.NewKeyFromString(key)
+	if err != nil {
+		return nil, errors.Join(invalidUtxoMasterPubKeyErr, err)
+	}
+	if k.IsPrivate() {
+		return nil, privateUtxoMasterPubKeyErr
+	}
+	if _, err := c.findExtendedPubKeyVersion(k); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// GenerateAddress derives the address at m/<account path>/major/minor.
+// The address type (P2PKH, P2SH-P2WPKH or P2WPKH) is inferred from the key version bytes.
+func (c *UtxoChainParams) GenerateAddress(masterPubKey *hdkeychain.ExtendedKey, major uint32, minor uint32, net *chaincfg.Params) (btcutil.Address, error) {
+	v, err := c.findExtendedPubKeyVersion(masterPubKey)
+	if err != nil {
+		return nil, err
+	}
+	if v.mainnet != (net == c.mainnet) {
+		return nil, utxoMasterPubKeyNetworkErr
+	}
+
+	branch, err := masterPubKey.Derive(major)
+	if err != nil {
+		return nil, err
+	}
+	child, err := branch.Derive(minor)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := child.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	pubKeyHash := btcutil.Hash160(pubKey.SerializeCompressed())
+
+	switch v.addrType {
+	case utxoAddressTypeP2PKH:
+		return btcutil.NewAddressPubKeyHash(pubKeyHash, net)
+	case utxoAddressTypeP2SHP2WPKH:
+		witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, net)
+		if err != nil {
+			return nil, err
+		}
+		redeemScript, err := txscript.PayToAddrScript(witnessAddr)
+		if err != nil {
+			return nil, err
+		}
+		return btcutil.NewAddressScriptHash(redeemScript, net)
+	case utxoAddressTypeP2WPKH:
+		return btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, net)
+	}
+
+	return nil, unsupportedUtxoMasterPubKeyErr
+}
+
+// NetworkParams maps the chain reported by getblockchaininfo to the network params.
+func (c *UtxoChainParams) NetworkParams(chain string) (*chaincfg.Params, error) {
+	net, ok := c.networks[chain]
+	if !ok {
+		return nil, unsupportedUtxoChainErr
+	}
+
+	return net, nil
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// BIP-84 test vector account m/84'/0'/0'
+	bip84Zpub string = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	bip84Zprv string = "zprvAWgYBBk7JR8Gjrh4UJQ2uJdG1r3WNRRfURiABBE3RvMXYSrRJL62XuezvGdPvG6GFBZduosCc1YP5wixPox7zhZLfiUm8aunE96BBa4Kei5"
+)
+
+func withExtendedKeyVersion(t *testing.T, key string, version []byte) string {
+	k, err := hdkeychain.NewKeyFromString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k1, err := k.CloneWithVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return k1.String()
+}
+
+func TestNewUtxoMasterPubKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should Return Valid Key", func(t *testing.T) {
+		_, err := BtcChainParams.NewMasterPubKey(bip84Zpub)
+		assert.NoError(t, err)
+
+		_, err = LtcChainParams.NewMasterPubKey(bip84Zpub)
+		assert.NoError(t, err)
+
+		_, err = LtcChainParams.NewMasterPubKey(withExtendedKeyVersion(t, bip84Zpub, LtcMainNetParams.HDPublicKeyID[:]))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should Return Error (private key)", func(t *testing.T) {
+		_, err := BtcChainParams.NewMasterPubKey(bip84Zprv)
+		assert.ErrorIs(t, err, privateUtxoMasterPubKeyErr)
+	})
+
+	t.Run("Should Return Error (invalid key)", func(t *testing.T) {
+		_, err := BtcChainParams.NewMasterPubKey("zpub")
+		assert.ErrorIs(t, err, invalidUtxoMasterPubKeyErr)
+	})
+
+	t.Run("Should Return Error (unsupported version)", func(t *testing.T) {
+		_, err := BtcChainParams.NewMasterPubKey(withExtendedKeyVersion(t, bip84Zpub, LtcMainNetParams.HDPublicKeyID[:]))
+		assert.ErrorIs(t, err, unsupportedUtxoMasterPubKeyErr)
+	})
+}
+
+func TestGenerateUtxoAddress(t *testing.T) {
+	t.Parallel()
+
+	key, err := BtcChainParams.NewMasterPubKey(bip84Zpub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should Return Valid BTC P2WPKH Addresses", func(t *testing.T) {
+		addr, err := BtcChainParams.GenerateAddress(key, 0, 0, &chaincfg.MainNetParams)
+		assert.NoError(t, err)
+		assert.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", addr.EncodeAddress())
+
+		addr, err = BtcChainParams.GenerateAddress(key, 0, 1, &chaincfg.MainNetParams)
+		assert.NoError(t, err)
+		assert.Equal(t, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", addr.EncodeAddress())
+
+		addr, err = BtcChainParams.GenerateAddress(key, 1, 0, &chaincfg.MainNetParams)
+		assert.NoError(t, err)
+		assert.Equal(t, "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el", addr.EncodeAddress())
+	})
+
+	t.Run("Should Return Valid LTC Addresses", func(t *testing.T) {
+		btcAddr, err := BtcChainParams.GenerateAddress(key, 0, 0, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		addr, err := LtcChainParams.GenerateAddress(key, 0, 0, &LtcMainNetParams)
+		assert.NoError(t, err)
+		assert.Regexp(t, "^ltc1q", addr.EncodeAddress())
+		assert.True(t, addr.IsForNet(&LtcMainNetParams))
+		assert.Equal(t, btcAddr.ScriptAddress(), addr.ScriptAddress())
+
+		ltubKey, err := LtcChainParams.NewMasterPubKey(withExtendedKeyVersion(t, bip84Zpub, LtcMainNetParams.HDPublicKeyID[:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err = LtcChainParams.GenerateAddress(ltubKey, 0, 0, &LtcMainNetParams)
+		assert.NoError(t, err)
+		assert.Regexp(t, "^L", addr.EncodeAddress())
+		assert.Equal(t, btcAddr.ScriptAddress(), addr.ScriptAddress())
+	})
+
+	t.Run("Should Return Error (network mismatch)", func(t *testing.T) {
+		_, err := BtcChainParams.GenerateAddress(key, 0, 0, &chaincfg.TestNet3Params)
+		assert.ErrorIs(t, err, utxoMasterPubKeyNetworkErr)
+
+		_, err = LtcChainParams.GenerateAddress(key, 0, 0, &LtcTestNetParams)
+		assert.ErrorIs(t, err, utxoMasterPubKeyNetworkErr)
+	})
+}
+
+func TestUtxoNetworkParams(t *testing.T) {
+	t.Parallel()
+
+	net, err := LtcChainParams.NetworkParams("main")
+	assert.NoError(t, err)
+	assert.Equal(t, &LtcMainNetParams, net)
+
+	_, err = LtcChainParams.NetworkParams("signet")
+	assert.ErrorIs(t, err, unsupportedUtxoChainErr)
+}
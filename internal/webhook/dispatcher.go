@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DISPATCH_INTERVAL time.Duration = 5 * time.Second
+	// An event is given up on after this many failed deliveries
+	MAX_DELIVERY_ATTEMPTS int32 = 10
+
+	delivery_timeout   time.Duration = 10 * time.Second
+	min_retry_interval time.Duration = 10 * time.Second
+	max_retry_interval time.Duration = 6 * time.Hour
+)
+
+// retryInterval returns how long to wait before the next delivery after the given number of failed ones,
+// doubling from min_retry_interval up to max_retry_interval.
+func retryInterval(attempts int32) time.Duration {
+	interval := min_retry_interval
+	for i := int32(1); i < attempts; i++ {
+		interval *= 2
+		if interval >= max_retry_interval {
+			return max_retry_interval
+		}
+	}
+
+	return interval
+}
+
+// Dispatcher delivers the outbox events to the webhook endpoints.
+type Dispatcher struct {
+	log *zerolog.Logger
+
+	dbConnPool *pgxpool.Pool
+	client     *http.Client
+}
+
+// deliver POSTs the signed body and returns the response status code, 0 if there was no response.
+func (d *Dispatcher) deliver(ctx context.Context, url string, secret string, eventId string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EVENT_ID_HEADER, eventId)
+	req.Header.Set(TIMESTAMP_HEADER, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SIGNATURE_HEADER, "sha256="+Sign(secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status code %v", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// dispatchNext delivers the next due event. It returns false if there was none.
+func (d *Dispatcher) dispatchNext(ctx context.Context) bool {
+	q, tx, err := util.InitDbQueriesWithTx(ctx, d.dbConnPool)
+	if err != nil {
+		d.log.Err(err).Msg(util.DefaultFailedSqlTxInitMsg)
+		return false
+	}
+
+	// The row stays locked during the delivery, so concurrent dispatchers skip it
+	event, err := q.FindDueWebhookOutboxEventAndLock(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			d.log.Err(err).Str("queryName", "FindDueWebhookOutboxEventAndLock").Msg(util.DefaultFailedSqlQueryMsg)
+		}
+		return false
+	}
+
+	deliveryCtx, cancel := context.WithTimeout(ctx, delivery_timeout)
+	statusCode, deliveryErr := d.deliver(deliveryCtx, event.Url, event.Secret, util.PgUUIDToString(event.ID), event.Payload)
+	cancel()
+
+	attempt := db.CreateWebhookDeliveryAttemptParams{OutboxID: event.ID}
+	if statusCode != 0 {
+		attempt.StatusCode = pgtype.Int4{Int32: int32(statusCode), Valid: true}
+	}
+	if deliveryErr != nil {
+		attempt.Error = pgtype.Text{String: deliveryErr.Error(), Valid: true}
+	}
+	if _, err := q.CreateWebhookDeliveryAttempt(ctx, attempt); err != nil {
+		tx.Rollback(ctx)
+		d.log.Err(err).Str("queryName", "CreateWebhookDeliveryAttempt").Msg(util.DefaultFailedSqlQueryMsg)
+		return false
+	}
+
+	switch {
+	case deliveryErr == nil:
+		if _, err := q.DeliverWebhookOutboxEventById(ctx, event.ID); err != nil {
+			tx.Rollback(ctx)
+			d.log.Err(err).Str("queryName", "DeliverWebhookOutboxEventById").Msg(util.DefaultFailedSqlQueryMsg)
+			return false
+		}
+	case event.Attempts+1 >= MAX_DELIVERY_ATTEMPTS:
+		d.log.Warn().Err(deliveryErr).Str("eventId", util.PgUUIDToString(event.ID)).Msgf("Giving up on delivering the webhook event to %v", event.Url)
+
+		if _, err := q.FailWebhookOutboxEventById(ctx, event.ID); err != nil {
+			tx.Rollback(ctx)
+			d.log.Err(err).Str("queryName", "FailWebhookOutboxEventById").Msg(util.DefaultFailedSqlQueryMsg)
+			return false
+		}
+	default:
+		d.log.Debug().Err(deliveryErr).Str("eventId", util.PgUUIDToString(event.ID)).Msgf("Failed to deliver the webhook event to %v", event.Url)
+
+		var nextAttemptAt pgtype.Timestamptz
+		if err := nextAttemptAt.Scan(time.Now().UTC().Add(retryInterval(event.Attempts + 1))); err != nil {
+			tx.Rollback(ctx)
+			d.log.Err(err).Str("fieldName", "nextAttemptAt").Msg(util.DefaultFailedScanningToPostgresqlDataTypeMsg)
+			return false
+		}
+
+		if _, err := q.RescheduleWebhookOutboxEventById(ctx, db.RescheduleWebhookOutboxEventByIdParams{ID: event.ID, NextAttemptAt: nextAttemptAt}); err != nil {
+			tx.Rollback(ctx)
+			d.log.Err(err).Str("queryName", "RescheduleWebhookOutboxEventById").Msg(util.DefaultFailedSqlQueryMsg)
+			return false
+		}
+	}
+
+	tx.Commit(ctx)
+
+	return true
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if !d.dispatchNext(ctx) {
+				return
+			}
+		}
+	}
+}
+
+// Start delivers the due events every DISPATCH_INTERVAL until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) {
+	t := time.NewTicker(DISPATCH_INTERVAL)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+func NewDispatcher(dbConnPool *pgxpool.Pool, client *http.Client, log *zerolog.Logger) *Dispatcher {
+	return &Dispatcher{log: log, dbConnPool: dbConnPool, client: client}
+}
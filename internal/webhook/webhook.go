@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/util"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	SIGNATURE_HEADER string = "X-Goipay-Signature"
+	TIMESTAMP_HEADER string = "X-Goipay-Timestamp"
+	EVENT_ID_HEADER  string = "X-Goipay-Event-Id"
+
+	INVOICE_CONFIRMED_EVENT string = "invoice.confirmed"
+	INVOICE_EXPIRED_EVENT   string = "invoice.expired"
+
+	secret_length int = 32
+)
+
+var (
+	// InvalidUrlErr is returned for webhook urls which aren't absolute http(s) ones.
+	InvalidUrlErr error = errors.New("invalid webhook url")
+)
+
+// payload is the JSON body POSTed to the webhook endpoints.
+type payload struct {
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Invoice   json.RawMessage `json:"invoice"`
+}
+
+// ValidateUrl checks that the url can be used as a webhook endpoint.
+func ValidateUrl(rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return InvalidUrlErr
+	}
+
+	return nil
+}
+
+// NewSecret generates a random hex-encoded signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, secret_length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret.
+// Receivers recompute it to verify the SIGNATURE_HEADER, the timestamp comes from the TIMESTAMP_HEADER.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Enqueue writes the event to the outbox of every webhook of the invoice owner.
+// It's meant to be called within the transaction changing the invoice status.
+func Enqueue(ctx context.Context, q *db.Queries, event string, invoice *db.Invoice) error {
+	payments, err := q.FindAllInvoicePaymentsByInvoiceId(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+
+	invoiceJson, err := protojson.Marshal(util.DbInvoiceToPbInvoice(invoice, payments))
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload{Event: event, CreatedAt: time.Now().UTC(), Invoice: invoiceJson})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateWebhookOutboxEventsByUserId(ctx, db.CreateWebhookOutboxEventsByUserIdParams{UserID: invoice.UserID, InvoiceID: invoice.ID, Payload: body})
+	return err
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUrl(t *testing.T) {
+	for _, u := range []string{"http://localhost:8080/hook", "https://example.com/webhook?token=1"} {
+		assert.NoError(t, ValidateUrl(u), u)
+	}
+
+	for _, u := range []string{"", "example.com/webhook", "ftp://example.com", "https://", "://example.com"} {
+		assert.ErrorIs(t, ValidateUrl(u), InvalidUrlErr, u)
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	s1, err := NewSecret()
+	assert.NoError(t, err)
+	s2, err := NewSecret()
+	assert.NoError(t, err)
+
+	assert.Len(t, s1, 2*secret_length)
+	assert.NotEqual(t, s1, s2)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"event":"invoice.confirmed"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "d22be21709f1f803f5fa7032390164af2d4e361231df55697690ced427c366ce", Sign("secret", 1700000000, []byte(`{"event":"invoice.confirmed"}`)))
+	assert.NotEqual(t, Sign("secret", 1700000000, []byte("body")), Sign("secret", 1700000001, []byte("body")))
+	assert.NotEqual(t, Sign("secret", 1700000000, []byte("body")), Sign("other", 1700000000, []byte("body")))
+}
+
+func TestRetryInterval(t *testing.T) {
+	assert.Equal(t, min_retry_interval, retryInterval(1))
+	assert.Equal(t, 2*min_retry_interval, retryInterval(2))
+	assert.Equal(t, 8*min_retry_interval, retryInterval(4))
+	assert.Equal(t, max_retry_interval, retryInterval(MAX_DELIVERY_ATTEMPTS*10))
+}
+
+func TestDeliver(t *testing.T) {
+	secret := uuid.NewString()
+	eventId := uuid.NewString()
+	body := []byte(`{"event":"invoice.confirmed"}`)
+
+	t.Run("Should Deliver Signed Payload", func(t *testing.T) {
+		received := make(chan *http.Request, 1)
+		receivedBody := make(chan []byte, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			received <- r
+			receivedBody <- b
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		d := NewDispatcher(nil, srv.Client(), &zerolog.Logger{})
+		statusCode, err := d.deliver(context.Background(), srv.URL, secret, eventId, body)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+
+		var r *http.Request
+		select {
+		case r = <-received:
+		case <-time.After(time.Second):
+			t.Fatal("Timeout has been expired")
+		}
+		b := <-receivedBody
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(TIMESTAMP_HEADER), 10, 64)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, eventId, r.Header.Get(EVENT_ID_HEADER))
+		assert.Equal(t, "sha256="+Sign(secret, timestamp, b), r.Header.Get(SIGNATURE_HEADER))
+		assert.Equal(t, body, b)
+	})
+
+	t.Run("Should Return Error For Non 2xx Response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		d := NewDispatcher(nil, srv.Client(), &zerolog.Logger{})
+		statusCode, err := d.deliver(context.Background(), srv.URL, secret, eventId, body)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusInternalServerError, statusCode)
+	})
+
+	t.Run("Should Return Error Without Response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		srv.Close()
+
+		d := NewDispatcher(nil, srv.Client(), &zerolog.Logger{})
+		statusCode, err := d.deliver(context.Background(), srv.URL, secret, eventId, body)
+		assert.Error(t, err)
+		assert.Equal(t, 0, statusCode)
+	})
+}
@@ -3,8 +3,9 @@
 protoPathDir="../proto"
 protoGoOutDir="../internal/pb"
 protoGoGrpcOutDir=$protoGoOutDir
+openApiOutDir="../docs/openapi"
 
-while getopts ":i:o:" opt
+while getopts ":i:o:a:" opt
 do
   case "$opt" in
     i)
@@ -14,6 +15,9 @@ do
       protoGoOutDir="$OPTARG"
       protoGoGrpcOutDir=$protoGoOutDir
       ;;
+    a)
+      openApiOutDir="$OPTARG"
+      ;;
     ?)
       echo "Invalid option."
       exit 1
@@ -33,24 +37,66 @@ while (( $# > 0 ))
 do
     v=$1
     mkdir -p $protoGoOutDir/$v
-    
-    opts=""
-    inputs=""
-    for pb in $(find "$protoPathDir/$v" -name '*.proto')
-    do
-        pb=$(basename "$pb")
-        opts+="--go-grpc_opt=M$pb=$protoGoOutDir/$v --go_opt=M$pb=$protoGoOutDir/$v "
-        inputs+="$protoPathDir/$v/$pb "
-    done
-
-    protoc --proto_path="$protoPathDir/$v" \
-        --go_out="$protoGoOutDir/$v" \
-        --go-grpc_out="$protoGoGrpcOutDir/$v" \
-        --go_opt="M$pb=$protoGoOutDir/$v" \
-        --go_opt=paths=source_relative \
-        --go-grpc_opt=paths=source_relative \
-        $opts \
-        $inputs
-        
+    mkdir -p $openApiOutDir
+
+    if [[ $v == "v1" ]]
+    then
+        opts=""
+        inputs=""
+        for pb in $(find "$protoPathDir/$v" -name '*.proto')
+        do
+            pb=$(basename "$pb")
+            opts+="--go-grpc_opt=M$pb=$protoGoOutDir/$v --go_opt=M$pb=$protoGoOutDir/$v "
+            opts+="--grpc-gateway_opt=M$pb=$protoGoOutDir/$v --openapiv2_opt=M$pb=$protoGoOutDir/$v "
+            inputs+="$protoPathDir/$v/$pb "
+        done
+
+        protoc --proto_path="$protoPathDir/$v" \
+            --proto_path="$protoPathDir/third_party" \
+            --go_out="$protoGoOutDir/$v" \
+            --go-grpc_out="$protoGoGrpcOutDir/$v" \
+            --grpc-gateway_out="$protoGoOutDir/$v" \
+            --openapiv2_out="$openApiOutDir" \
+            --go_opt="M$pb=$protoGoOutDir/$v" \
+            --go_opt=paths=source_relative \
+            --go-grpc_opt=paths=source_relative \
+            --grpc-gateway_opt=paths=source_relative \
+            --openapiv2_opt=allow_merge=true,merge_file_name=goipay \
+            $opts \
+            $inputs
+    else
+        # The later versions are registered as <version>/<file>.proto, so their names don't clash with the v1 ones,
+        # and import the shared v1 messages, e.g. crypto.proto
+        v1Opts="--go_opt=Mcrypto.proto=github.com/chekist32/goipay/internal/pb/v1 "
+        v1Opts+="--go-grpc_opt=Mcrypto.proto=github.com/chekist32/goipay/internal/pb/v1 "
+        v1Opts+="--grpc-gateway_opt=Mcrypto.proto=github.com/chekist32/goipay/internal/pb/v1 "
+        v1Opts+="--openapiv2_opt=Mcrypto.proto=github.com/chekist32/goipay/internal/pb/v1 "
+
+        opts=""
+        inputs=""
+        for pb in $(find "$protoPathDir/$v" -name '*.proto')
+        do
+            pb=$v/$(basename "$pb")
+            opts+="--go-grpc_opt=M$pb=$protoGoOutDir/$v --go_opt=M$pb=$protoGoOutDir/$v "
+            opts+="--grpc-gateway_opt=M$pb=$protoGoOutDir/$v --openapiv2_opt=M$pb=$protoGoOutDir/$v "
+            inputs+="$protoPathDir/$pb "
+        done
+
+        protoc --proto_path="$protoPathDir" \
+            --proto_path="$protoPathDir/v1" \
+            --proto_path="$protoPathDir/third_party" \
+            --go_out="$protoGoOutDir" \
+            --go-grpc_out="$protoGoGrpcOutDir" \
+            --grpc-gateway_out="$protoGoOutDir" \
+            --openapiv2_out="$openApiOutDir" \
+            --go_opt=paths=source_relative \
+            --go-grpc_opt=paths=source_relative \
+            --grpc-gateway_opt=paths=source_relative \
+            --openapiv2_opt=allow_merge=true,merge_file_name=goipay_$v \
+            $v1Opts \
+            $opts \
+            $inputs
+    fi
+
     shift
 done
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS eth_crypto_data(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    master_pub_key TEXT NOT NULL UNIQUE,
+    last_major_index INTEGER NOT NULL DEFAULT 0,
+    last_minor_index INTEGER NOT NULL DEFAULT 0
+);
+
+ALTER TABLE crypto_data ADD COLUMN eth_id UUID REFERENCES eth_crypto_data (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE crypto_data DROP COLUMN eth_id;
+
+DROP TABLE eth_crypto_data CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL for the native coin of the chain
+ALTER TABLE invoices ADD COLUMN token_contract TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN token_contract;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS ton_crypto_data(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    address TEXT NOT NULL UNIQUE
+);
+
+ALTER TABLE crypto_data ADD COLUMN ton_id UUID REFERENCES ton_crypto_data (id);
+
+ALTER TABLE invoices ADD COLUMN memo TEXT UNIQUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN memo;
+
+ALTER TABLE crypto_data DROP COLUMN ton_id;
+
+DROP TABLE ton_crypto_data CASCADE;
+-- +goose StatementEnd
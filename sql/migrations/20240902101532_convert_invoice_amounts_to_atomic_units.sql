@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS decimals SMALLINT;
+
+-- The decimals of a token aren't stored with its invoices and guessing them would corrupt the amounts,
+-- so they have to be set by hand before migrating, e.g. for USDT:
+--   ALTER TABLE invoices ADD COLUMN IF NOT EXISTS decimals SMALLINT;
+--   UPDATE invoices SET decimals = 6 WHERE token_contract = '0xdac17f958d2ee523a2206206994597c13d831ec7';
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM invoices WHERE token_contract IS NOT NULL AND decimals IS NULL) THEN
+        RAISE EXCEPTION 'The decimals of the ERC-20 token invoices are unknown. Set invoices.decimals of every row with a token_contract by hand and run the migration again.';
+    END IF;
+END $$;
+
+UPDATE invoices
+SET decimals = CASE
+    WHEN coin = 'XMR' THEN 12
+    WHEN coin IN ('BTC', 'LTC') THEN 8
+    WHEN coin = 'ETH' THEN 18
+    WHEN coin = 'TON' THEN 9
+END
+WHERE decimals IS NULL;
+
+ALTER TABLE invoices ALTER COLUMN decimals SET NOT NULL;
+
+ALTER TABLE invoices
+    ALTER COLUMN required_amount TYPE NUMERIC(78, 0) USING round(required_amount::NUMERIC * power(10::NUMERIC, decimals)),
+    ALTER COLUMN actual_amount TYPE NUMERIC(78, 0) USING round(actual_amount::NUMERIC * power(10::NUMERIC, decimals));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices
+    ALTER COLUMN required_amount TYPE DOUBLE PRECISION USING (required_amount / power(10::NUMERIC, decimals))::DOUBLE PRECISION,
+    ALTER COLUMN actual_amount TYPE DOUBLE PRECISION USING (actual_amount / power(10::NUMERIC, decimals))::DOUBLE PRECISION;
+
+ALTER TABLE invoices DROP COLUMN decimals;
+-- +goose StatementEnd
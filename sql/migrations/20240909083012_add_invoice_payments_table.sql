@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE invoice_status_type ADD VALUE 'PARTIALLY_PAID' AFTER 'PENDING';
+
+CREATE TABLE IF NOT EXISTS invoice_payments(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES invoices (id),
+    tx_id TEXT NOT NULL,
+    output_index INTEGER NOT NULL,
+    amount NUMERIC(78, 0) NOT NULL,
+    block_height BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('UTC', now()),
+    UNIQUE (invoice_id, tx_id, output_index)
+);
+
+-- Invoices paid before the table existed were paid by a single tx.
+-- The output index of such payments is unknown, so it is recorded as 0.
+INSERT INTO invoice_payments(invoice_id, tx_id, output_index, amount, created_at)
+SELECT id, tx_id, 0, actual_amount, created_at FROM invoices
+WHERE tx_id IS NOT NULL AND actual_amount IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE invoice_payments CASCADE;
+
+UPDATE invoices SET status = 'PENDING' WHERE status = 'PARTIALLY_PAID';
+
+ALTER TYPE invoice_status_type RENAME TO invoice_status_type_old;
+CREATE TYPE invoice_status_type AS ENUM (
+  'PENDING',
+  'PENDING_MEMPOOL',
+  'EXPIRED',
+  'CONFIRMED'
+);
+ALTER TABLE invoices ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE invoices ALTER COLUMN status TYPE invoice_status_type USING status::TEXT::invoice_status_type;
+ALTER TABLE invoices ALTER COLUMN status SET DEFAULT 'PENDING';
+DROP TYPE invoice_status_type_old;
+-- +goose StatementEnd
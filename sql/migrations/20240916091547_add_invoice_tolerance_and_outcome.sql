@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE invoice_outcome_type AS ENUM (
+  'EXACT',
+  'UNDERPAID',
+  'OVERPAID'
+);
+
+ALTER TABLE invoices ADD COLUMN tolerance_amount NUMERIC(78, 0) NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN amount_delta NUMERIC(78, 0);
+ALTER TABLE invoices ADD COLUMN outcome invoice_outcome_type;
+
+UPDATE invoices
+SET amount_delta = actual_amount - required_amount,
+    outcome = CASE
+        WHEN actual_amount > required_amount THEN 'OVERPAID'::invoice_outcome_type
+        WHEN actual_amount < required_amount THEN 'UNDERPAID'::invoice_outcome_type
+        ELSE 'EXACT'::invoice_outcome_type
+    END
+WHERE status IN ('PENDING_MEMPOOL', 'CONFIRMED') AND actual_amount IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN outcome;
+ALTER TABLE invoices DROP COLUMN amount_delta;
+ALTER TABLE invoices DROP COLUMN tolerance_amount;
+
+DROP TYPE invoice_outcome_type;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE invoice_status_type ADD VALUE 'PAID_AFTER_EXPIRY';
+
+ALTER TABLE invoices ADD COLUMN expired_at TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN expired_at;
+
+UPDATE invoices SET status = 'EXPIRED' WHERE status = 'PAID_AFTER_EXPIRY';
+
+ALTER TYPE invoice_status_type RENAME TO invoice_status_type_old;
+CREATE TYPE invoice_status_type AS ENUM (
+  'PENDING',
+  'PARTIALLY_PAID',
+  'PENDING_MEMPOOL',
+  'EXPIRED',
+  'CONFIRMED'
+);
+ALTER TABLE invoices ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE invoices ALTER COLUMN status TYPE invoice_status_type USING status::TEXT::invoice_status_type;
+ALTER TABLE invoices ALTER COLUMN status SET DEFAULT 'PENDING';
+DROP TYPE invoice_status_type_old;
+-- +goose StatementEnd
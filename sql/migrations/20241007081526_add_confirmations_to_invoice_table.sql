@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN confirmations SMALLINT NOT NULL DEFAULT 0;
+
+UPDATE invoices SET confirmations = confirmations_required WHERE status = 'CONFIRMED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN confirmations;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS invoice_events(
+    sequence BIGSERIAL PRIMARY KEY,
+    invoice_id UUID NOT NULL REFERENCES invoices (id),
+    status invoice_status_type NOT NULL,
+    -- The invoice as it was right after the transition
+    invoice JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('UTC', now())
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE invoice_events;
+-- +goose StatementEnd
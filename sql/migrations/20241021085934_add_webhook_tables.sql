@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE webhook_delivery_status_type AS ENUM (
+  'PENDING',
+  'DELIVERED',
+  'FAILED'
+);
+
+CREATE TABLE IF NOT EXISTS webhooks(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id),
+    url TEXT NOT NULL,
+    -- HMAC-SHA256 key the payloads are signed with
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('UTC', now())
+);
+
+CREATE TABLE IF NOT EXISTS webhook_outbox(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL REFERENCES invoices (id),
+    payload JSONB NOT NULL,
+    status webhook_delivery_status_type NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('UTC', now()),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('UTC', now())
+);
+CREATE INDEX webhook_outbox_pending_idx ON webhook_outbox (next_attempt_at) WHERE status = 'PENDING';
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    outbox_id UUID NOT NULL REFERENCES webhook_outbox (id) ON DELETE CASCADE,
+    -- Not set if no response was received
+    status_code INTEGER,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('UTC', now())
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_delivery_attempts;
+DROP TABLE webhook_outbox;
+DROP TABLE webhooks;
+DROP TYPE webhook_delivery_status_type;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE api_key_role_type AS ENUM (
+  'USER',
+  'ADMIN'
+);
+
+CREATE TABLE IF NOT EXISTS api_keys(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- Hex SHA-256 of the key, the key itself is never stored
+    key_hash TEXT NOT NULL UNIQUE,
+    role api_key_role_type NOT NULL,
+    -- Set for USER keys only
+    user_id UUID REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('UTC', now()),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((role = 'ADMIN') = (user_id IS NULL))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_keys;
+DROP TYPE api_key_role_type;
+-- +goose StatementEnd
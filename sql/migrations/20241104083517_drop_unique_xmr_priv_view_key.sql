@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The private view keys are encrypted with random data keys, so equal keys don't compare equal anymore
+ALTER TABLE xmr_crypto_data DROP CONSTRAINT xmr_crypto_data_priv_view_key_key;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE xmr_crypto_data ADD CONSTRAINT xmr_crypto_data_priv_view_key_key UNIQUE (priv_view_key);
+-- +goose StatementEnd
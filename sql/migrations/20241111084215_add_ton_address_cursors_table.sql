@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The last transaction seen on a TON merchant address, the transactions after it are paged through on every block
+CREATE TABLE IF NOT EXISTS ton_address_cursors(
+    address TEXT PRIMARY KEY,
+    last_lt BIGINT NOT NULL,
+    last_hash TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE ton_address_cursors;
+-- +goose StatementEnd
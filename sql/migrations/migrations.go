@@ -0,0 +1,7 @@
+// Package migrations embeds the goose migrations, so the server is able to apply them itself.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
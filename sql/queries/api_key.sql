@@ -0,0 +1,18 @@
+-- name: CreateApiKey :one
+INSERT INTO api_keys(key_hash, role, user_id) VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: FindActiveApiKeyByKeyHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL;
+
+-- name: FindActiveApiKeyByIdForUpdate :one
+SELECT * FROM api_keys
+WHERE id = $1 AND revoked_at IS NULL
+FOR UPDATE;
+
+-- name: RevokeApiKeyById :one
+UPDATE api_keys
+SET revoked_at = timezone('UTC', now())
+WHERE id = $1 AND revoked_at IS NULL
+RETURNING *;
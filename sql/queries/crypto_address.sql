@@ -23,4 +23,17 @@ DELETE FROM crypto_addresses
 WHERE user_id = $1 AND coin = $2
 RETURNING *;
 
-
+-- name: ReleaseStuckCryptoAddresses :many
+UPDATE crypto_addresses AS ca
+SET is_occupied = false
+WHERE ca.is_occupied = true
+    AND (sqlc.narg('coin')::coin_type IS NULL OR ca.coin = sqlc.narg('coin'))
+    AND (sqlc.narg('address')::text IS NULL OR ca.address = sqlc.narg('address'))
+    AND NOT EXISTS (
+        SELECT 1 FROM invoices AS i
+        WHERE i.crypto_address = ca.address AND (
+            i.status IN ('PENDING', 'PARTIALLY_PAID', 'PENDING_MEMPOOL', 'PAID_AFTER_EXPIRY')
+            OR (i.status = 'EXPIRED' AND i.expired_at > sqlc.arg('expired_since'))
+        )
+    )
+RETURNING *;
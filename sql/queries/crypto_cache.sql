@@ -1,6 +1,9 @@
 -- name: FindCryptoCacheByCoin :one
 SELECT * FROM crypto_cache
 WHERE coin = $1;
+-- name: FindAllCryptoCaches :many
+SELECT * FROM crypto_cache
+ORDER BY coin;
 
 
 -- name: UpdateCryptoCacheByCoin :one
@@ -9,9 +9,17 @@ WHERE user_id = $1;
 -- name: FindCryptoKeysByUserId :one
 SELECT 
     COALESCE(xmr.priv_view_key, '') AS priv_view_key, 
-    COALESCE(xmr.pub_spend_key, '') AS pub_spend_key
+    COALESCE(xmr.pub_spend_key, '') AS pub_spend_key,
+    COALESCE(btc.master_pub_key, '') AS btc_master_pub_key,
+    COALESCE(ltc.master_pub_key, '') AS ltc_master_pub_key,
+    COALESCE(eth.master_pub_key, '') AS eth_master_pub_key,
+    COALESCE(ton.address, '') AS ton_address
 FROM crypto_data as cd
 LEFT JOIN xmr_crypto_data as xmr ON cd.xmr_id = xmr.id
+LEFT JOIN btc_crypto_data as btc ON cd.btc_id = btc.id
+LEFT JOIN ltc_crypto_data as ltc ON cd.ltc_id = ltc.id
+LEFT JOIN eth_crypto_data as eth ON cd.eth_id = eth.id
+LEFT JOIN ton_crypto_data as ton ON cd.ton_id = ton.id
 WHERE cd.user_id = $1;
 
 -- name: SetXMRCryptoDataByUserId :one
@@ -20,6 +28,30 @@ SET xmr_id = $2
 WHERE user_id = $1
 RETURNING *;
 
+-- name: SetBTCCryptoDataByUserId :one
+UPDATE crypto_data
+SET btc_id = $2 
+WHERE user_id = $1
+RETURNING *;
+
+-- name: SetLTCCryptoDataByUserId :one
+UPDATE crypto_data
+SET ltc_id = $2 
+WHERE user_id = $1
+RETURNING *;
+
+-- name: SetETHCryptoDataByUserId :one
+UPDATE crypto_data
+SET eth_id = $2 
+WHERE user_id = $1
+RETURNING *;
+
+-- name: SetTONCryptoDataByUserId :one
+UPDATE crypto_data
+SET ton_id = $2 
+WHERE user_id = $1
+RETURNING *;
+
 
 -- XMR
 -- name: CreateXMRCryptoData :one
@@ -41,6 +73,17 @@ SET priv_view_key = $2,
 WHERE id = $1
 RETURNING *;
 
+-- name: FindAllPrivViewKeysAndLockXMRCryptoData :many
+SELECT id, priv_view_key
+FROM xmr_crypto_data
+FOR UPDATE;
+
+-- name: UpdatePrivViewKeyXMRCryptoDataById :one
+UPDATE xmr_crypto_data
+SET priv_view_key = $2
+WHERE id = $1
+RETURNING *;
+
 -- name: FindIndicesAndLockXMRCryptoDataById :one
 SELECT last_major_index, last_minor_index 
 FROM xmr_crypto_data
@@ -52,4 +95,121 @@ UPDATE xmr_crypto_data
 SET last_major_index = $2,
     last_minor_index = $3
 WHERE id = $1
+RETURNING *;
+
+
+-- BTC
+-- name: CreateBTCCryptoData :one
+INSERT INTO btc_crypto_data(master_pub_key) VALUES ($1)
+RETURNING *;
+
+-- name: FindKeysAndLockBTCCryptoDataById :one
+SELECT master_pub_key
+FROM btc_crypto_data
+WHERE id = $1
+FOR SHARE;
+
+-- name: UpdateKeysBTCCryptoDataById :one
+UPDATE btc_crypto_data
+SET master_pub_key = $2,
+    last_major_index = 0,
+    last_minor_index = 0
+WHERE id = $1
+RETURNING *;
+
+-- name: FindIndicesAndLockBTCCryptoDataById :one
+SELECT last_major_index, last_minor_index 
+FROM btc_crypto_data
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateIndicesBTCCryptoDataById :one
+UPDATE btc_crypto_data
+SET last_major_index = $2,
+    last_minor_index = $3
+WHERE id = $1
+RETURNING *;
+
+
+-- LTC
+-- name: CreateLTCCryptoData :one
+INSERT INTO ltc_crypto_data(master_pub_key) VALUES ($1)
+RETURNING *;
+
+-- name: FindKeysAndLockLTCCryptoDataById :one
+SELECT master_pub_key
+FROM ltc_crypto_data
+WHERE id = $1
+FOR SHARE;
+
+-- name: UpdateKeysLTCCryptoDataById :one
+UPDATE ltc_crypto_data
+SET master_pub_key = $2,
+    last_major_index = 0,
+    last_minor_index = 0
+WHERE id = $1
+RETURNING *;
+
+-- name: FindIndicesAndLockLTCCryptoDataById :one
+SELECT last_major_index, last_minor_index 
+FROM ltc_crypto_data
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateIndicesLTCCryptoDataById :one
+UPDATE ltc_crypto_data
+SET last_major_index = $2,
+    last_minor_index = $3
+WHERE id = $1
+RETURNING *;
+
+
+-- ETH
+-- name: CreateETHCryptoData :one
+INSERT INTO eth_crypto_data(master_pub_key) VALUES ($1)
+RETURNING *;
+
+-- name: FindKeysAndLockETHCryptoDataById :one
+SELECT master_pub_key
+FROM eth_crypto_data
+WHERE id = $1
+FOR SHARE;
+
+-- name: UpdateKeysETHCryptoDataById :one
+UPDATE eth_crypto_data
+SET master_pub_key = $2,
+    last_major_index = 0,
+    last_minor_index = 0
+WHERE id = $1
+RETURNING *;
+
+-- name: FindIndicesAndLockETHCryptoDataById :one
+SELECT last_major_index, last_minor_index 
+FROM eth_crypto_data
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateIndicesETHCryptoDataById :one
+UPDATE eth_crypto_data
+SET last_major_index = $2,
+    last_minor_index = $3
+WHERE id = $1
+RETURNING *;
+
+
+-- TON
+-- name: CreateTONCryptoData :one
+INSERT INTO ton_crypto_data(address) VALUES ($1)
+RETURNING *;
+
+-- name: FindAddressAndLockTONCryptoDataById :one
+SELECT address
+FROM ton_crypto_data
+WHERE id = $1
+FOR SHARE;
+
+-- name: UpdateAddressTONCryptoDataById :one
+UPDATE ton_crypto_data
+SET address = $2
+WHERE id = $1
 RETURNING *;
\ No newline at end of file
@@ -5,42 +5,115 @@ INSERT INTO invoices(
     required_amount, 
     confirmations_required,
     expires_at,
-    user_id) 
-VALUES ($1, $2, $3, $4, $5, $6)
+    user_id,
+    token_contract,
+    memo,
+    decimals,
+    tolerance_amount) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING *;
 
 
 -- name: FindAllInvoicesByIds :many
 SELECT * FROM invoices
 WHERE id = ANY($1::uuid[]);
+-- name: FindAllInvoicesByFilter :many
+SELECT * FROM invoices
+WHERE (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+    AND (sqlc.narg('coin')::coin_type IS NULL OR coin = sqlc.narg('coin'))
+    AND (sqlc.narg('status')::invoice_status_type IS NULL OR status = sqlc.narg('status'))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit');
 -- name: FindAllPendingInvoices :many
 SELECT * FROM invoices
-WHERE status IN ('PENDING', 'PENDING_MEMPOOL');
+WHERE status IN ('PENDING', 'PARTIALLY_PAID', 'PENDING_MEMPOOL');
+-- name: FindAllInvoicesExpiredSince :many
+SELECT * FROM invoices
+WHERE status IN ('EXPIRED', 'PAID_AFTER_EXPIRY') AND expired_at > $1;
+-- name: FindInvoiceByIdAndLock :one
+SELECT * FROM invoices
+WHERE id = $1
+FOR UPDATE;
 
 
 -- name: ConfirmInvoiceById :one
 UPDATE invoices
 SET status = 'CONFIRMED',
+    confirmations = confirmations_required,
     confirmed_at = timezone('UTC', now())
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateConfirmationsInvoiceById :one
+UPDATE invoices
+SET confirmations = $2
+WHERE id = $1
+RETURNING *;
+
 -- name: ConfirmInvoiceStatusMempoolById :one
 UPDATE invoices
 SET actual_amount = $2,
     status = 'PENDING_MEMPOOL',
+    tx_id = $3,
+    amount_delta = $4,
+    outcome = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdatePaidAmountInvoiceById :one
+UPDATE invoices
+SET actual_amount = $2,
+    tx_id = $3,
+    amount_delta = $4,
+    outcome = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: PartiallyPayInvoiceById :one
+UPDATE invoices
+SET actual_amount = $2,
+    status = 'PARTIALLY_PAID',
     tx_id = $3
 WHERE id = $1
 RETURNING *;
 
+-- name: PayAfterExpiryInvoiceById :one
+UPDATE invoices
+SET actual_amount = $2,
+    status = 'PAID_AFTER_EXPIRY',
+    tx_id = $3,
+    amount_delta = $4,
+    outcome = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: UnconfirmInvoiceById :one
+UPDATE invoices
+SET status = 'PENDING_MEMPOOL',
+    confirmations = 0,
+    confirmed_at = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: ReorgInvoiceById :one
+UPDATE invoices
+SET status = 'REORGED'
+WHERE id = $1
+RETURNING *;
+
 -- name: ExpireInvoiceById :one
 UPDATE invoices
-SET status = 'EXPIRED'
+SET status = 'EXPIRED',
+    expired_at = timezone('UTC', now())
 WHERE id = $1
 RETURNING *;
 
 -- name: ShiftExpiresAtForNonConfirmedInvoices :many
 UPDATE invoices
 SET expires_at = timezone('UTC', now()) + INTERVAL '5 minute'
-WHERE status IN ('PENDING', 'PENDING_MEMPOOL') AND (expires_at - timezone('UTC', now()) < INTERVAL '5 minutes')
-RETURNING *;
\ No newline at end of file
+WHERE status IN ('PENDING', 'PARTIALLY_PAID', 'PENDING_MEMPOOL') AND (expires_at - timezone('UTC', now()) < INTERVAL '5 minutes')
+RETURNING *;
+
+-- name: CountInvoicesByCoinAndStatus :many
+SELECT coin, status, COUNT(*) AS count FROM invoices
+GROUP BY coin, status;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- name: CreateInvoiceEvent :one
+INSERT INTO invoice_events(invoice_id, status, invoice)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+
+-- name: FindAllInvoiceEventsFromSequence :many
+SELECT * FROM invoice_events
+WHERE sequence > $1
+ORDER BY sequence
+LIMIT $2;
+
+-- name: LockInvoiceEvents :exec
+-- Serializes the event inserts till the end of the tx, so the sequences are committed in order
+-- and a reader never sees a greater sequence before a lower one.
+SELECT pg_advisory_xact_lock(hashtext('invoice_events'));
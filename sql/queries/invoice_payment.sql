@@ -0,0 +1,42 @@
+-- name: CreateInvoicePayment :one
+INSERT INTO invoice_payments(
+    invoice_id,
+    tx_id,
+    output_index,
+    amount,
+    block_height)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (invoice_id, tx_id, output_index) DO NOTHING
+RETURNING *;
+
+
+-- name: FindAllInvoicePaymentsByInvoiceId :many
+SELECT * FROM invoice_payments
+WHERE invoice_id = $1
+ORDER BY created_at, id;
+-- name: FindAllInvoicePaymentsByInvoiceIds :many
+SELECT * FROM invoice_payments
+WHERE invoice_id = ANY($1::uuid[])
+ORDER BY created_at, id;
+
+-- name: SumAmountInvoicePaymentsByInvoiceId :one
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(78, 0) AS amount FROM invoice_payments
+WHERE invoice_id = $1;
+
+
+-- name: UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId :many
+UPDATE invoice_payments
+SET block_height = $3
+WHERE invoice_id = $1 AND tx_id = $2
+RETURNING *;
+-- name: ResetBlockHeightInvoicePaymentsByCoinFromBlockHeight :many
+UPDATE invoice_payments AS ip
+SET block_height = NULL
+FROM invoices AS i
+WHERE ip.invoice_id = i.id AND i.coin = $1 AND ip.block_height >= $2
+RETURNING ip.*;
+
+-- name: DeleteInvoicePaymentsByInvoiceIdAndTxIds :many
+DELETE FROM invoice_payments
+WHERE invoice_id = $1 AND tx_id = ANY(sqlc.arg(tx_ids)::text[])
+RETURNING *;
@@ -0,0 +1,11 @@
+-- name: FindTonAddressCursorByAddress :one
+SELECT * FROM ton_address_cursors
+WHERE address = $1;
+
+-- name: UpsertTonAddressCursor :exec
+INSERT INTO ton_address_cursors(address, last_lt, last_hash)
+VALUES ($1, $2, $3)
+ON CONFLICT (address) DO UPDATE
+SET last_lt = EXCLUDED.last_lt,
+    last_hash = EXCLUDED.last_hash
+WHERE ton_address_cursors.last_lt < EXCLUDED.last_lt;
@@ -0,0 +1,60 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks(user_id, url, secret) VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: FindAllWebhooksByUserId :many
+SELECT * FROM webhooks
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: DeleteWebhookByIdAndUserId :one
+DELETE FROM webhooks
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+
+-- name: CreateWebhookOutboxEventsByUserId :many
+INSERT INTO webhook_outbox(webhook_id, invoice_id, payload)
+SELECT w.id, $2, $3 FROM webhooks AS w
+WHERE w.user_id = $1
+RETURNING *;
+
+-- name: FindDueWebhookOutboxEventAndLock :one
+SELECT o.id, o.payload, o.attempts, w.url, w.secret FROM webhook_outbox AS o
+JOIN webhooks AS w ON w.id = o.webhook_id
+WHERE o.status = 'PENDING' AND o.next_attempt_at <= timezone('UTC', now())
+ORDER BY o.next_attempt_at
+LIMIT 1
+FOR UPDATE OF o SKIP LOCKED;
+
+-- name: DeliverWebhookOutboxEventById :one
+UPDATE webhook_outbox
+SET status = 'DELIVERED',
+    attempts = attempts + 1,
+    delivered_at = timezone('UTC', now())
+WHERE id = $1
+RETURNING *;
+
+-- name: RescheduleWebhookOutboxEventById :one
+UPDATE webhook_outbox
+SET attempts = attempts + 1,
+    next_attempt_at = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: FailWebhookOutboxEventById :one
+UPDATE webhook_outbox
+SET status = 'FAILED',
+    attempts = attempts + 1
+WHERE id = $1
+RETURNING *;
+
+
+-- name: CreateWebhookDeliveryAttempt :one
+INSERT INTO webhook_delivery_attempts(outbox_id, status_code, error) VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: FindAllWebhookDeliveryAttemptsByOutboxId :many
+SELECT * FROM webhook_delivery_attempts
+WHERE outbox_id = $1
+ORDER BY created_at;
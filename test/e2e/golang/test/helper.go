@@ -2,17 +2,56 @@ package e2e
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/docker/go-connections/nat"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+// Directory with ca.crt, server.crt, server.key, client.crt and client.key.
+// If set, goipay is run with mutual TLS, the server certificate must be valid for localhost.
+const tls_dir_env string = "E2E_TLS_DIR"
+
+// newGoipayClientConn dials goipay with mutual TLS if the tls_dir_env is set, in plaintext otherwise.
+func newGoipayClientConn(addr string) (*grpc.ClientConn, error) {
+	tlsDir := os.Getenv(tls_dir_env)
+	if tlsDir == "" {
+		return grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	caPem, err := os.ReadFile(filepath.Join(tlsDir, "ca.crt"))
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPem) {
+		return nil, errors.New("no certificates found in ca.crt")
+	}
+
+	cert, err := tls.LoadX509KeyPair(filepath.Join(tlsDir, "client.crt"), filepath.Join(tlsDir, "client.key"))
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{cert},
+		ServerName:   "localhost",
+	})))
+}
+
 func spinUpPostgresContainer() (testcontainers.Container, func(ctx context.Context), error) {
 	ctx := context.Background()
 
@@ -17,8 +17,6 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 func spinUpGoipayContainer() (testcontainers.Container, func(ctx context.Context), error) {
@@ -53,6 +51,17 @@ func spinUpGoipayContainer() (testcontainers.Container, func(ctx context.Context
 
 		"XMR_DAEMON_URL": "http://node.monerodevs.org:38089",
 	}
+	var goipayMounts testcontainers.ContainerMounts
+	if tlsDir := os.Getenv(tls_dir_env); tlsDir != "" {
+		goipayReqEnv["SERVER_TLS_CERT"] = "/app/tls/server.crt"
+		goipayReqEnv["SERVER_TLS_KEY"] = "/app/tls/server.key"
+		goipayReqEnv["SERVER_TLS_CLIENT_CA"] = "/app/tls/ca.crt"
+		goipayMounts = append(goipayMounts, testcontainers.ContainerMount{
+			Source: testcontainers.GenericBindMountSource{HostPath: tlsDir},
+			Target: testcontainers.ContainerMountTarget("/app/tls"),
+		})
+	}
+
 	goipayReq := testcontainers.ContainerRequest{
 		FromDockerfile: testcontainers.FromDockerfile{
 			Context: "../../../../",
@@ -60,6 +69,7 @@ func spinUpGoipayContainer() (testcontainers.Container, func(ctx context.Context
 		ExposedPorts: []string{fmt.Sprintf("%v/tcp", goipayReqEnv["SERVER_PORT"])},
 		Networks:     []string{nets[0]},
 		Env:          goipayReqEnv,
+		Mounts:       goipayMounts,
 		WaitingFor:   wait.ForListeningPort(nat.Port(fmt.Sprintf("%v/tcp", goipayReqEnv["SERVER_PORT"]))).WithPollInterval(5 * time.Second),
 		LogConsumerCfg: &testcontainers.LogConsumerConfig{
 			Consumers: []testcontainers.LogConsumer{&testcontainers.StdoutLogConsumer{}},
@@ -106,7 +116,7 @@ func TestSimpleRegisterUser(t *testing.T) {
 		assert.FailNow(t, "")
 	}
 
-	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%v", goipayPort.Port()), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := newGoipayClientConn(fmt.Sprintf("localhost:%v", goipayPort.Port()))
 	if err != nil {
 		log.Err(err).Msg("")
 		assert.FailNow(t, "")
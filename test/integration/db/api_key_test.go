@@ -0,0 +1,142 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestApiKey(ctx context.Context, q *db.Queries, userId pgtype.UUID) (db.ApiKey, error) {
+	params := db.CreateApiKeyParams{KeyHash: uuid.NewString(), Role: db.ApiKeyRoleTypeADMIN}
+	if userId.Valid {
+		params.Role = db.ApiKeyRoleTypeUSER
+		params.UserID = userId
+	}
+
+	return q.CreateApiKey(ctx, params)
+}
+
+func TestCreateApiKey(t *testing.T) {
+	t.Run("User Key", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			params := db.CreateApiKeyParams{KeyHash: uuid.NewString(), Role: db.ApiKeyRoleTypeUSER, UserID: userId}
+			apiKey, err := q.CreateApiKey(ctx, params)
+			assert.NoError(t, err)
+			assert.True(t, apiKey.ID.Valid)
+			assert.Equal(t, params.KeyHash, apiKey.KeyHash)
+			assert.Equal(t, params.Role, apiKey.Role)
+			assert.Equal(t, userId, apiKey.UserID)
+			assert.False(t, apiKey.RevokedAt.Valid)
+		})
+	})
+
+	t.Run("Admin Key", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			apiKey, err := q.CreateApiKey(ctx, db.CreateApiKeyParams{KeyHash: uuid.NewString(), Role: db.ApiKeyRoleTypeADMIN})
+			assert.NoError(t, err)
+			assert.Equal(t, db.ApiKeyRoleTypeADMIN, apiKey.Role)
+			assert.False(t, apiKey.UserID.Valid)
+		})
+	})
+
+	t.Run("User Key Without User", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			_, err := q.CreateApiKey(ctx, db.CreateApiKeyParams{KeyHash: uuid.NewString(), Role: db.ApiKeyRoleTypeUSER})
+			assert.Error(t, err)
+		})
+	})
+}
+
+func TestFindActiveApiKeyByKeyHash(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		expectedApiKey, err := createTestApiKey(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		apiKey, err := q.FindActiveApiKeyByKeyHash(ctx, expectedApiKey.KeyHash)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedApiKey, apiKey)
+
+		_, err = q.FindActiveApiKeyByKeyHash(ctx, uuid.NewString())
+		assert.True(t, errors.Is(err, pgx.ErrNoRows))
+	})
+}
+
+func TestFindActiveApiKeyByIdForUpdate(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		expectedApiKey, err := createTestApiKey(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		apiKey, err := q.FindActiveApiKeyByIdForUpdate(ctx, expectedApiKey.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedApiKey, apiKey)
+
+		if _, err := q.RevokeApiKeyById(ctx, expectedApiKey.ID); err != nil {
+			log.Fatal(err)
+		}
+		_, err = q.FindActiveApiKeyByIdForUpdate(ctx, expectedApiKey.ID)
+		assert.True(t, errors.Is(err, pgx.ErrNoRows))
+	})
+}
+
+func TestRevokeApiKeyById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		expectedApiKey, err := createTestApiKey(ctx, q, pgtype.UUID{})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		apiKey, err := q.RevokeApiKeyById(ctx, expectedApiKey.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedApiKey.ID, apiKey.ID)
+		assert.True(t, apiKey.RevokedAt.Valid)
+
+		_, err = q.FindActiveApiKeyByKeyHash(ctx, expectedApiKey.KeyHash)
+		assert.True(t, errors.Is(err, pgx.ErrNoRows))
+
+		// Already revoked
+		_, err = q.RevokeApiKeyById(ctx, expectedApiKey.ID)
+		assert.True(t, errors.Is(err, pgx.ErrNoRows))
+	})
+}
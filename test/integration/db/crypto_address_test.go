@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/chekist32/goipay/internal/db"
 	"github.com/google/uuid"
@@ -235,3 +236,78 @@ func TestDeleteAllCryptoAddressByUserIdAndCoin(t *testing.T) {
 	})
 
 }
+
+func TestReleaseStuckCryptoAddresses(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		createOccupiedAddr := func(coin db.CoinType) db.CryptoAddress {
+			addr, err := q.CreateCryptoAddress(ctx, db.CreateCryptoAddressParams{Address: uuid.NewString(), Coin: coin, IsOccupied: true, UserID: userId})
+			if err != nil {
+				log.Fatal(err)
+			}
+			return addr
+		}
+		createInvoice := func(addr db.CryptoAddress) db.Invoice {
+			invoice, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if _, err := tx.Exec(ctx, "UPDATE invoices SET crypto_address = $1, coin = $2 WHERE id = $3", addr.Address, addr.Coin, invoice.ID); err != nil {
+				log.Fatal(err)
+			}
+			return invoice
+		}
+
+		stuckAddr := createOccupiedAddr(db.CoinTypeBTC)
+		pendingAddr := createOccupiedAddr(db.CoinTypeBTC)
+		expiredAddr := createOccupiedAddr(db.CoinTypeBTC)
+		xmrAddr := createOccupiedAddr(db.CoinTypeXMR)
+
+		createInvoice(pendingAddr)
+		if _, err := q.ExpireInvoiceById(ctx, createInvoice(expiredAddr).ID); err != nil {
+			log.Fatal(err)
+		}
+
+		var expiredSince pgtype.Timestamptz
+		if err := expiredSince.Scan(time.Now().UTC().Add(-time.Hour)); err != nil {
+			log.Fatal(err)
+		}
+
+		// The expired invoice is still within the grace period
+		released, err := q.ReleaseStuckCryptoAddresses(ctx, db.ReleaseStuckCryptoAddressesParams{
+			Coin:         db.NullCoinType{CoinType: db.CoinTypeBTC, Valid: true},
+			ExpiredSince: expiredSince,
+		})
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(released)) {
+			assert.Equal(t, stuckAddr.Address, released[0].Address)
+			assert.False(t, released[0].IsOccupied)
+		}
+
+		if err := expiredSince.Scan(time.Now().UTC().Add(time.Hour)); err != nil {
+			log.Fatal(err)
+		}
+
+		released, err = q.ReleaseStuckCryptoAddresses(ctx, db.ReleaseStuckCryptoAddressesParams{
+			Address:      pgtype.Text{String: expiredAddr.Address, Valid: true},
+			ExpiredSince: expiredSince,
+		})
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(released)) {
+			assert.Equal(t, expiredAddr.Address, released[0].Address)
+		}
+
+		released, err = q.ReleaseStuckCryptoAddresses(ctx, db.ReleaseStuckCryptoAddressesParams{ExpiredSince: expiredSince})
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(released)) {
+			assert.Equal(t, xmrAddr.Address, released[0].Address)
+		}
+	})
+}
@@ -58,3 +58,18 @@ func TestUpdateCryptoCacheByCoin(t *testing.T) {
 		})
 	})
 }
+
+func TestFindAllCryptoCaches(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		caches, err := q.FindAllCryptoCaches(ctx)
+		assert.NoError(t, err)
+		if assert.Equal(t, len(dbCoinTypes), len(caches)) {
+			for i := 0; i < len(caches); i++ {
+				assert.Equal(t, dbCoinTypes[i], caches[i].Coin)
+			}
+		}
+	})
+}
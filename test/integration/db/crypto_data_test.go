@@ -43,7 +43,8 @@ func TestCreateXMRCryptoData(t *testing.T) {
 		})
 	})
 
-	t.Run("Should Return SQL Error (non unique private view key)", func(t *testing.T) {
+	// The encrypted private view keys can't be compared, so they aren't unique
+	t.Run("Should Return Valid XMR Crypto Data (non unique private view key)", func(t *testing.T) {
 		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
 			ctx := context.Background()
 			q := db.New(tx)
@@ -52,9 +53,7 @@ func TestCreateXMRCryptoData(t *testing.T) {
 			assert.NoError(t, err)
 
 			_, err = q.CreateXMRCryptoData(ctx, db.CreateXMRCryptoDataParams{PrivViewKey: xmr1.PrivViewKey, PubSpendKey: uuid.NewString()})
-			var pgErr *pgconn.PgError
-			assert.ErrorAs(t, err, &pgErr)
-			assert.Equal(t, "23505", pgErr.Code)
+			assert.NoError(t, err)
 		})
 	})
 
@@ -329,6 +328,40 @@ func TestUpdateKeysXMRCryptoDataById(t *testing.T) {
 	})
 }
 
+func TestFindAllPrivViewKeysAndLockXMRCryptoData(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		xmr, err := createRandomXMRCryptoData(ctx, q)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		keys, err := q.FindAllPrivViewKeysAndLockXMRCryptoData(ctx)
+		assert.NoError(t, err)
+		assert.Contains(t, keys, db.FindAllPrivViewKeysAndLockXMRCryptoDataRow{ID: xmr.ID, PrivViewKey: xmr.PrivViewKey})
+	})
+}
+
+func TestUpdatePrivViewKeyXMRCryptoDataById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		xmr, err := createRandomXMRCryptoData(ctx, q)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		privViewKey := uuid.NewString()
+		updated, err := q.UpdatePrivViewKeyXMRCryptoDataById(ctx, db.UpdatePrivViewKeyXMRCryptoDataByIdParams{ID: xmr.ID, PrivViewKey: privViewKey})
+		assert.NoError(t, err)
+		assert.Equal(t, privViewKey, updated.PrivViewKey)
+		assert.Equal(t, xmr.PubSpendKey, updated.PubSpendKey)
+	})
+}
+
 func TestSetXMRCryptoDataByUserId(t *testing.T) {
 	t.Run("Should Return Valid Crypto Data", func(t *testing.T) {
 		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
@@ -388,3 +421,381 @@ func TestSetXMRCryptoDataByUserId(t *testing.T) {
 	})
 
 }
+
+// hdCryptoDatum is the shape shared by the BTC, LTC and ETH crypto data rows.
+type hdCryptoDatum struct {
+	ID             pgtype.UUID
+	MasterPubKey   string
+	LastMajorIndex int32
+	LastMinorIndex int32
+}
+
+// hdCryptoDataQueries are the queries of a crypto data table holding a master public key and the last derivation indices.
+type hdCryptoDataQueries struct {
+	coin          string
+	create        func(ctx context.Context, q *db.Queries, masterPubKey string) (hdCryptoDatum, error)
+	findKeys      func(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error)
+	findIndices   func(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error)
+	updateIndices func(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) (hdCryptoDatum, error)
+	updateKeys    func(ctx context.Context, q *db.Queries, id pgtype.UUID, masterPubKey string) (hdCryptoDatum, error)
+	set           func(ctx context.Context, q *db.Queries, userId pgtype.UUID, id pgtype.UUID) (db.CryptoDatum, error)
+	cryptoDataId  func(cd *db.CryptoDatum) pgtype.UUID
+	masterPubKey  func(keys *db.FindCryptoKeysByUserIdRow) string
+}
+
+var hdCryptoDataTests []hdCryptoDataQueries = []hdCryptoDataQueries{
+	{
+		coin: "BTC",
+		create: func(ctx context.Context, q *db.Queries, masterPubKey string) (hdCryptoDatum, error) {
+			d, err := q.CreateBTCCryptoData(ctx, masterPubKey)
+			return hdCryptoDatum(d), err
+		},
+		findKeys: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error) {
+			return q.FindKeysAndLockBTCCryptoDataById(ctx, id)
+		},
+		findIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error) {
+			i, err := q.FindIndicesAndLockBTCCryptoDataById(ctx, id)
+			return i.LastMajorIndex, i.LastMinorIndex, err
+		},
+		updateIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) (hdCryptoDatum, error) {
+			d, err := q.UpdateIndicesBTCCryptoDataById(ctx, db.UpdateIndicesBTCCryptoDataByIdParams{ID: id, LastMajorIndex: major, LastMinorIndex: minor})
+			return hdCryptoDatum(d), err
+		},
+		updateKeys: func(ctx context.Context, q *db.Queries, id pgtype.UUID, masterPubKey string) (hdCryptoDatum, error) {
+			d, err := q.UpdateKeysBTCCryptoDataById(ctx, db.UpdateKeysBTCCryptoDataByIdParams{ID: id, MasterPubKey: masterPubKey})
+			return hdCryptoDatum(d), err
+		},
+		set: func(ctx context.Context, q *db.Queries, userId pgtype.UUID, id pgtype.UUID) (db.CryptoDatum, error) {
+			return q.SetBTCCryptoDataByUserId(ctx, db.SetBTCCryptoDataByUserIdParams{UserID: userId, BtcID: id})
+		},
+		cryptoDataId: func(cd *db.CryptoDatum) pgtype.UUID { return cd.BtcID },
+		masterPubKey: func(keys *db.FindCryptoKeysByUserIdRow) string { return keys.BtcMasterPubKey },
+	},
+	{
+		coin: "LTC",
+		create: func(ctx context.Context, q *db.Queries, masterPubKey string) (hdCryptoDatum, error) {
+			d, err := q.CreateLTCCryptoData(ctx, masterPubKey)
+			return hdCryptoDatum(d), err
+		},
+		findKeys: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error) {
+			return q.FindKeysAndLockLTCCryptoDataById(ctx, id)
+		},
+		findIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error) {
+			i, err := q.FindIndicesAndLockLTCCryptoDataById(ctx, id)
+			return i.LastMajorIndex, i.LastMinorIndex, err
+		},
+		updateIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) (hdCryptoDatum, error) {
+			d, err := q.UpdateIndicesLTCCryptoDataById(ctx, db.UpdateIndicesLTCCryptoDataByIdParams{ID: id, LastMajorIndex: major, LastMinorIndex: minor})
+			return hdCryptoDatum(d), err
+		},
+		updateKeys: func(ctx context.Context, q *db.Queries, id pgtype.UUID, masterPubKey string) (hdCryptoDatum, error) {
+			d, err := q.UpdateKeysLTCCryptoDataById(ctx, db.UpdateKeysLTCCryptoDataByIdParams{ID: id, MasterPubKey: masterPubKey})
+			return hdCryptoDatum(d), err
+		},
+		set: func(ctx context.Context, q *db.Queries, userId pgtype.UUID, id pgtype.UUID) (db.CryptoDatum, error) {
+			return q.SetLTCCryptoDataByUserId(ctx, db.SetLTCCryptoDataByUserIdParams{UserID: userId, LtcID: id})
+		},
+		cryptoDataId: func(cd *db.CryptoDatum) pgtype.UUID { return cd.LtcID },
+		masterPubKey: func(keys *db.FindCryptoKeysByUserIdRow) string { return keys.LtcMasterPubKey },
+	},
+	{
+		coin: "ETH",
+		create: func(ctx context.Context, q *db.Queries, masterPubKey string) (hdCryptoDatum, error) {
+			d, err := q.CreateETHCryptoData(ctx, masterPubKey)
+			return hdCryptoDatum(d), err
+		},
+		findKeys: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (string, error) {
+			return q.FindKeysAndLockETHCryptoDataById(ctx, id)
+		},
+		findIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID) (int32, int32, error) {
+			i, err := q.FindIndicesAndLockETHCryptoDataById(ctx, id)
+			return i.LastMajorIndex, i.LastMinorIndex, err
+		},
+		updateIndices: func(ctx context.Context, q *db.Queries, id pgtype.UUID, major int32, minor int32) (hdCryptoDatum, error) {
+			d, err := q.UpdateIndicesETHCryptoDataById(ctx, db.UpdateIndicesETHCryptoDataByIdParams{ID: id, LastMajorIndex: major, LastMinorIndex: minor})
+			return hdCryptoDatum(d), err
+		},
+		updateKeys: func(ctx context.Context, q *db.Queries, id pgtype.UUID, masterPubKey string) (hdCryptoDatum, error) {
+			d, err := q.UpdateKeysETHCryptoDataById(ctx, db.UpdateKeysETHCryptoDataByIdParams{ID: id, MasterPubKey: masterPubKey})
+			return hdCryptoDatum(d), err
+		},
+		set: func(ctx context.Context, q *db.Queries, userId pgtype.UUID, id pgtype.UUID) (db.CryptoDatum, error) {
+			return q.SetETHCryptoDataByUserId(ctx, db.SetETHCryptoDataByUserIdParams{UserID: userId, EthID: id})
+		},
+		cryptoDataId: func(cd *db.CryptoDatum) pgtype.UUID { return cd.EthID },
+		masterPubKey: func(keys *db.FindCryptoKeysByUserIdRow) string { return keys.EthMasterPubKey },
+	},
+}
+
+func randomPgUUID() pgtype.UUID {
+	var id pgtype.UUID
+	if err := id.Scan(uuid.NewString()); err != nil {
+		log.Fatal(err)
+	}
+
+	return id
+}
+
+func createUserWithCryptoData(ctx context.Context, q *db.Queries) pgtype.UUID {
+	userId, err := q.CreateUser(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := q.CreateCryptoData(ctx, db.CreateCryptoDataParams{UserID: userId}); err != nil {
+		log.Fatal(err)
+	}
+
+	return userId
+}
+
+func TestHdCryptoData(t *testing.T) {
+	for _, c := range hdCryptoDataTests {
+		t.Run(c.coin, func(t *testing.T) {
+			t.Run("Create Should Return Valid Crypto Data", func(t *testing.T) {
+				runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+					_, err := c.create(context.Background(), db.New(tx), uuid.NewString())
+					assert.NoError(t, err)
+				})
+			})
+
+			t.Run("Create Should Return SQL Error (non unique master public key)", func(t *testing.T) {
+				runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+					ctx := context.Background()
+					q := db.New(tx)
+
+					d, err := c.create(ctx, q, uuid.NewString())
+					assert.NoError(t, err)
+
+					_, err = c.create(ctx, q, d.MasterPubKey)
+					var pgErr *pgconn.PgError
+					assert.ErrorAs(t, err, &pgErr)
+					assert.Equal(t, "23505", pgErr.Code)
+				})
+			})
+
+			t.Run("Find Should Return Proper Keys And Indices", func(t *testing.T) {
+				runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+					ctx := context.Background()
+					q := db.New(tx)
+
+					d, err := c.create(ctx, q, uuid.NewString())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					key, err := c.findKeys(ctx, q, d.ID)
+					assert.NoError(t, err)
+					assert.Equal(t, d.MasterPubKey, key)
+
+					major, minor, err := c.findIndices(ctx, q, d.ID)
+					assert.NoError(t, err)
+					assert.Equal(t, d.LastMajorIndex, major)
+					assert.Equal(t, d.LastMinorIndex, minor)
+				})
+			})
+
+			t.Run("Find Should Return SQL Error (no rows)", func(t *testing.T) {
+				runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+					ctx := context.Background()
+					q := db.New(tx)
+
+					_, err := c.findKeys(ctx, q, randomPgUUID())
+					assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+					_, _, err = c.findIndices(ctx, q, randomPgUUID())
+					assert.ErrorIs(t, err, pgx.ErrNoRows)
+				})
+			})
+
+			t.Run("Update Should Set Indices And Reset Them On New Keys", func(t *testing.T) {
+				runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+					ctx := context.Background()
+					q := db.New(tx)
+
+					d, err := c.create(ctx, q, uuid.NewString())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					d1, err := c.updateIndices(ctx, q, d.ID, 1, 1)
+					assert.NoError(t, err)
+					assert.Equal(t, int32(1), d1.LastMajorIndex)
+					assert.Equal(t, int32(1), d1.LastMinorIndex)
+
+					d2, err := c.updateKeys(ctx, q, d.ID, uuid.NewString())
+					assert.NoError(t, err)
+					assert.Equal(t, int32(0), d2.LastMajorIndex)
+					assert.Equal(t, int32(0), d2.LastMinorIndex)
+				})
+			})
+
+			t.Run("Set Should Return Valid Crypto Data", func(t *testing.T) {
+				runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+					ctx := context.Background()
+					q := db.New(tx)
+
+					userId := createUserWithCryptoData(ctx, q)
+					d, err := c.create(ctx, q, uuid.NewString())
+					if err != nil {
+						log.Fatal(err)
+					}
+
+					cryptoData, err := c.set(ctx, q, userId, d.ID)
+					assert.NoError(t, err)
+					assert.Equal(t, d.ID, c.cryptoDataId(&cryptoData))
+
+					keys, err := q.FindCryptoKeysByUserId(ctx, userId)
+					assert.NoError(t, err)
+					assert.Equal(t, d.MasterPubKey, c.masterPubKey(&keys))
+				})
+			})
+
+			t.Run("Set Should Return SQL Error (invalid id)", func(t *testing.T) {
+				runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+					ctx := context.Background()
+					q := db.New(tx)
+
+					_, err := c.set(ctx, q, createUserWithCryptoData(ctx, q), randomPgUUID())
+					var pgErr *pgconn.PgError
+					assert.ErrorAs(t, err, &pgErr)
+					assert.Equal(t, "23503", pgErr.Code)
+				})
+			})
+		})
+	}
+}
+
+func createRandomTONCryptoData(ctx context.Context, q *db.Queries) (db.TonCryptoDatum, error) {
+	return q.CreateTONCryptoData(ctx, uuid.NewString())
+}
+
+func TestCreateTONCryptoData(t *testing.T) {
+	t.Run("Should Return Valid TON Crypto Data", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			_, err := createRandomTONCryptoData(ctx, q)
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("Should Return SQL Error (non unique address)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			ton1, err := createRandomTONCryptoData(ctx, q)
+			assert.NoError(t, err)
+
+			_, err = q.CreateTONCryptoData(ctx, ton1.Address)
+			var pgErr *pgconn.PgError
+			assert.ErrorAs(t, err, &pgErr)
+			assert.Equal(t, "23505", pgErr.Code)
+		})
+	})
+}
+
+func TestFindAddressAndLockTONCryptoDataById(t *testing.T) {
+	t.Run("Should Return Proper TON Address", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			ton, err := createRandomTONCryptoData(ctx, q)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			address, err := q.FindAddressAndLockTONCryptoDataById(ctx, ton.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, ton.Address, address)
+		})
+	})
+
+	t.Run("Should Return SQL Error (no rows)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			var tonId pgtype.UUID
+			if err := tonId.Scan(uuid.NewString()); err != nil {
+				log.Fatal(err)
+			}
+
+			_, err := q.FindAddressAndLockTONCryptoDataById(ctx, tonId)
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+		})
+	})
+}
+
+func TestUpdateAddressTONCryptoDataById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		ton, err := createRandomTONCryptoData(ctx, q)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		newAddress := uuid.NewString()
+		ton1, err := q.UpdateAddressTONCryptoDataById(ctx, db.UpdateAddressTONCryptoDataByIdParams{ID: ton.ID, Address: newAddress})
+		assert.NoError(t, err)
+		assert.Equal(t, newAddress, ton1.Address)
+	})
+}
+
+func TestSetTONCryptoDataByUserId(t *testing.T) {
+	t.Run("Should Return Valid Crypto Data", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if _, err := q.CreateCryptoData(ctx, db.CreateCryptoDataParams{UserID: userId}); err != nil {
+				log.Fatal(err)
+			}
+			ton, err := createRandomTONCryptoData(ctx, q)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			cryptoData, err := q.SetTONCryptoDataByUserId(ctx, db.SetTONCryptoDataByUserIdParams{UserID: userId, TonID: ton.ID})
+			assert.NoError(t, err)
+			assert.Equal(t, ton.ID, cryptoData.TonID)
+
+			keys, err := q.FindCryptoKeysByUserId(ctx, userId)
+			assert.NoError(t, err)
+			assert.Equal(t, ton.Address, keys.TonAddress)
+		})
+	})
+
+	t.Run("Should Return SQL Error (invalid ton_id)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if _, err := q.CreateCryptoData(ctx, db.CreateCryptoDataParams{UserID: userId}); err != nil {
+				log.Fatal(err)
+			}
+
+			var tonId pgtype.UUID
+			if err := tonId.Scan(uuid.NewString()); err != nil {
+				log.Fatal(err)
+			}
+
+			_, err = q.SetTONCryptoDataByUserId(ctx, db.SetTONCryptoDataByUserIdParams{UserID: userId, TonID: tonId})
+			var pgErr *pgconn.PgError
+			assert.ErrorAs(t, err, &pgErr)
+			assert.Equal(t, "23503", pgErr.Code)
+		})
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"testing"
 
 	"github.com/docker/go-connections/nat"
@@ -48,48 +47,7 @@ func spinUpPostgresContainer() (testcontainers.Container, func(ctx context.Conte
 		return nil, nil, err
 	}
 
-	postgresIp, err := postgres.ContainerIP(ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	migrationsReqEnv := map[string]string{
-		"GOOSE_DRIVER": "postgres",
-		"GOOSE_DBSTRING": fmt.Sprintf(
-			"host=%v port=5432 user=%v password=%v dbname=%v",
-			postgresIp,
-			postgresReqEnv["POSTGRES_USER"],
-			postgresReqEnv["POSTGRES_PASSWORD"],
-			postgresReqEnv["POSTGRES_DB"],
-		),
-	}
-
-	migrationsReq := testcontainers.ContainerRequest{
-		Image:    "ghcr.io/kukymbr/goose-docker:3.21.1",
-		Env:      migrationsReqEnv,
-		Networks: []string{net.Name},
-		Mounts: testcontainers.ContainerMounts{
-			{
-				Source: testcontainers.GenericBindMountSource{
-					HostPath: fmt.Sprintf("%v/../../../sql/migrations", os.Getenv("PWD")),
-				},
-				Target: testcontainers.ContainerMountTarget("/migrations"),
-			},
-		},
-		WaitingFor: wait.ForExit(),
-	}
-	migrations, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: migrationsReq,
-		Started:          true,
-	})
-	if err != nil {
-		log.Printf("Could not start migrations: %s", err)
-		postgres.Terminate(ctx)
-		return nil, nil, err
-	}
-
 	closeHandler := func(ctx context.Context) {
-		migrations.Terminate(ctx)
 		postgres.Terminate(ctx)
 		net.Remove(ctx)
 	}
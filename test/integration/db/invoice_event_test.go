@@ -0,0 +1,111 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestInvoiceEvent(ctx context.Context, q *db.Queries, invoice *db.Invoice) (db.InvoiceEvent, error) {
+	snapshot, err := json.Marshal(invoice)
+	if err != nil {
+		return db.InvoiceEvent{}, err
+	}
+
+	return q.CreateInvoiceEvent(ctx, db.CreateInvoiceEventParams{InvoiceID: invoice.ID, Status: invoice.Status, Invoice: snapshot})
+}
+
+func TestCreateInvoiceEvent(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		event1, err := createTestInvoiceEvent(ctx, q, &inv)
+		assert.NoError(t, err)
+		assert.Equal(t, inv.ID, event1.InvoiceID)
+		assert.Equal(t, inv.Status, event1.Status)
+
+		var snapshot db.Invoice
+		assert.NoError(t, json.Unmarshal(event1.Invoice, &snapshot))
+		assert.Equal(t, inv.ID, snapshot.ID)
+
+		event2, err := createTestInvoiceEvent(ctx, q, &inv)
+		assert.NoError(t, err)
+		assert.Greater(t, event2.Sequence, event1.Sequence)
+	})
+}
+
+func TestFindAllInvoiceEventsFromSequence(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		events := make([]db.InvoiceEvent, 0, 3)
+		for i := 0; i < 3; i++ {
+			event, err := createTestInvoiceEvent(ctx, q, &inv)
+			if err != nil {
+				log.Fatal(err)
+			}
+			events = append(events, event)
+		}
+
+		actualEvents, err := q.FindAllInvoiceEventsFromSequence(ctx, db.FindAllInvoiceEventsFromSequenceParams{Sequence: events[0].Sequence, Limit: 100})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(actualEvents))
+		assert.Equal(t, events[1].Sequence, actualEvents[0].Sequence)
+		assert.Equal(t, events[2].Sequence, actualEvents[1].Sequence)
+
+		actualEvents, err = q.FindAllInvoiceEventsFromSequence(ctx, db.FindAllInvoiceEventsFromSequenceParams{Sequence: events[0].Sequence, Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(actualEvents))
+		assert.Equal(t, events[1].Sequence, actualEvents[0].Sequence)
+	})
+}
+
+func TestLockInvoiceEvents(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		assert.NoError(t, q.LockInvoiceEvents(ctx))
+		// Locking again within the same tx doesn't block
+		assert.NoError(t, q.LockInvoiceEvents(ctx))
+
+		otherTx, err := dbConnPool.Begin(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer otherTx.Rollback(ctx)
+
+		// The other tx waits for the lock till the first one ends
+		lockCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		assert.Error(t, db.New(otherTx).LockInvoiceEvents(lockCtx))
+	})
+}
@@ -0,0 +1,298 @@
+package test
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"testing"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestInvoicePayment(ctx context.Context, q *db.Queries, invoiceId pgtype.UUID, txId string, outputIndex int32, amount int64) (db.InvoicePayment, error) {
+	return q.CreateInvoicePayment(ctx, db.CreateInvoicePaymentParams{
+		InvoiceID:   invoiceId,
+		TxID:        txId,
+		OutputIndex: outputIndex,
+		Amount:      util.BigIntToPgNumeric(big.NewInt(amount)),
+	})
+}
+
+func TestCreateInvoicePayment(t *testing.T) {
+	t.Run("Should Create Invoice Payment", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			txId := uuid.NewString()
+			payment, err := createTestInvoicePayment(ctx, q, inv.ID, txId, 1, 1_000)
+			assert.NoError(t, err)
+			assert.Equal(t, inv.ID, payment.InvoiceID)
+			assert.Equal(t, txId, payment.TxID)
+			assert.Equal(t, int32(1), payment.OutputIndex)
+			assert.False(t, payment.BlockHeight.Valid)
+
+			amount, err := util.PgNumericToBigInt(payment.Amount)
+			assert.NoError(t, err)
+			assert.Equal(t, big.NewInt(1_000), amount)
+		})
+	})
+
+	t.Run("Should Return ErrNoRows (the same output twice)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			txId := uuid.NewString()
+			if _, err := createTestInvoicePayment(ctx, q, inv.ID, txId, 0, 1_000); err != nil {
+				log.Fatal(err)
+			}
+
+			_, err = createTestInvoicePayment(ctx, q, inv.ID, txId, 0, 1_000)
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+			_, err = createTestInvoicePayment(ctx, q, inv.ID, txId, 1, 1_000)
+			assert.NoError(t, err)
+		})
+	})
+}
+
+func TestSumAmountInvoicePaymentsByInvoiceId(t *testing.T) {
+	t.Run("Should Return Sum Of Payments", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if _, err := createTestInvoicePayment(ctx, q, inv.ID, uuid.NewString(), 0, 1_000_000_000_000); err != nil {
+				log.Fatal(err)
+			}
+			if _, err := createTestInvoicePayment(ctx, q, inv.ID, uuid.NewString(), 3, 500_000_000_001); err != nil {
+				log.Fatal(err)
+			}
+
+			total, err := q.SumAmountInvoicePaymentsByInvoiceId(ctx, inv.ID)
+			assert.NoError(t, err)
+
+			totalAmount, err := util.PgNumericToBigInt(total)
+			assert.NoError(t, err)
+			assert.Equal(t, big.NewInt(1_500_000_000_001), totalAmount)
+		})
+	})
+
+	t.Run("Should Return 0 (no payments)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			total, err := q.SumAmountInvoicePaymentsByInvoiceId(ctx, inv.ID)
+			assert.NoError(t, err)
+
+			totalAmount, err := util.PgNumericToBigInt(total)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(0), totalAmount.Int64())
+		})
+	})
+}
+
+func TestFindAllInvoicePaymentsByInvoiceIds(t *testing.T) {
+	t.Run("Should Return Payments Of Given Invoices", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var invoices [3]db.Invoice
+			for i := 0; i < len(invoices); i++ {
+				inv, err := createRandTestInvoice(ctx, q, userId)
+				if err != nil {
+					log.Fatal(err)
+				}
+				invoices[i] = inv
+
+				for j := 0; j <= i; j++ {
+					if _, err := createTestInvoicePayment(ctx, q, inv.ID, uuid.NewString(), int32(j), 1_000); err != nil {
+						log.Fatal(err)
+					}
+				}
+			}
+
+			payments, err := q.FindAllInvoicePaymentsByInvoiceIds(ctx, []pgtype.UUID{invoices[0].ID, invoices[2].ID})
+			assert.NoError(t, err)
+			assert.Equal(t, 4, len(payments))
+
+			payments, err = q.FindAllInvoicePaymentsByInvoiceId(ctx, invoices[1].ID)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(payments))
+		})
+	})
+}
+
+func TestUpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId(t *testing.T) {
+	t.Run("Should Update Block Height Of Every Output Of Tx", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			txId := uuid.NewString()
+			for i := 0; i < 2; i++ {
+				if _, err := createTestInvoicePayment(ctx, q, inv.ID, txId, int32(i), 1_000); err != nil {
+					log.Fatal(err)
+				}
+			}
+
+			expectedBlockHeight := pgtype.Int8{Int64: 3_000_000, Valid: true}
+			payments, err := q.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId(ctx, db.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxIdParams{InvoiceID: inv.ID, TxID: txId, BlockHeight: expectedBlockHeight})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(payments))
+			for i := 0; i < len(payments); i++ {
+				assert.Equal(t, expectedBlockHeight, payments[i].BlockHeight)
+			}
+		})
+	})
+}
+
+func TestResetBlockHeightInvoicePaymentsByCoinFromBlockHeight(t *testing.T) {
+	t.Run("Should Reset Block Height Of Payments Mined Since Fork Height", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			blockHeights := []int64{2_999_999, 3_000_000, 3_000_001}
+			txIds := make([]string, 0, len(blockHeights))
+			for i := 0; i < len(blockHeights); i++ {
+				txId := uuid.NewString()
+				txIds = append(txIds, txId)
+
+				if _, err := createTestInvoicePayment(ctx, q, inv.ID, txId, 0, 1_000); err != nil {
+					log.Fatal(err)
+				}
+				if _, err := q.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxId(ctx, db.UpdateBlockHeightInvoicePaymentsByInvoiceIdAndTxIdParams{InvoiceID: inv.ID, TxID: txId, BlockHeight: pgtype.Int8{Int64: blockHeights[i], Valid: true}}); err != nil {
+					log.Fatal(err)
+				}
+			}
+
+			payments, err := q.ResetBlockHeightInvoicePaymentsByCoinFromBlockHeight(ctx, db.ResetBlockHeightInvoicePaymentsByCoinFromBlockHeightParams{Coin: inv.Coin, BlockHeight: pgtype.Int8{Int64: 3_000_000, Valid: true}})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(payments))
+			for i := 0; i < len(payments); i++ {
+				assert.NotEqual(t, txIds[0], payments[i].TxID)
+				assert.False(t, payments[i].BlockHeight.Valid)
+			}
+
+			payments, err = q.FindAllInvoicePaymentsByInvoiceId(ctx, inv.ID)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for i := 0; i < len(payments); i++ {
+				assert.Equal(t, payments[i].TxID == txIds[0], payments[i].BlockHeight.Valid)
+			}
+		})
+	})
+}
+
+func TestDeleteInvoicePaymentsByInvoiceIdAndTxIds(t *testing.T) {
+	t.Run("Should Delete Only Payments Of Given Txs", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			missedTxId := uuid.NewString()
+			for i := 0; i < 2; i++ {
+				if _, err := createTestInvoicePayment(ctx, q, inv.ID, missedTxId, int32(i), 1_000); err != nil {
+					log.Fatal(err)
+				}
+			}
+			keptPayment, err := createTestInvoicePayment(ctx, q, inv.ID, uuid.NewString(), 0, 2_000)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			deleted, err := q.DeleteInvoicePaymentsByInvoiceIdAndTxIds(ctx, db.DeleteInvoicePaymentsByInvoiceIdAndTxIdsParams{InvoiceID: inv.ID, TxIds: []string{missedTxId, uuid.NewString()}})
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(deleted))
+
+			payments, err := q.FindAllInvoicePaymentsByInvoiceId(ctx, inv.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, []db.InvoicePayment{keptPayment}, payments)
+		})
+	})
+}
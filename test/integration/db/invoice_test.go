@@ -3,11 +3,13 @@ package test
 import (
 	"context"
 	"log"
+	"math/big"
 	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/util"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -24,7 +26,9 @@ func createRandTestInvoice(ctx context.Context, q *db.Queries, userId pgtype.UUI
 	return q.CreateInvoice(ctx, db.CreateInvoiceParams{
 		CryptoAddress:         uuid.NewString(),
 		Coin:                  dbCoinTypes[rand.Intn(len(dbCoinTypes))],
-		RequiredAmount:        rand.Float64(),
+		RequiredAmount:        util.BigIntToPgNumeric(big.NewInt(rand.Int63())),
+		Decimals:              int16(util.XMR_DECIMALS),
+		ToleranceAmount:       util.BigIntToPgNumeric(big.NewInt(0)),
 		ConfirmationsRequired: int16(rand.Int()),
 		ExpiresAt:             expiresAt,
 		UserID:                userId,
@@ -53,6 +57,77 @@ func TestCreateInvoice(t *testing.T) {
 		})
 	})
 
+	t.Run("Should Create Invoice (with token contract)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var expiresAt pgtype.Timestamptz
+			if err := expiresAt.Scan(time.Now().UTC()); err != nil {
+				log.Fatal(err)
+			}
+
+			tokenContract := pgtype.Text{String: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Valid: true}
+			invoice, err := q.CreateInvoice(ctx, db.CreateInvoiceParams{
+				CryptoAddress:         uuid.NewString(),
+				Coin:                  db.CoinTypeETH,
+				RequiredAmount:        util.BigIntToPgNumeric(big.NewInt(rand.Int63())),
+				Decimals:              int16(util.XMR_DECIMALS),
+				ToleranceAmount:       util.BigIntToPgNumeric(big.NewInt(0)),
+				ConfirmationsRequired: int16(rand.Int()),
+				ExpiresAt:             expiresAt,
+				UserID:                userId,
+				TokenContract:         tokenContract,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tokenContract, invoice.TokenContract)
+		})
+	})
+
+	t.Run("Should Return SQL Error (non unique memo)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var expiresAt pgtype.Timestamptz
+			if err := expiresAt.Scan(time.Now().UTC()); err != nil {
+				log.Fatal(err)
+			}
+
+			params := db.CreateInvoiceParams{
+				CryptoAddress:         uuid.NewString(),
+				Coin:                  db.CoinTypeTON,
+				RequiredAmount:        util.BigIntToPgNumeric(big.NewInt(rand.Int63())),
+				Decimals:              int16(util.XMR_DECIMALS),
+				ToleranceAmount:       util.BigIntToPgNumeric(big.NewInt(0)),
+				ConfirmationsRequired: int16(rand.Int()),
+				ExpiresAt:             expiresAt,
+				UserID:                userId,
+				Memo:                  pgtype.Text{String: uuid.NewString(), Valid: true},
+			}
+
+			invoice, err := q.CreateInvoice(ctx, params)
+			assert.NoError(t, err)
+			assert.Equal(t, params.Memo, invoice.Memo)
+
+			_, err = q.CreateInvoice(ctx, params)
+			var pgErr *pgconn.PgError
+			assert.ErrorAs(t, err, &pgErr)
+			assert.Equal(t, "23505", pgErr.Code)
+		})
+	})
+
 	t.Run("Should Return SQL Error (no such userId)", func(t *testing.T) {
 		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
 			ctx := context.Background()
@@ -163,6 +238,30 @@ func TestConfirmInvoiceById(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, db.InvoiceStatusTypeCONFIRMED, confirmedInv.Status)
 		assert.True(t, confirmedInv.ConfirmedAt.Valid)
+		assert.Equal(t, confirmedInv.ConfirmationsRequired, confirmedInv.Confirmations)
+	})
+}
+
+func TestUpdateConfirmationsInvoiceById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+		assert.Equal(t, int16(0), inv.Confirmations)
+
+		updatedInv, err := q.UpdateConfirmationsInvoiceById(ctx, db.UpdateConfirmationsInvoiceByIdParams{ID: inv.ID, Confirmations: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, int16(2), updatedInv.Confirmations)
+		assert.Equal(t, inv.Status, updatedInv.Status)
 	})
 }
 
@@ -184,6 +283,7 @@ func TestExpireInvoiceById(t *testing.T) {
 		confirmedInv, err := q.ExpireInvoiceById(ctx, inv.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, db.InvoiceStatusTypeEXPIRED, confirmedInv.Status)
+		assert.True(t, confirmedInv.ExpiredAt.Valid)
 	})
 }
 
@@ -202,26 +302,131 @@ func TestConfirmInvoiceStatusMempoolById(t *testing.T) {
 			log.Fatal(err)
 		}
 
-		expectedActualAmount := 1.2
+		expectedActualAmount, _ := new(big.Int).SetString("1200000000000000000000", 10)
 		expectedTxId := "txid"
 
-		var actualAmount pgtype.Float8
-		if err := actualAmount.Scan(expectedActualAmount); err != nil {
-			log.Fatal(err)
-		}
+		actualAmount := util.BigIntToPgNumeric(expectedActualAmount)
 		var txId pgtype.Text
 		if err := txId.Scan(expectedTxId); err != nil {
 			log.Fatal(err)
 		}
 
-		confirmedInv, err := q.ConfirmInvoiceStatusMempoolById(ctx, db.ConfirmInvoiceStatusMempoolByIdParams{ID: inv.ID, ActualAmount: actualAmount, TxID: txId})
+		expectedOutcome := db.NullInvoiceOutcomeType{InvoiceOutcomeType: db.InvoiceOutcomeTypeUNDERPAID, Valid: true}
+		expectedAmountDelta := big.NewInt(-1_000)
+
+		confirmedInv, err := q.ConfirmInvoiceStatusMempoolById(ctx, db.ConfirmInvoiceStatusMempoolByIdParams{ID: inv.ID, ActualAmount: actualAmount, TxID: txId, AmountDelta: util.BigIntToPgNumeric(expectedAmountDelta), Outcome: expectedOutcome})
 		assert.NoError(t, err)
 		assert.Equal(t, db.InvoiceStatusTypePENDINGMEMPOOL, confirmedInv.Status)
-		assert.Equal(t, expectedActualAmount, confirmedInv.ActualAmount.Float64)
+		assert.Equal(t, expectedOutcome, confirmedInv.Outcome)
+		amountDelta, err := util.PgNumericToBigInt(confirmedInv.AmountDelta)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedAmountDelta, amountDelta)
+		confirmedActualAmount, err := util.PgNumericToBigInt(confirmedInv.ActualAmount)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedActualAmount, confirmedActualAmount)
 		assert.Equal(t, expectedTxId, confirmedInv.TxID.String)
 	})
 }
 
+func TestUpdatePaidAmountInvoiceById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+		inv, err = q.ConfirmInvoiceStatusMempoolById(ctx, db.ConfirmInvoiceStatusMempoolByIdParams{ID: inv.ID, ActualAmount: util.BigIntToPgNumeric(big.NewInt(3_000)), TxID: pgtype.Text{String: "txid1", Valid: true}})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		expectedActualAmount := big.NewInt(2_000)
+		expectedAmountDelta := big.NewInt(1_000)
+		expectedOutcome := db.NullInvoiceOutcomeType{InvoiceOutcomeType: db.InvoiceOutcomeTypeOVERPAID, Valid: true}
+
+		updatedInv, err := q.UpdatePaidAmountInvoiceById(ctx, db.UpdatePaidAmountInvoiceByIdParams{ID: inv.ID, ActualAmount: util.BigIntToPgNumeric(expectedActualAmount), TxID: pgtype.Text{String: "txid2", Valid: true}, AmountDelta: util.BigIntToPgNumeric(expectedAmountDelta), Outcome: expectedOutcome})
+		assert.NoError(t, err)
+		// The status is left as is
+		assert.Equal(t, db.InvoiceStatusTypePENDINGMEMPOOL, updatedInv.Status)
+		assert.Equal(t, expectedOutcome, updatedInv.Outcome)
+		assert.Equal(t, "txid2", updatedInv.TxID.String)
+		actualAmount, err := util.PgNumericToBigInt(updatedInv.ActualAmount)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedActualAmount, actualAmount)
+		amountDelta, err := util.PgNumericToBigInt(updatedInv.AmountDelta)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedAmountDelta, amountDelta)
+	})
+}
+
+func TestFindAllInvoicesByFilter(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId1, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		userId2, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var invoices [3]db.Invoice
+		for i := 0; i < len(invoices); i++ {
+			inv, err := createRandTestInvoice(ctx, q, userId1)
+			if err != nil {
+				log.Fatal(err)
+			}
+			invoices[i] = inv
+		}
+		if _, err := createRandTestInvoice(ctx, q, userId2); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := q.ExpireInvoiceById(ctx, invoices[0].ID); err != nil {
+			log.Fatal(err)
+		}
+
+		res, err := q.FindAllInvoicesByFilter(ctx, db.FindAllInvoicesByFilterParams{UserID: userId1, Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(res))
+
+		res, err = q.FindAllInvoicesByFilter(ctx, db.FindAllInvoicesByFilterParams{UserID: userId1, Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(res))
+
+		res, err = q.FindAllInvoicesByFilter(ctx, db.FindAllInvoicesByFilterParams{
+			UserID: userId1,
+			Status: db.NullInvoiceStatusType{InvoiceStatusType: db.InvoiceStatusTypeEXPIRED, Valid: true},
+			Limit:  10,
+		})
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(res)) {
+			assert.Equal(t, invoices[0].ID, res[0].ID)
+		}
+
+		res, err = q.FindAllInvoicesByFilter(ctx, db.FindAllInvoicesByFilterParams{
+			UserID: userId1,
+			Coin:   db.NullCoinType{CoinType: invoices[1].Coin, Valid: true},
+			Limit:  10,
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res)
+		for i := 0; i < len(res); i++ {
+			assert.Equal(t, invoices[1].Coin, res[i].Coin)
+			assert.Equal(t, userId1, res[i].UserID)
+		}
+	})
+}
+
 func TestFindAllPendingInvoices(t *testing.T) {
 	t.Run("Should Return 2 Invoices", func(t *testing.T) {
 		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
@@ -243,10 +448,7 @@ func TestFindAllPendingInvoices(t *testing.T) {
 				expectedInvoices[i] = inv
 			}
 
-			var actualAmount pgtype.Float8
-			if err := actualAmount.Scan(1.5); err != nil {
-				log.Fatal(err)
-			}
+			actualAmount := util.BigIntToPgNumeric(big.NewInt(1_500_000_000_000))
 			var txId pgtype.Text
 			if err := txId.Scan("txid"); err != nil {
 				log.Fatal(err)
@@ -288,10 +490,7 @@ func TestFindAllPendingInvoices(t *testing.T) {
 				expectedInvoices[i] = inv
 			}
 
-			var actualAmount pgtype.Float8
-			if err := actualAmount.Scan(1.5); err != nil {
-				log.Fatal(err)
-			}
+			actualAmount := util.BigIntToPgNumeric(big.NewInt(1_500_000_000_000))
 			var txId pgtype.Text
 			if err := txId.Scan("txid"); err != nil {
 				log.Fatal(err)
@@ -394,3 +593,234 @@ func TestShiftExpiresAtForNonConfirmedInvoices(t *testing.T) {
 		}
 	})
 }
+
+func TestPartiallyPayInvoiceById(t *testing.T) {
+	t.Run("Should Return PARTIALLY_PAID Invoice", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			expectedActualAmount := big.NewInt(500_000_000_000)
+			expectedTxId := "txid"
+
+			partiallyPaidInv, err := q.PartiallyPayInvoiceById(ctx, db.PartiallyPayInvoiceByIdParams{ID: inv.ID, ActualAmount: util.BigIntToPgNumeric(expectedActualAmount), TxID: pgtype.Text{String: expectedTxId, Valid: true}})
+			assert.NoError(t, err)
+			assert.Equal(t, db.InvoiceStatusTypePARTIALLYPAID, partiallyPaidInv.Status)
+			actualAmount, err := util.PgNumericToBigInt(partiallyPaidInv.ActualAmount)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedActualAmount, actualAmount)
+			assert.Equal(t, expectedTxId, partiallyPaidInv.TxID.String)
+
+			invoices, err := q.FindAllPendingInvoices(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(invoices))
+		})
+	})
+}
+
+func TestFindInvoiceByIdAndLock(t *testing.T) {
+	t.Run("Should Return Invoice", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			userId, err := q.CreateUser(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			expectedInv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			inv, err := q.FindInvoiceByIdAndLock(ctx, expectedInv.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedInv.ID, inv.ID)
+		})
+	})
+}
+
+func TestPayAfterExpiryInvoiceById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if _, err := q.ExpireInvoiceById(ctx, inv.ID); err != nil {
+			log.Fatal(err)
+		}
+
+		expectedOutcome := db.NullInvoiceOutcomeType{InvoiceOutcomeType: db.InvoiceOutcomeTypeEXACT, Valid: true}
+
+		paidInv, err := q.PayAfterExpiryInvoiceById(ctx, db.PayAfterExpiryInvoiceByIdParams{ID: inv.ID, ActualAmount: inv.RequiredAmount, TxID: pgtype.Text{String: "txid", Valid: true}, AmountDelta: util.BigIntToPgNumeric(big.NewInt(0)), Outcome: expectedOutcome})
+		assert.NoError(t, err)
+		assert.Equal(t, db.InvoiceStatusTypePAIDAFTEREXPIRY, paidInv.Status)
+		assert.Equal(t, expectedOutcome, paidInv.Outcome)
+		assert.Equal(t, "txid", paidInv.TxID.String)
+	})
+}
+
+func TestFindAllInvoicesExpiredSince(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var invoices [3]db.Invoice
+		for i := 0; i < len(invoices); i++ {
+			inv, err := createRandTestInvoice(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+			invoices[i] = inv
+		}
+
+		if _, err := q.ExpireInvoiceById(ctx, invoices[0].ID); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := q.ConfirmInvoiceById(ctx, invoices[1].ID); err != nil {
+			log.Fatal(err)
+		}
+
+		var since pgtype.Timestamptz
+		if err := since.Scan(time.Now().UTC().Add(-time.Hour)); err != nil {
+			log.Fatal(err)
+		}
+
+		expiredInvoices, err := q.FindAllInvoicesExpiredSince(ctx, since)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(expiredInvoices))
+		assert.Equal(t, invoices[0].ID, expiredInvoices[0].ID)
+
+		if err := since.Scan(time.Now().UTC().Add(time.Hour)); err != nil {
+			log.Fatal(err)
+		}
+
+		expiredInvoices, err = q.FindAllInvoicesExpiredSince(ctx, since)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(expiredInvoices))
+	})
+}
+
+func TestUnconfirmInvoiceById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if _, err := q.ConfirmInvoiceById(ctx, inv.ID); err != nil {
+			log.Fatal(err)
+		}
+
+		unconfirmedInv, err := q.UnconfirmInvoiceById(ctx, inv.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, db.InvoiceStatusTypePENDINGMEMPOOL, unconfirmedInv.Status)
+		assert.False(t, unconfirmedInv.ConfirmedAt.Valid)
+		assert.Equal(t, int16(0), unconfirmedInv.Confirmations)
+	})
+}
+
+func TestReorgInvoiceById(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if _, err := q.ConfirmInvoiceById(ctx, inv.ID); err != nil {
+			log.Fatal(err)
+		}
+
+		reorgedInv, err := q.ReorgInvoiceById(ctx, inv.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, db.InvoiceStatusTypeREORGED, reorgedInv.Status)
+	})
+}
+
+func TestCountInvoicesByCoinAndStatus(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		countsOf := func() map[db.CoinType]map[db.InvoiceStatusType]int64 {
+			rows, err := q.CountInvoicesByCoinAndStatus(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			counts := make(map[db.CoinType]map[db.InvoiceStatusType]int64)
+			for i := 0; i < len(rows); i++ {
+				if counts[rows[i].Coin] == nil {
+					counts[rows[i].Coin] = make(map[db.InvoiceStatusType]int64)
+				}
+				counts[rows[i].Coin][rows[i].Status] = rows[i].Count
+			}
+
+			return counts
+		}
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		before := countsOf()
+
+		invoice1, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+		invoice2, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := q.ExpireInvoiceById(ctx, invoice2.ID); err != nil {
+			log.Fatal(err)
+		}
+
+		after := countsOf()
+		assert.Equal(t, before[invoice1.Coin][db.InvoiceStatusTypePENDING]+1, after[invoice1.Coin][db.InvoiceStatusTypePENDING])
+		assert.Equal(t, before[invoice2.Coin][db.InvoiceStatusTypeEXPIRED]+1, after[invoice2.Coin][db.InvoiceStatusTypeEXPIRED])
+	})
+}
@@ -8,7 +8,10 @@ import (
 	"testing"
 
 	"github.com/chekist32/goipay/internal/db"
+	"github.com/chekist32/goipay/internal/migrate"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/rs/zerolog"
 )
 
 var (
@@ -41,5 +44,11 @@ func TestMain(m *testing.M) {
 	}
 	dbConnPool = connPool
 
+	sqlDb := stdlib.OpenDBFromPool(connPool)
+	if err := migrate.Up(ctx, sqlDb, &zerolog.Logger{}); err != nil {
+		log.Fatal(err)
+	}
+	sqlDb.Close()
+
 	os.Exit(m.Run())
 }
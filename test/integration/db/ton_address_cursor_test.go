@@ -0,0 +1,38 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertTonAddressCursor(t *testing.T) {
+	t.Run("Should Only Move Forward", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			ctx := context.Background()
+			q := db.New(tx)
+
+			address := uuid.NewString()
+			expected := db.TonAddressCursor{Address: address, LastLt: 100, LastHash: uuid.NewString()}
+
+			assert.NoError(t, q.UpsertTonAddressCursor(ctx, db.UpsertTonAddressCursorParams{Address: address, LastLt: 50, LastHash: uuid.NewString()}))
+			assert.NoError(t, q.UpsertTonAddressCursor(ctx, db.UpsertTonAddressCursorParams{Address: address, LastLt: expected.LastLt, LastHash: expected.LastHash}))
+			assert.NoError(t, q.UpsertTonAddressCursor(ctx, db.UpsertTonAddressCursorParams{Address: address, LastLt: 70, LastHash: uuid.NewString()}))
+
+			cursor, err := q.FindTonAddressCursorByAddress(ctx, address)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, cursor)
+		})
+	})
+
+	t.Run("Should Return SQL Error (no rows)", func(t *testing.T) {
+		runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+			_, err := db.New(tx).FindTonAddressCursorByAddress(context.Background(), uuid.NewString())
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+		})
+	})
+}
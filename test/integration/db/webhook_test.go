@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/chekist32/goipay/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestWebhook(ctx context.Context, q *db.Queries, userId pgtype.UUID) (db.Webhook, error) {
+	return q.CreateWebhook(ctx, db.CreateWebhookParams{UserID: userId, Url: "https://example.com/" + uuid.NewString(), Secret: uuid.NewString()})
+}
+
+func TestCreateWebhook(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		params := db.CreateWebhookParams{UserID: userId, Url: "https://example.com/webhook", Secret: uuid.NewString()}
+		webhook, err := q.CreateWebhook(ctx, params)
+		assert.NoError(t, err)
+		assert.True(t, webhook.ID.Valid)
+		assert.Equal(t, params.Url, webhook.Url)
+		assert.Equal(t, params.Secret, webhook.Secret)
+	})
+}
+
+func TestFindAllWebhooksByUserId(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		otherUserId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if _, err := createTestWebhook(ctx, q, userId); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if _, err := createTestWebhook(ctx, q, otherUserId); err != nil {
+			log.Fatal(err)
+		}
+
+		webhooks, err := q.FindAllWebhooksByUserId(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(webhooks))
+	})
+}
+
+func TestDeleteWebhookByIdAndUserId(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		otherUserId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		webhook, err := createTestWebhook(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		_, err = q.DeleteWebhookByIdAndUserId(ctx, db.DeleteWebhookByIdAndUserIdParams{ID: webhook.ID, UserID: otherUserId})
+		assert.True(t, errors.Is(err, pgx.ErrNoRows))
+
+		deletedWebhook, err := q.DeleteWebhookByIdAndUserId(ctx, db.DeleteWebhookByIdAndUserIdParams{ID: webhook.ID, UserID: userId})
+		assert.NoError(t, err)
+		assert.Equal(t, webhook.ID, deletedWebhook.ID)
+	})
+}
+
+func TestWebhookOutbox(t *testing.T) {
+	runInTransaction(t, dbConnPool, func(t *testing.T, tx pgx.Tx) {
+		ctx := context.Background()
+		q := db.New(tx)
+
+		userId, err := q.CreateUser(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		webhooks := make([]db.Webhook, 0, 2)
+		for i := 0; i < 2; i++ {
+			webhook, err := createTestWebhook(ctx, q, userId)
+			if err != nil {
+				log.Fatal(err)
+			}
+			webhooks = append(webhooks, webhook)
+		}
+
+		inv, err := createRandTestInvoice(ctx, q, userId)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		payload := []byte(`{"event":"invoice.confirmed"}`)
+		events, err := q.CreateWebhookOutboxEventsByUserId(ctx, db.CreateWebhookOutboxEventsByUserIdParams{UserID: userId, InvoiceID: inv.ID, Payload: payload})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(events))
+		for i := 0; i < len(events); i++ {
+			assert.Equal(t, db.WebhookDeliveryStatusTypePENDING, events[i].Status)
+			assert.Equal(t, int32(0), events[i].Attempts)
+		}
+
+		// Delivered
+		due, err := q.FindDueWebhookOutboxEventAndLock(ctx)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(payload), string(due.Payload))
+
+		attempt, err := q.CreateWebhookDeliveryAttempt(ctx, db.CreateWebhookDeliveryAttemptParams{OutboxID: due.ID, StatusCode: pgtype.Int4{Int32: 200, Valid: true}})
+		assert.NoError(t, err)
+		assert.Equal(t, due.ID, attempt.OutboxID)
+
+		delivered, err := q.DeliverWebhookOutboxEventById(ctx, due.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, db.WebhookDeliveryStatusTypeDELIVERED, delivered.Status)
+		assert.Equal(t, int32(1), delivered.Attempts)
+		assert.True(t, delivered.DeliveredAt.Valid)
+
+		// Rescheduled
+		due, err = q.FindDueWebhookOutboxEventAndLock(ctx)
+		assert.NoError(t, err)
+		assert.NotEqual(t, delivered.ID, due.ID)
+
+		var nextAttemptAt pgtype.Timestamptz
+		if err := nextAttemptAt.Scan(time.Now().UTC().Add(time.Hour)); err != nil {
+			log.Fatal(err)
+		}
+		rescheduled, err := q.RescheduleWebhookOutboxEventById(ctx, db.RescheduleWebhookOutboxEventByIdParams{ID: due.ID, NextAttemptAt: nextAttemptAt})
+		assert.NoError(t, err)
+		assert.Equal(t, db.WebhookDeliveryStatusTypePENDING, rescheduled.Status)
+		assert.Equal(t, int32(1), rescheduled.Attempts)
+
+		_, err = q.FindDueWebhookOutboxEventAndLock(ctx)
+		assert.True(t, errors.Is(err, pgx.ErrNoRows))
+
+		// Failed
+		failed, err := q.FailWebhookOutboxEventById(ctx, due.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, db.WebhookDeliveryStatusTypeFAILED, failed.Status)
+		assert.Equal(t, int32(2), failed.Attempts)
+
+		attempts, err := q.FindAllWebhookDeliveryAttemptsByOutboxId(ctx, delivered.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(attempts))
+	})
+}